@@ -0,0 +1,86 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/AnKlvy/news-service/internal/data/database"
+	"github.com/AnKlvy/news-service/internal/validator"
+)
+
+// bulkNewsHandler запускает массовое изменение статуса или категорий новостей.
+// С dry_run задание не создаётся, а в ответе возвращается количество новостей, которые
+// подходят под условие и которые будут изменены.
+func (app *application) bulkNewsHandler(w http.ResponseWriter, r *http.Request) {
+	var input struct {
+		database.BulkOperation
+		DryRun bool `json:"dry_run"`
+	}
+
+	err := app.readJSON(w, r, &input)
+	if err != nil {
+		app.badRequestResponse(w, r, err)
+		return
+	}
+
+	v := validator.New()
+	if database.ValidateBulkOperation(v, input.BulkOperation); !v.Valid() {
+		app.failedValidationResponse(w, r, v.Errors)
+		return
+	}
+
+	counts, err := app.models.News.BulkPreview(input.BulkOperation)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	if input.DryRun {
+		err = app.writeJSON(w, http.StatusOK, envelope{"preview": counts}, nil)
+		if err != nil {
+			app.serverErrorResponse(w, r, err)
+		}
+		return
+	}
+
+	job := &database.BulkJob{Operation: input.BulkOperation, Matched: counts.Matched}
+	err = app.models.BulkJobs.Insert(job)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	headers := make(http.Header)
+	headers.Set("Location", fmt.Sprintf("/v1/bulk-jobs/%d", job.ID))
+
+	err = app.writeJSON(w, http.StatusAccepted, envelope{"job": job, "preview": counts}, headers)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+// showBulkJobHandler возвращает состояние и прогресс массового задания.
+func (app *application) showBulkJobHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := app.readIDParam(r)
+	if err != nil {
+		app.notFoundResponse(w, r)
+		return
+	}
+
+	job, err := app.models.BulkJobs.Get(id)
+	if err != nil {
+		switch {
+		case errors.Is(err, database.ErrRecordNotFound):
+			app.notFoundResponse(w, r)
+		default:
+			app.serverErrorResponse(w, r, err)
+		}
+		return
+	}
+
+	err = app.writeJSON(w, http.StatusOK, envelope{"job": job}, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
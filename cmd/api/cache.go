@@ -0,0 +1,52 @@
+package main
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/AnKlvy/news-service/internal/data/database"
+	"github.com/AnKlvy/news-service/internal/events"
+	"github.com/AnKlvy/news-service/internal/jsonlog"
+)
+
+// invalidateCacheOnEvents удаляет из кэша новости, изменённые другими экземплярами
+// сервиса: их изменения приходят через LISTEN/NOTIFY в шину событий. Если подписка
+// не успевает за событиями, кэш очищается целиком, чтобы не отдавать устаревшие данные.
+func invalidateCacheOnEvents(bus *events.Bus, newsCache *database.CachedNewsModel, logger *jsonlog.Logger) {
+	for {
+		sub, err := bus.Subscribe(0, 256)
+		if err != nil {
+			return
+		}
+
+		for event := range sub.Events() {
+			newsCache.Invalidate(event.News.ID)
+		}
+
+		err = sub.Err()
+		if errors.Is(err, events.ErrBusClosed) {
+			return
+		}
+		logger.PrintError(err, map[string]string{"component": "cache"})
+		newsCache.Purge()
+	}
+}
+
+// showStatsHandler возвращает счётчики кэша новостей и состояние реплик базы данных.
+// Отключённый кэш и отсутствие реплик отображаются как null.
+func (app *application) showStatsHandler(w http.ResponseWriter, r *http.Request) {
+	var cacheStats *database.CacheStats
+	if app.newsCache != nil {
+		stats := app.newsCache.Stats()
+		cacheStats = &stats
+	}
+	var replicas map[string]bool
+	if app.replicas != nil {
+		replicas = app.replicas.Healthy()
+	}
+
+	err := app.writeJSON(w, http.StatusOK, envelope{"news_cache": cacheStats, "db_replicas": replicas}, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/AnKlvy/news-service/internal/data/database"
+)
+
+// versionETag возвращает сильный ETag записи. Версия увеличивается при каждом изменении,
+// поэтому пары из идентификатора и версии достаточно, чтобы различать представления.
+func versionETag(id int64, version int32) string {
+	return fmt.Sprintf(`"%d-%d"`, id, version)
+}
+
+func newsETag(news *database.News) string {
+	return versionETag(news.ID, news.Version)
+}
+
+// newsListETag возвращает ETag страницы списка новостей. Он меняется, если изменилась
+// любая новость на странице, её состав или общее количество записей.
+func newsListETag(news []*database.News, metadata database.Metadata) string {
+	h := sha256.New()
+	fmt.Fprintf(h, "%d/%d/%d;", metadata.CurrentPage, metadata.PageSize, metadata.TotalRecords)
+	for _, n := range news {
+		fmt.Fprintf(h, "%d-%d;", n.ID, n.Version)
+	}
+	return `"` + hex.EncodeToString(h.Sum(nil)[:16]) + `"`
+}
+
+// newsListLastModified возвращает время последнего изменения новостей на странице.
+func newsListLastModified(news []*database.News) time.Time {
+	var lastModified time.Time
+	for _, n := range news {
+		if n.UpdatedAt.After(lastModified) {
+			lastModified = n.UpdatedAt
+		}
+	}
+	return lastModified
+}
+
+// notModified добавляет в ответ заголовки ETag и Last-Modified и проверяет условия
+// If-None-Match и If-Modified-Since. Если представление у клиента актуально, отправляется
+// ответ 304 Not Modified и возвращается true. If-Modified-Since учитывается, только
+// если If-None-Match не передан.
+func (app *application) notModified(w http.ResponseWriter, r *http.Request, etag string, lastModified time.Time) bool {
+	w.Header().Set("ETag", etag)
+	if !lastModified.IsZero() {
+		w.Header().Set("Last-Modified", lastModified.UTC().Format(http.TimeFormat))
+	}
+
+	if inm := r.Header.Get("If-None-Match"); inm != "" {
+		if !etagMatches(inm, etag, false) {
+			return false
+		}
+	} else {
+		ims, err := http.ParseTime(r.Header.Get("If-Modified-Since"))
+		if err != nil || lastModified.IsZero() || lastModified.Truncate(time.Second).After(ims) {
+			return false
+		}
+	}
+
+	w.WriteHeader(http.StatusNotModified)
+	return true
+}
+
+// preconditionMet проверяет заголовок If-Match. Запрос без заголовка условий не имеет.
+// Изменение по устаревшему представлению должно завершиться ответом 412 Precondition Failed.
+func preconditionMet(r *http.Request, etag string) bool {
+	im := r.Header.Get("If-Match")
+	return im == "" || etagMatches(im, etag, true)
+}
+
+// etagMatches сравнивает ETag со списком из заголовка If-Match или If-None-Match.
+// При сильном сравнении слабые ETag (W/"...") не совпадают ни с чем.
+func etagMatches(header, etag string, strong bool) bool {
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" {
+			return true
+		}
+		if weak, ok := strings.CutPrefix(candidate, "W/"); ok {
+			if strong {
+				continue
+			}
+			candidate = weak
+		}
+		if candidate == etag {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,188 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/AnKlvy/news-service/internal/data/database"
+	"github.com/AnKlvy/news-service/internal/jsonlog"
+	"github.com/julienschmidt/httprouter"
+)
+
+func TestEtagMatches(t *testing.T) {
+	tests := []struct {
+		header string
+		strong bool
+		want   bool
+	}{
+		{`"1-2"`, true, true},
+		{`"1-3"`, true, false},
+		{`"1-3", "1-2"`, true, true},
+		{`*`, true, true},
+		{`W/"1-2"`, true, false},
+		{`W/"1-2"`, false, true},
+		{`"1-2"`, false, true},
+		{`1-2`, false, false},
+	}
+
+	for _, tt := range tests {
+		if got := etagMatches(tt.header, `"1-2"`, tt.strong); got != tt.want {
+			t.Errorf("etagMatches(%s, strong=%t) = %t, want %t", tt.header, tt.strong, got, tt.want)
+		}
+	}
+}
+
+func TestNewsListETagChangesWithVersion(t *testing.T) {
+	metadata := database.Metadata{CurrentPage: 1, PageSize: 20, TotalRecords: 1}
+	before := newsListETag([]*database.News{{ID: 1, Version: 1}}, metadata)
+	after := newsListETag([]*database.News{{ID: 1, Version: 2}}, metadata)
+	if before == after {
+		t.Fatalf("list ETag %s did not change after the news version changed", before)
+	}
+	if again := newsListETag([]*database.News{{ID: 1, Version: 1}}, metadata); again != before {
+		t.Fatalf("list ETag is not stable: %s != %s", again, before)
+	}
+}
+
+// conditionalNewsStore возвращает одну и ту же новость и запоминает вызовы изменения.
+type conditionalNewsStore struct {
+	database.MockNewsModel
+	news    database.News
+	updated bool
+	deleted int32
+}
+
+func (m *conditionalNewsStore) Get(id int64) (*database.News, error) {
+	news := m.news
+	return &news, nil
+}
+
+func (m *conditionalNewsStore) Update(news *database.News) error {
+	m.updated = true
+	news.Version++
+	return nil
+}
+
+func (m *conditionalNewsStore) Delete(id int64, version int32) error {
+	m.deleted = version
+	return nil
+}
+
+func newConditionalTestApp() (*application, *conditionalNewsStore) {
+	store := &conditionalNewsStore{news: database.News{
+		ID:            1,
+		Title:         "Title",
+		Content:       "Content",
+		ContentFormat: "plain",
+		Categories:    []string{"world"},
+		Status:        "DRAFT",
+		Author:        "Author",
+		UpdatedAt:     time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC),
+		Version:       3,
+	}}
+	models := database.NewMockModels()
+	models.News = store
+	return &application{
+		logger: jsonlog.New(&strings.Builder{}, jsonlog.LevelOff),
+		models: models,
+	}, store
+}
+
+func newsRequest(method, body string, header map[string]string) *http.Request {
+	r := httptest.NewRequest(method, "/v1/news/1", strings.NewReader(body))
+	for k, v := range header {
+		r.Header.Set(k, v)
+	}
+	params := httprouter.Params{{Key: "id", Value: "1"}}
+	return r.WithContext(context.WithValue(r.Context(), httprouter.ParamsKey, params))
+}
+
+func TestShowNewsConditional(t *testing.T) {
+	tests := []struct {
+		name   string
+		header map[string]string
+		want   int
+	}{
+		{"no conditions", nil, http.StatusOK},
+		{"matching If-None-Match", map[string]string{"If-None-Match": `"1-3"`}, http.StatusNotModified},
+		{"weak If-None-Match", map[string]string{"If-None-Match": `W/"1-3"`}, http.StatusNotModified},
+		{"stale If-None-Match", map[string]string{"If-None-Match": `"1-2"`}, http.StatusOK},
+		{"fresh If-Modified-Since", map[string]string{"If-Modified-Since": "Wed, 01 May 2024 12:00:00 GMT"}, http.StatusNotModified},
+		{"old If-Modified-Since", map[string]string{"If-Modified-Since": "Wed, 01 May 2024 11:59:59 GMT"}, http.StatusOK},
+		{
+			"If-None-Match wins over If-Modified-Since",
+			map[string]string{"If-None-Match": `"1-2"`, "If-Modified-Since": "Wed, 01 May 2024 12:00:00 GMT"},
+			http.StatusOK,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app, _ := newConditionalTestApp()
+			rr := httptest.NewRecorder()
+			app.showNewsHandler(rr, newsRequest(http.MethodGet, "", tt.header))
+
+			if rr.Code != tt.want {
+				t.Fatalf("status = %d, want %d", rr.Code, tt.want)
+			}
+			if got := rr.Header().Get("ETag"); got != `"1-3"` {
+				t.Errorf("ETag = %s, want %s", got, `"1-3"`)
+			}
+		})
+	}
+}
+
+func TestUpdateNewsIfMatch(t *testing.T) {
+	tests := []struct {
+		name    string
+		ifMatch string
+		want    int
+	}{
+		{"no If-Match", "", http.StatusOK},
+		{"current version", `"1-3"`, http.StatusOK},
+		{"stale version", `"1-2"`, http.StatusPreconditionFailed},
+		{"weak ETag", `W/"1-3"`, http.StatusPreconditionFailed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app, store := newConditionalTestApp()
+			header := map[string]string{}
+			if tt.ifMatch != "" {
+				header["If-Match"] = tt.ifMatch
+			}
+
+			rr := httptest.NewRecorder()
+			app.updateNewsHandler(rr, newsRequest(http.MethodPatch, `{"title": "New title"}`, header))
+
+			if rr.Code != tt.want {
+				t.Fatalf("status = %d, want %d: %s", rr.Code, tt.want, rr.Body)
+			}
+			if store.updated != (tt.want == http.StatusOK) {
+				t.Fatalf("updated = %t, want %t", store.updated, tt.want == http.StatusOK)
+			}
+			if tt.want == http.StatusOK && rr.Header().Get("ETag") != `"1-4"` {
+				t.Errorf("ETag = %s, want the new version %s", rr.Header().Get("ETag"), `"1-4"`)
+			}
+		})
+	}
+}
+
+func TestDeleteNewsIfMatch(t *testing.T) {
+	app, store := newConditionalTestApp()
+	rr := httptest.NewRecorder()
+	app.deleteNewsHandler(rr, newsRequest(http.MethodDelete, "", map[string]string{"If-Match": `"1-2"`}))
+	if rr.Code != http.StatusPreconditionFailed {
+		t.Fatalf("stale If-Match status = %d, want %d", rr.Code, http.StatusPreconditionFailed)
+	}
+
+	rr = httptest.NewRecorder()
+	app.deleteNewsHandler(rr, newsRequest(http.MethodDelete, "", map[string]string{"If-Match": `"1-3"`}))
+	if rr.Code != http.StatusOK || store.deleted != 3 {
+		t.Fatalf("status = %d, deleted version = %d, want %d and version 3", rr.Code, store.deleted, http.StatusOK)
+	}
+}
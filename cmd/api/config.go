@@ -0,0 +1,442 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/AnKlvy/news-service/internal/certs"
+	"github.com/AnKlvy/news-service/internal/data/database"
+	"github.com/AnKlvy/news-service/internal/jsonlog"
+	"github.com/AnKlvy/news-service/internal/validator"
+	"github.com/joho/godotenv"
+	"gopkg.in/yaml.v3"
+)
+
+// Переменные окружения с этим префиксом переопределяют настройки из файла: например,
+// NEWS_SERVICE_DB_DSN задаёт -db-dsn, а NEWS_SERVICE_CONFIG — путь к файлу настроек.
+const envPrefix = "NEWS_SERVICE_"
+
+// loadConfig собирает настройки из нескольких источников. Каждый следующий источник
+// переопределяет предыдущий: значения по умолчанию, файл настроек (YAML или JSON),
+// переменные окружения с префиксом NEWS_SERVICE_ и флаги командной строки. Файл .env,
+// если он есть, дополняет переменные окружения. Возвращает аргументы, оставшиеся
+// после флагов (подкоманду).
+func loadConfig(args []string) (config, []string, error) {
+	var cfg config
+	var configFile string
+
+	fs := newFlagSet(&cfg)
+	fs.StringVar(&configFile, "config", "", "Path to a YAML or JSON configuration file")
+	if err := fs.Parse(args); err != nil {
+		return config{}, nil, err
+	}
+
+	// Флаги, явно заданные в командной строке, имеют наивысший приоритет.
+	explicit := map[string]bool{}
+	fs.Visit(func(f *flag.Flag) { explicit[f.Name] = true })
+
+	if err := godotenv.Load(); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return config{}, nil, fmt.Errorf(".env: %w", err)
+	}
+
+	if !explicit["config"] {
+		configFile = os.Getenv(envPrefix + "CONFIG")
+	}
+	if configFile != "" {
+		values, err := readConfigFile(configFile)
+		if err != nil {
+			return config{}, nil, err
+		}
+		for _, name := range sortedKeys(values) {
+			if name == "config" || fs.Lookup(name) == nil {
+				return config{}, nil, fmt.Errorf("%s: unknown setting %q", configFile, name)
+			}
+			if explicit[name] {
+				continue
+			}
+			if err := fs.Set(name, values[name]); err != nil {
+				return config{}, nil, fmt.Errorf("%s: invalid value %q for %s: %w", configFile, values[name], name, err)
+			}
+		}
+	}
+
+	var envErr error
+	fs.VisitAll(func(f *flag.Flag) {
+		value, ok := os.LookupEnv(envName(f.Name))
+		if !ok || explicit[f.Name] || f.Name == "config" || envErr != nil {
+			return
+		}
+		if err := fs.Set(f.Name, value); err != nil {
+			envErr = fmt.Errorf("invalid value %q for %s: %w", value, envName(f.Name), err)
+		}
+	})
+	if envErr != nil {
+		return config{}, nil, envErr
+	}
+
+	// Если адрес загруженных файлов не задан явно, строим его от базового адреса сайта.
+	if cfg.media.baseURL == "" {
+		baseURL := strings.TrimSuffix(cfg.baseURL, "/")
+		if baseURL == "" {
+			scheme := "http"
+			if cfg.tls.certFile != "" {
+				scheme = "https"
+			}
+			baseURL = fmt.Sprintf("%s://localhost:%d", scheme, cfg.port)
+		}
+		cfg.media.baseURL = baseURL + "/media"
+	}
+
+	// Собственный адрес загруженных файлов всегда должен проходить проверку хостов изображений.
+	if len(cfg.imageAllowedHosts) > 0 {
+		if u, err := url.Parse(cfg.media.baseURL); err == nil && u.Hostname() != "" {
+			cfg.imageAllowedHosts = append(cfg.imageAllowedHosts, u.Hostname())
+		}
+	}
+
+	if err := cfg.validate(); err != nil {
+		return config{}, nil, err
+	}
+	return cfg, fs.Args(), nil
+}
+
+// newFlagSet описывает все настройки сервиса. Имена флагов используются и как ключи
+// файла настроек, и для построения имён переменных окружения.
+func newFlagSet(cfg *config) *flag.FlagSet {
+	fs := flag.NewFlagSet("api", flag.ContinueOnError)
+
+	fs.IntVar(&cfg.port, "port", 4000, "API server port")
+	fs.StringVar(&cfg.grpcAddr, "grpc-addr", ":9000", "gRPC server listen address")
+	fs.StringVar(&cfg.env, "env", "development", "Environment (development|staging|production)")
+	fs.StringVar(&cfg.baseURL, "base-url", "", "Public base URL used for links in feeds and sitemaps (defaults to the request host)")
+	fs.StringVar(&cfg.db.dsn, "db-dsn", "", "PostgreSQL DSN")
+	fs.IntVar(&cfg.db.maxOpenConns, "db-max-open-conns", 25, "PostgreSQL max open connections")
+	fs.IntVar(&cfg.db.maxIdleConns, "db-max-idle-conns", 25, "PostgreSQL max idle connections")
+	fs.StringVar(&cfg.db.maxIdleTime, "db-max-idle-time", "15m", "PostgreSQL max connection idle time")
+	fs.Var((*stringList)(&cfg.db.replicaDSNs), "db-replica-dsns", "Comma-separated PostgreSQL DSNs of read replicas")
+	fs.IntVar(&cfg.db.replicas.maxOpenConns, "db-replica-max-open-conns", 25, "PostgreSQL max open connections per read replica")
+	fs.IntVar(&cfg.db.replicas.maxIdleConns, "db-replica-max-idle-conns", 25, "PostgreSQL max idle connections per read replica")
+	fs.StringVar(&cfg.db.replicas.maxIdleTime, "db-replica-max-idle-time", "15m", "PostgreSQL max connection idle time on read replicas")
+	fs.DurationVar(&cfg.db.replicaCheckInterval, "db-replica-check-interval", 5*time.Second, "How often read replicas are health checked")
+	// Создаем флаги командной строки для чтения значений настроек в структуру config.
+	// Обратите внимание, что по умолчанию для параметра 'enabled' установлено значение true.
+	fs.Float64Var(&cfg.limiter.rps, "limiter-rps", 2, "Rate limiter maximum requests per second")
+	fs.IntVar(&cfg.limiter.burst, "limiter-burst", 4, "Rate limiter maximum burst")
+	fs.BoolVar(&cfg.limiter.enabled, "limiter-enabled", true, "Enable rate limiter")
+	fs.IntVar(&cfg.excerptLength, "excerpt-length", 200, "Maximum length of generated article excerpts (characters)")
+	fs.Var((*stringList)(&cfg.imageAllowedHosts), "image-allowed-hosts", "Comma-separated list of hosts images may be served from (e.g. cdn.example.com,*.example.org)")
+	fs.StringVar(&cfg.feed.title, "feed-title", "News Service", "Title of the RSS/Atom feeds")
+	fs.IntVar(&cfg.feed.limit, "feed-limit", 50, "Number of articles in the RSS/Atom feeds")
+	fs.DurationVar(&cfg.feed.cacheTTL, "feed-cache-ttl", time.Minute, "How long generated feeds and sitemaps are served from memory")
+	fs.IntVar(&cfg.feed.cacheSize, "feed-cache-size", 1000, "Maximum number of generated feeds and sitemaps kept in memory")
+	fs.StringVar(&cfg.media.dir, "media-dir", "./uploads", "Directory for uploaded media files")
+	fs.Int64Var(&cfg.media.maxSize, "media-max-size", 10<<20, "Maximum size of an uploaded media file in bytes")
+	fs.StringVar(&cfg.media.baseURL, "media-base-url", "", "Public URL prefix of uploaded media (defaults to <base-url>/media; must not point to localhost outside development)")
+	cfg.media.thumbnailWidths = []int{320, 640, 1024}
+	fs.Var((*intList)(&cfg.media.thumbnailWidths), "media-thumbnail-widths", "Comma-separated widths of generated thumbnails in pixels")
+	fs.IntVar(&cfg.media.thumbnailWorkers, "media-thumbnail-workers", 2, "Number of background thumbnail workers")
+	fs.StringVar(&cfg.sitemap.publicationName, "sitemap-publication-name", "News Service", "Publication name in the Google News sitemap")
+	fs.StringVar(&cfg.sitemap.language, "sitemap-language", "ru", "Publication language (ISO 639) in the Google News sitemap")
+	fs.IntVar(&cfg.eventsHistory, "events-history", 1000, "Number of recent news events kept for resuming event streams")
+	fs.DurationVar(&cfg.sse.heartbeat, "sse-heartbeat", 15*time.Second, "Interval between heartbeat comments on Server-Sent Events streams")
+	fs.DurationVar(&cfg.sse.retry, "sse-retry", 3*time.Second, "Reconnection delay suggested to Server-Sent Events clients")
+	fs.IntVar(&cfg.batch.maxCreate, "batch-max-create", 100, "Maximum number of articles in a batch create request")
+	fs.IntVar(&cfg.batch.maxGet, "batch-max-get", 100, "Maximum number of ids in a batch get request")
+	fs.IntVar(&cfg.bulk.chunkSize, "bulk-chunk-size", 500, "Number of articles updated per transaction by bulk jobs")
+	fs.DurationVar(&cfg.bulk.pollInterval, "bulk-poll-interval", 2*time.Second, "How often new bulk jobs are picked up")
+	fs.IntVar(&cfg.webhooks.workers, "webhook-workers", 2, "Number of webhook delivery workers")
+	fs.DurationVar(&cfg.webhooks.pollInterval, "webhook-poll-interval", 2*time.Second, "How often the webhook outbox is polled when idle")
+	fs.DurationVar(&cfg.webhooks.timeout, "webhook-timeout", 10*time.Second, "Timeout of a single webhook request")
+	fs.IntVar(&cfg.webhooks.maxAttempts, "webhook-max-attempts", 8, "Delivery attempts before a webhook delivery is marked dead")
+	fs.DurationVar(&cfg.webhooks.backoffBase, "webhook-backoff-base", 30*time.Second, "Delay before the first webhook retry, doubled after each failure")
+	fs.DurationVar(&cfg.webhooks.backoffMax, "webhook-backoff-max", time.Hour, "Maximum delay between webhook retries")
+	fs.BoolVar(&cfg.webhooks.allowPrivateTargets, "webhook-allow-private-targets", false, "Allow webhook deliveries to loopback, private and link-local addresses (development only)")
+	fs.DurationVar(&cfg.idempotency.ttl, "idempotency-ttl", 24*time.Hour, "How long idempotency keys of create requests are kept")
+	fs.DurationVar(&cfg.idempotency.cleanupInterval, "idempotency-cleanup-interval", time.Hour, "How often expired idempotency keys are deleted")
+	fs.IntVar(&cfg.cache.size, "cache-size", 10000, "Maximum number of articles kept in the in-process cache (0 disables the cache)")
+	fs.DurationVar(&cfg.cache.ttl, "cache-ttl", 30*time.Second, "How long a cached article is served without reading the database")
+	fs.IntVar(&cfg.cache.listSize, "cache-list-size", 1000, "Maximum number of cached article list pages")
+	fs.DurationVar(&cfg.cache.listTTL, "cache-list-ttl", 0, "How long article list pages are cached (0 disables list caching)")
+	fs.StringVar(&cfg.logLevel, "log-level", "INFO", "Minimum log level (DEBUG|INFO|WARN|ERROR|FATAL|OFF)")
+	fs.Var((*stringList)(&cfg.logComponentLevels), "log-component-levels", "Comma-separated per-component log levels overriding -log-level (e.g. cache=DEBUG,webhooks=WARN)")
+	fs.Var((*stringList)(&cfg.newsSortFields), "news-sort-fields", "Comma-separated sort values allowed for news lists (defaults to all supported values)")
+	cfg.features = defaultFeatures()
+	fs.Var(&cfg.features, "features", "Comma-separated feature switches such as feeds=false,sitemaps=true")
+	fs.DurationVar(&cfg.shutdownTimeout, "shutdown-timeout", 30*time.Second, "How long in-flight requests and background jobs may take to finish on shutdown")
+	fs.StringVar(&cfg.tls.certFile, "tls-cert-file", "", "PEM certificate of the HTTP and gRPC servers (enables TLS)")
+	fs.StringVar(&cfg.tls.keyFile, "tls-key-file", "", "PEM private key of the server certificate")
+	fs.StringVar(&cfg.tls.clientCAFile, "tls-client-ca-file", "", "PEM bundle of CAs that sign client certificates (enables mutual TLS)")
+	fs.StringVar(&cfg.tls.minVersion, "tls-min-version", "1.2", "Minimum TLS version (1.2|1.3)")
+	fs.DurationVar(&cfg.tls.reloadInterval, "tls-reload-interval", 30*time.Second, "How often certificate files are checked for changes")
+	fs.Var((*stringList)(&cfg.tls.allowedClients), "tls-allowed-clients", "Comma-separated client certificate common names or subjects allowed to call the API (defaults to any client with a valid certificate)")
+	fs.Var((*stringList)(&cfg.tls.adminClients), "tls-admin-clients", "Comma-separated client certificate common names or subjects allowed to call admin and webhook endpoints (admin endpoints are disabled when empty)")
+	fs.BoolVar(&cfg.migrateOnStart, "migrate-on-start", false, "Apply pending database migrations before serving")
+
+	return fs
+}
+
+// validate проверяет настройки до запуска сервиса и перечисляет все ошибки сразу.
+func (cfg config) validate() error {
+	v := validator.New()
+
+	v.Check(cfg.port > 0 && cfg.port <= 65535, "port", "must be between 1 and 65535")
+	v.Check(cfg.grpcAddr != "", "grpc-addr", "must be provided")
+	v.Check(validator.PermittedValue(cfg.env, "development", "staging", "production"), "env", "must be development, staging or production")
+	v.Check(cfg.baseURL == "" || isHTTPURL(cfg.baseURL), "base-url", "must be an absolute http or https URL")
+	v.Check(cfg.db.dsn != "", "db-dsn", "must be provided")
+	v.Check(isDuration(cfg.db.maxIdleTime), "db-max-idle-time", "must be a duration such as 15m")
+	v.Check(isDuration(cfg.db.replicas.maxIdleTime), "db-replica-max-idle-time", "must be a duration such as 15m")
+	v.Check(len(cfg.db.replicaDSNs) == 0 || cfg.db.replicaCheckInterval > 0, "db-replica-check-interval", "must be positive")
+	v.Check(!cfg.limiter.enabled || cfg.limiter.rps > 0, "limiter-rps", "must be positive")
+	v.Check(!cfg.limiter.enabled || cfg.limiter.burst > 0, "limiter-burst", "must be positive")
+	v.Check(cfg.excerptLength > 0, "excerpt-length", "must be positive")
+	v.Check(cfg.feed.limit > 0, "feed-limit", "must be positive")
+	v.Check(cfg.feed.cacheTTL >= 0, "feed-cache-ttl", "must not be negative")
+	v.Check(cfg.feed.cacheSize > 0, "feed-cache-size", "must be positive")
+	v.Check(cfg.media.dir != "", "media-dir", "must be provided")
+	v.Check(cfg.media.maxSize > 0, "media-max-size", "must be positive")
+	v.Check(isHTTPURL(cfg.media.baseURL), "media-base-url", "must be an absolute http or https URL")
+	v.Check(cfg.env == "development" || !isLoopbackURL(cfg.media.baseURL), "media-base-url", "must be a public URL outside development, set media-base-url or base-url")
+	v.Check(cfg.media.thumbnailWorkers > 0, "media-thumbnail-workers", "must be positive")
+	v.Check(cfg.sitemap.language != "", "sitemap-language", "must be provided")
+	v.Check(cfg.eventsHistory >= 0, "events-history", "must not be negative")
+	v.Check(cfg.sse.heartbeat > 0, "sse-heartbeat", "must be positive")
+	v.Check(cfg.sse.retry > 0, "sse-retry", "must be positive")
+	v.Check(cfg.batch.maxCreate > 0, "batch-max-create", "must be positive")
+	v.Check(cfg.batch.maxGet > 0, "batch-max-get", "must be positive")
+	v.Check(cfg.bulk.chunkSize > 0, "bulk-chunk-size", "must be positive")
+	v.Check(cfg.bulk.pollInterval > 0, "bulk-poll-interval", "must be positive")
+	v.Check(cfg.webhooks.workers > 0, "webhook-workers", "must be positive")
+	v.Check(cfg.webhooks.pollInterval > 0, "webhook-poll-interval", "must be positive")
+	v.Check(cfg.webhooks.timeout > 0, "webhook-timeout", "must be positive")
+	v.Check(cfg.webhooks.maxAttempts > 0, "webhook-max-attempts", "must be positive")
+	v.Check(cfg.webhooks.backoffBase > 0, "webhook-backoff-base", "must be positive")
+	v.Check(cfg.webhooks.backoffMax >= cfg.webhooks.backoffBase, "webhook-backoff-max", "must not be less than webhook-backoff-base")
+	v.Check(cfg.idempotency.ttl > 0, "idempotency-ttl", "must be positive")
+	v.Check(cfg.idempotency.cleanupInterval > 0, "idempotency-cleanup-interval", "must be positive")
+	v.Check(cfg.cache.size >= 0, "cache-size", "must not be negative")
+	v.Check(cfg.cache.size == 0 || cfg.cache.ttl > 0, "cache-ttl", "must be positive when the cache is enabled")
+	v.Check(cfg.cache.listTTL >= 0, "cache-list-ttl", "must not be negative")
+	v.Check(cfg.cache.listTTL == 0 || cfg.cache.listSize > 0, "cache-list-size", "must be positive when list caching is enabled")
+	v.Check((cfg.tls.certFile == "") == (cfg.tls.keyFile == ""), "tls-key-file", "must be provided together with tls-cert-file")
+	v.Check(cfg.tls.clientCAFile == "" || cfg.tls.certFile != "", "tls-client-ca-file", "requires tls-cert-file")
+	v.Check(len(cfg.tls.allowedClients) == 0 || cfg.tls.clientCAFile != "", "tls-allowed-clients", "requires tls-client-ca-file")
+	v.Check(len(cfg.tls.adminClients) == 0 || cfg.tls.clientCAFile != "", "tls-admin-clients", "requires tls-client-ca-file")
+	_, err := certs.ParseVersion(cfg.tls.minVersion)
+	v.Check(err == nil, "tls-min-version", "must be 1.2 or 1.3")
+	v.Check(cfg.tls.certFile == "" || cfg.tls.reloadInterval > 0, "tls-reload-interval", "must be positive")
+	v.Check(cfg.shutdownTimeout > 0, "shutdown-timeout", "must be positive")
+	_, err = jsonlog.ParseLevel(cfg.logLevel)
+	v.Check(err == nil, "log-level", "must be DEBUG, INFO, WARN, ERROR, FATAL or OFF")
+	_, err = parseComponentLevels(cfg.logComponentLevels)
+	v.Check(err == nil, "log-component-levels", "must be a list of component=LEVEL pairs")
+	for _, field := range cfg.newsSortFields {
+		v.Check(validator.PermittedValue(field, database.NewsSortSafelist...), "news-sort-fields", fmt.Sprintf("unsupported sort value %q", field))
+	}
+	v.Check(len(cfg.newsSortFields) == 0 || slices.Contains(cfg.newsSortFields, "id"), "news-sort-fields", "must include id, the default sort")
+
+	if v.Valid() {
+		return nil
+	}
+
+	problems := make([]string, 0, len(v.Errors))
+	for _, name := range sortedKeys(v.Errors) {
+		problems = append(problems, fmt.Sprintf("  %s: %s", name, v.Errors[name]))
+	}
+	return fmt.Errorf("invalid configuration:\n%s", strings.Join(problems, "\n"))
+}
+
+// printConfig выводит действующие настройки в формате файла настроек. Пароли
+// в строках подключения к базе данных скрываются.
+func printConfig(w io.Writer, cfg config) {
+	values := configValues(cfg)
+	for _, name := range sortedKeys(values) {
+		value := values[name]
+		if strings.Contains(name, "dsn") {
+			value = maskDSNs(value)
+		}
+		fmt.Fprintf(w, "%s: %s\n", name, value)
+	}
+}
+
+// configValues возвращает значения настроек в виде строк, ключами служат имена флагов.
+func configValues(cfg config) map[string]string {
+	// Флаги набора привязаны к копии cfg, поэтому после их создания возвращаем
+	// в неё действующие значения вместо значений по умолчанию.
+	var bound config
+	fs := newFlagSet(&bound)
+	bound = cfg
+
+	values := map[string]string{}
+	fs.VisitAll(func(f *flag.Flag) { values[f.Name] = f.Value.String() })
+	return values
+}
+
+var dsnPassword = regexp.MustCompile(`(password\s*=\s*)('[^']*'|\S+)`)
+
+// maskDSNs скрывает пароли в списке строк подключения через запятую. Поддерживаются
+// обе формы DSN: URL и набор пар ключ=значение.
+func maskDSNs(value string) string {
+	dsns := strings.Split(value, ",")
+	for i, dsn := range dsns {
+		if u, err := url.Parse(dsn); err == nil && u.User != nil {
+			if _, ok := u.User.Password(); ok {
+				u.User = url.UserPassword(u.User.Username(), "xxxxx")
+				dsns[i] = u.String()
+				continue
+			}
+		}
+		dsns[i] = dsnPassword.ReplaceAllString(dsn, "${1}xxxxx")
+	}
+	return strings.Join(dsns, ",")
+}
+
+// readConfigFile читает файл настроек. Вложенные разделы разворачиваются в имена флагов
+// через дефис (db: {dsn: ...} превращается в db-dsn), подчёркивания в ключах допускаются
+// вместо дефисов, а списки записываются через запятую.
+func readConfigFile(path string) (map[string]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var raw map[string]any
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".json":
+		err = json.Unmarshal(data, &raw)
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, &raw)
+	default:
+		return nil, fmt.Errorf("%s: unsupported configuration file format, use .yaml, .yml or .json", path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+
+	values := map[string]string{}
+	if err := flatten(values, "", raw); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return values, nil
+}
+
+func flatten(dst map[string]string, prefix string, src map[string]any) error {
+	for key, value := range src {
+		name := strings.ReplaceAll(strings.ToLower(key), "_", "-")
+		if prefix != "" {
+			name = prefix + "-" + name
+		}
+
+		switch value := value.(type) {
+		case map[string]any:
+			if err := flatten(dst, name, value); err != nil {
+				return err
+			}
+		case []any:
+			items := make([]string, len(value))
+			for i, item := range value {
+				items[i] = fmt.Sprint(item)
+			}
+			dst[name] = strings.Join(items, ",")
+		case nil:
+			return fmt.Errorf("setting %q has no value", name)
+		case float64:
+			// JSON не различает целые и дробные числа.
+			dst[name] = strconv.FormatFloat(value, 'f', -1, 64)
+		default:
+			dst[name] = fmt.Sprint(value)
+		}
+	}
+	return nil
+}
+
+func envName(flagName string) string {
+	return envPrefix + strings.ToUpper(strings.ReplaceAll(flagName, "-", "_"))
+}
+
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+func isHTTPURL(s string) bool {
+	u, err := url.Parse(s)
+	return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+}
+
+// isLoopbackURL сообщает, указывает ли адрес на localhost или loopback-адрес: такие
+// ссылки на загруженные файлы недоступны клиентам вне сервера.
+func isLoopbackURL(s string) bool {
+	u, err := url.Parse(s)
+	if err != nil {
+		return false
+	}
+	host := strings.ToLower(u.Hostname())
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+func isDuration(s string) bool {
+	_, err := time.ParseDuration(s)
+	return err == nil
+}
+
+// stringList — значение флага со списком строк через запятую.
+type stringList []string
+
+func (l *stringList) String() string {
+	return strings.Join(*l, ",")
+}
+
+func (l *stringList) Set(val string) error {
+	*l = nil
+	for _, s := range strings.Split(val, ",") {
+		if s = strings.TrimSpace(s); s != "" {
+			*l = append(*l, s)
+		}
+	}
+	return nil
+}
+
+// intList — значение флага со списком положительных целых чисел через запятую.
+type intList []int
+
+func (l *intList) String() string {
+	items := make([]string, len(*l))
+	for i, n := range *l {
+		items[i] = strconv.Itoa(n)
+	}
+	return strings.Join(items, ",")
+}
+
+func (l *intList) Set(val string) error {
+	list := []int{}
+	for _, s := range strings.Split(val, ",") {
+		n, err := strconv.Atoi(strings.TrimSpace(s))
+		if err != nil || n <= 0 {
+			return fmt.Errorf("invalid positive number %q", s)
+		}
+		list = append(list, n)
+	}
+	*l = list
+	return nil
+}
@@ -0,0 +1,183 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeConfigFile(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadConfigPrecedence(t *testing.T) {
+	path := writeConfigFile(t, "config.yaml", `
+port: 5000
+feed:
+  title: From file
+  limit: 20
+limiter:
+  rps: 5
+db:
+  dsn: postgres://file@db/news
+`)
+	t.Setenv(envPrefix+"CONFIG", path)
+	t.Setenv(envPrefix+"PORT", "6000")
+	t.Setenv(envPrefix+"FEED_TITLE", "From env")
+
+	cfg, args, err := loadConfig([]string{"-port", "7000", "config", "print"})
+	if err != nil {
+		t.Fatalf("loadConfig() error = %v", err)
+	}
+
+	tests := []struct {
+		name string
+		got  any
+		want any
+	}{
+		{"flag over env and file", cfg.port, 7000},
+		{"env over file", cfg.feed.title, "From env"},
+		{"file over default", cfg.feed.limit, 20},
+		{"file over default", cfg.limiter.rps, 5.0},
+		{"default", cfg.limiter.burst, 4},
+		{"default", cfg.grpcAddr, ":9000"},
+	}
+	for _, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("%s: got %v, want %v", tt.name, tt.got, tt.want)
+		}
+	}
+	if strings.Join(args, " ") != "config print" {
+		t.Errorf("args = %q, want the subcommand", args)
+	}
+	if cfg.media.baseURL != "http://localhost:7000/media" {
+		t.Errorf("media base URL = %q, want it derived from the port", cfg.media.baseURL)
+	}
+}
+
+func TestLoadConfigFlagOverridesConfigFileEnv(t *testing.T) {
+	t.Setenv(envPrefix+"CONFIG", writeConfigFile(t, "env.json", `{"port": 5000}`))
+	path := writeConfigFile(t, "flag.json", `{"port": 6000, "db": {"dsn": "postgres://db/news"}}`)
+
+	cfg, _, err := loadConfig([]string{"-config", path})
+	if err != nil {
+		t.Fatalf("loadConfig() error = %v", err)
+	}
+	if cfg.port != 6000 {
+		t.Fatalf("port = %d, want 6000 from the -config file", cfg.port)
+	}
+}
+
+func TestLoadConfigErrors(t *testing.T) {
+	tests := []struct {
+		name string
+		file string
+		env  map[string]string
+		want string
+	}{
+		{
+			name: "unknown setting",
+			file: "db_dsn: postgres://db/news\nunknown: 1\n",
+			want: `unknown setting "unknown"`,
+		},
+		{
+			name: "invalid env value",
+			file: "db_dsn: postgres://db/news\n",
+			env:  map[string]string{envPrefix + "PORT": "abc"},
+			want: envPrefix + "PORT",
+		},
+		{
+			name: "validation",
+			file: "port: 0\n",
+			want: "db-dsn: must be provided",
+		},
+		{
+			name: "localhost media in production",
+			file: "env: production\ndb_dsn: postgres://db/news\n",
+			want: "media-base-url: must be a public URL",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Setenv(envPrefix+"CONFIG", writeConfigFile(t, "config.yaml", tt.file))
+			for k, v := range tt.env {
+				t.Setenv(k, v)
+			}
+
+			_, _, err := loadConfig(nil)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("loadConfig() error = %v, want it to mention %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadConfigMediaBaseURLOutsideDevelopment(t *testing.T) {
+	tests := []struct {
+		name string
+		file string
+		want string
+	}{
+		{"derived from base-url", "base_url: https://news.example.com/\n", "https://news.example.com/media"},
+		{"explicit", "media:\n  base_url: https://cdn.example.com/media\n", "https://cdn.example.com/media"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			file := "env: production\ndb_dsn: postgres://db/news\n" + tt.file
+			t.Setenv(envPrefix+"CONFIG", writeConfigFile(t, "config.yaml", file))
+
+			cfg, _, err := loadConfig(nil)
+			if err != nil {
+				t.Fatalf("loadConfig() error = %v", err)
+			}
+			if cfg.media.baseURL != tt.want {
+				t.Fatalf("media base URL = %q, want %q", cfg.media.baseURL, tt.want)
+			}
+		})
+	}
+}
+
+func TestIsLoopbackURL(t *testing.T) {
+	tests := []struct {
+		url  string
+		want bool
+	}{
+		{"http://localhost:4000/media", true},
+		{"http://api.localhost/media", true},
+		{"http://127.0.0.1:4000/media", true},
+		{"http://[::1]:4000/media", true},
+		{"https://news.example.com/media", false},
+		{"http://10.0.0.5/media", false},
+	}
+
+	for _, tt := range tests {
+		if got := isLoopbackURL(tt.url); got != tt.want {
+			t.Errorf("isLoopbackURL(%q) = %t, want %t", tt.url, got, tt.want)
+		}
+	}
+}
+
+func TestPrintConfigMasksPasswords(t *testing.T) {
+	var cfg config
+	newFlagSet(&cfg)
+	cfg.db.dsn = "postgres://news:secret@db/news"
+	cfg.db.replicaDSNs = []string{"host=replica user=news password='s3 cret'"}
+
+	var out strings.Builder
+	printConfig(&out, cfg)
+
+	if strings.Contains(out.String(), "secret") || strings.Contains(out.String(), "s3 cret") {
+		t.Fatalf("printConfig() leaks a password:\n%s", out.String())
+	}
+	if !strings.Contains(out.String(), "db-dsn: postgres://news:xxxxx@db/news") {
+		t.Fatalf("printConfig() does not show the masked DSN:\n%s", out.String())
+	}
+}
@@ -3,15 +3,21 @@ package main
 import (
 	"fmt"
 	"net/http"
+
+	"github.com/AnKlvy/news-service/internal/certs"
 )
 
 func (app *application) logError(r *http.Request, err error) {
 	// Use the PrintError() method to log the error message, and include the current
 	// request method and URL as properties in the log entry.
-	app.logger.PrintError(err, map[string]string{
+	properties := map[string]string{
 	"request_method": r.Method,
 	"request_url": r.URL.String(),
-	})
+	}
+	if caller := certs.FromContext(r.Context()); caller != "" {
+	properties["caller"] = caller
+	}
+	app.logger.PrintError(err, properties)
 	}
 	
 
@@ -76,4 +82,23 @@ func (app *application) rateLimitExceededResponse(w http.ResponseWriter, r *http
 	// Вызываем вспомогательную функцию errorResponse() для отправки клиенту
 	// ответа 429 Too Many Requests с сообщением.
 	app.errorResponse(w, r, http.StatusTooManyRequests, message)
-}
\ No newline at end of file
+}
+func (app *application) idempotencyMismatchResponse(w http.ResponseWriter, r *http.Request) {
+	message := "the idempotency key has already been used with a different request payload"
+	app.errorResponse(w, r, http.StatusUnprocessableEntity, message)
+}
+
+func (app *application) preconditionFailedResponse(w http.ResponseWriter, r *http.Request) {
+	message := "the resource has been modified since it was retrieved, fetch it again and retry"
+	app.errorResponse(w, r, http.StatusPreconditionFailed, message)
+}
+
+func (app *application) forbiddenResponse(w http.ResponseWriter, r *http.Request) {
+	message := "your client certificate is not permitted to access this resource"
+	app.errorResponse(w, r, http.StatusForbidden, message)
+}
+
+func (app *application) clientCertificateRequiredResponse(w http.ResponseWriter, r *http.Request) {
+	message := "this resource requires a client certificate"
+	app.errorResponse(w, r, http.StatusUnauthorized, message)
+}
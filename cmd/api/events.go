@@ -0,0 +1,145 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/AnKlvy/news-service/internal/data/database"
+	"github.com/AnKlvy/news-service/internal/events"
+	"github.com/AnKlvy/news-service/internal/validator"
+	"github.com/julienschmidt/httprouter"
+)
+
+// Размер буфера событий одного SSE-клиента.
+const sseBufferSize = 256
+
+// showNewsOrStaticHandler обслуживает маршрут GET /v1/news/:id. httprouter не позволяет
+// зарегистрировать статические сегменты /v1/news/events и /v1/news/batch рядом
+// с параметром :id, поэтому нужный обработчик выбирается здесь.
+func (app *application) showNewsOrStaticHandler(w http.ResponseWriter, r *http.Request) {
+	switch httprouter.ParamsFromContext(r.Context()).ByName("id") {
+	case "events":
+		app.newsEventsHandler(w, r)
+	case "batch":
+		app.batchGetNewsHandler(w, r)
+	default:
+		app.showNewsHandler(w, r)
+	}
+}
+
+// newsEventsHandler отдаёт поток изменений новостей в формате Server-Sent Events.
+// Фильтры задаются так же, как для списка новостей. Браузер при переподключении
+// передаёт номер последнего полученного события в заголовке Last-Event-ID, и поток
+// продолжается с места обрыва. Если продолжить без пропусков нельзя, клиент получает
+// событие reset и должен заново загрузить список.
+func (app *application) newsEventsHandler(w http.ResponseWriter, r *http.Request) {
+	var nf database.NewsFilter
+
+	v := validator.New()
+	qs := r.URL.Query()
+
+	nf.Title = app.readString(qs, "title", "")
+	nf.Categories = app.readCSV(qs, "categories", []string{})
+	nf.Status = app.readString(qs, "status", "")
+	nf.Author = app.readString(qs, "author", "")
+	nf.MinReadingTime = database.Runtime(app.readInt(qs, "min_reading_time", 0, v))
+	nf.MaxReadingTime = database.Runtime(app.readInt(qs, "max_reading_time", 0, v))
+
+	// Заголовок отправляет браузер при переподключении, параметр строки запроса
+	// позволяет продолжить поток после перезагрузки страницы.
+	lastEventID := r.Header.Get("Last-Event-ID")
+	if lastEventID == "" {
+		lastEventID = app.readString(qs, "last_event_id", "")
+	}
+	var afterID int64
+	if lastEventID != "" {
+		id, err := strconv.ParseInt(lastEventID, 10, 64)
+		v.Check(err == nil && id >= 0, "last_event_id", "must be a non-negative integer")
+		afterID = id
+	}
+
+	if database.ValidateNewsFilter(v, nf); !v.Valid() {
+		app.failedValidationResponse(w, r, v.Errors)
+		return
+	}
+
+	includeHTML := app.includeContentHTML(r)
+
+	reset := false
+	sub, err := app.events.Subscribe(afterID, sseBufferSize)
+	if errors.Is(err, events.ErrResumeUnavailable) {
+		reset = true
+		sub, err = app.events.Subscribe(0, sseBufferSize)
+	}
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+	defer sub.Close()
+
+	// Поток живёт дольше WriteTimeout сервера, поэтому снимаем ограничение для этого
+	// соединения.
+	rc := http.NewResponseController(w)
+	if err := rc.SetWriteDeadline(time.Time{}); err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	// Отключаем буферизацию ответа в nginx.
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	fmt.Fprintf(w, "retry: %d\n\n", app.config.sse.retry.Milliseconds())
+	if reset {
+		fmt.Fprint(w, "event: reset\ndata: {}\n\n")
+	}
+	if err := rc.Flush(); err != nil {
+		return
+	}
+
+	heartbeat := time.NewTicker(app.config.sse.heartbeat)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case <-heartbeat.C:
+			// Комментарий SSE не виден клиенту, но не даёт прокси закрыть простаивающее соединение.
+			fmt.Fprint(w, ": heartbeat\n\n")
+		case e, ok := <-sub.Events():
+			if !ok {
+				// Шина закрыта при остановке сервера или клиент не успевал читать события;
+				// браузер переподключится сам.
+				return
+			}
+			// Событие отправляется и тогда, когда новость подходила под фильтр только до
+			// изменения: клиент должен убрать её из своей выборки.
+			if !e.Matches(nf) {
+				continue
+			}
+
+			news := *e.News
+			if !includeHTML {
+				news.ContentHTML = ""
+			}
+			data, err := json.Marshal(envelope{"news": news, "occurred_at": e.OccurredAt})
+			if err != nil {
+				app.logError(r, err)
+				return
+			}
+			fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", e.ID, e.Type, data)
+		}
+
+		if err := rc.Flush(); err != nil {
+			return
+		}
+	}
+}
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"bufio"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/AnKlvy/news-service/internal/data/database"
+	"github.com/AnKlvy/news-service/internal/events"
+	"github.com/AnKlvy/news-service/internal/jsonlog"
+)
+
+// readSSEUntil читает поток с фильтрами query до строки want и возвращает прочитанное.
+func readSSEUntil(t *testing.T, query string, lastEventID int64, bus *events.Bus, want string) string {
+	t.Helper()
+
+	app := &application{
+		logger: jsonlog.New(&strings.Builder{}, jsonlog.LevelOff),
+		events: bus,
+	}
+	app.config.sse.retry = time.Second
+	app.config.sse.heartbeat = time.Minute
+
+	srv := httptest.NewServer(http.HandlerFunc(app.newsEventsHandler))
+	defer srv.Close()
+
+	req, err := http.NewRequest(http.MethodGet, srv.URL+"?"+query, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Last-Event-ID", strconv.FormatInt(lastEventID, 10))
+
+	res, err := srv.Client().Do(req)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		t.Fatalf("status = %d, want %d", res.StatusCode, http.StatusOK)
+	}
+
+	var read strings.Builder
+	scanner := bufio.NewScanner(res.Body)
+	for scanner.Scan() {
+		read.WriteString(scanner.Text() + "\n")
+		if scanner.Text() == want {
+			return read.String()
+		}
+	}
+	t.Fatalf("stream ended without %q: %s", want, read.String())
+	return ""
+}
+
+func TestNewsEventsResetsOnForeignLastEventID(t *testing.T) {
+	other := events.NewBus(10)
+	sub, err := other.Subscribe(0, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	other.Publish(database.EventCreated, &database.News{ID: 1}, nil)
+	foreignID := (<-sub.Events()).ID
+	sub.Close()
+
+	bus := events.NewBus(10)
+	defer bus.Close()
+	bus.Publish(database.EventCreated, &database.News{ID: 1}, nil)
+
+	readSSEUntil(t, "", foreignID, bus, "event: reset")
+}
+
+func TestNewsEventsResumesFromHistory(t *testing.T) {
+	bus := events.NewBus(10)
+	defer bus.Close()
+
+	sub, err := bus.Subscribe(0, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	bus.Publish(database.EventCreated, &database.News{ID: 1}, nil)
+	bus.Publish(database.EventUpdated, &database.News{ID: 1}, nil)
+	first := (<-sub.Events()).ID
+	second := (<-sub.Events()).ID
+	sub.Close()
+
+	stream := readSSEUntil(t, "", first, bus, "id: "+strconv.FormatInt(second, 10))
+	if strings.Contains(stream, "event: reset") {
+		t.Fatalf("stream was reset instead of resumed: %s", stream)
+	}
+}
+
+func TestNewsEventsSendsTransitionsOutOfFilter(t *testing.T) {
+	bus := events.NewBus(10)
+	defer bus.Close()
+
+	sub, err := bus.Subscribe(0, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	bus.Publish(database.EventCreated, &database.News{ID: 9, Status: "DRAFT"}, nil)
+	bus.Publish(database.EventUpdated, &database.News{ID: 2, Status: "DRAFT"}, &database.News{ID: 2, Status: "DRAFT"})
+	bus.Publish(database.EventUpdated, &database.News{ID: 1, Status: "ARCHIVED"}, &database.News{ID: 1, Status: "PUBLISHED"})
+	first := (<-sub.Events()).ID
+	unrelated := (<-sub.Events()).ID
+	transition := (<-sub.Events()).ID
+	sub.Close()
+
+	stream := readSSEUntil(t, "status=PUBLISHED", first, bus, "id: "+strconv.FormatInt(transition, 10))
+	if strings.Contains(stream, "id: "+strconv.FormatInt(unrelated, 10)) {
+		t.Fatalf("stream contains an event outside the filter: %s", stream)
+	}
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// Необязательные возможности сервиса, которые можно выключить в настройках
+// без перезапуска: RSS/Atom-ленты и карты сайта.
+const (
+	featureFeeds    = "feeds"
+	featureSitemaps = "sitemaps"
+)
+
+func defaultFeatures() featureFlags {
+	return featureFlags{featureFeeds: true, featureSitemaps: true}
+}
+
+// featureFlags — значение флага -features вида "feeds=false,sitemaps=true".
+// Возможности, не упомянутые в значении, сохраняют значение по умолчанию.
+type featureFlags map[string]bool
+
+func (f *featureFlags) String() string {
+	items := make([]string, 0, len(*f))
+	for _, name := range sortedKeys(*f) {
+		items = append(items, fmt.Sprintf("%s=%t", name, (*f)[name]))
+	}
+	return strings.Join(items, ",")
+}
+
+func (f *featureFlags) Set(val string) error {
+	// Набор заменяется целиком: его копии в уже действующих настройках не меняются.
+	flags := defaultFeatures()
+	for _, item := range strings.Split(val, ",") {
+		if item = strings.TrimSpace(item); item == "" {
+			continue
+		}
+		name, value, _ := strings.Cut(item, "=")
+		if _, ok := flags[name]; !ok {
+			return fmt.Errorf("unknown feature %q", name)
+		}
+		enabled, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("invalid value %q for feature %q", value, name)
+		}
+		flags[name] = enabled
+	}
+	*f = flags
+	return nil
+}
+
+// requireFeature отвечает 404 Not Found, пока возможность выключена в настройках.
+func (app *application) requireFeature(name string, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if !app.settings().features[name] {
+			app.notFoundResponse(w, r)
+			return
+		}
+		next(w, r)
+	}
+}
@@ -0,0 +1,379 @@
+package main
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/xml"
+	"fmt"
+	"mime"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+
+	"github.com/AnKlvy/news-service/internal/cache"
+	"github.com/AnKlvy/news-service/internal/data/database"
+	"github.com/julienschmidt/httprouter"
+)
+
+// Форматы лент, которые умеет отдавать HTTP-сервер.
+const (
+	feedFormatRSS  = "rss"
+	feedFormatAtom = "atom"
+)
+
+// feedCache хранит уже сгенерированные ленты в памяти. Агрегаторы опрашивают ленты
+// очень часто, поэтому в течение TTL мы отдаём готовый документ (или 304 Not Modified),
+// не обращаясь к базе данных. Количество записей ограничено: категории и авторы в
+// адресе ленты задаёт клиент, и перебором можно было бы заполнить память.
+type feedCache struct {
+	entries *cache.LRU[string, *feedEntry]
+}
+
+type feedEntry struct {
+	body         []byte
+	contentType  string
+	etag         string
+	lastModified time.Time
+}
+
+func newFeedCache(size int, ttl time.Duration) *feedCache {
+	return &feedCache{entries: cache.NewLRU[string, *feedEntry](size, ttl)}
+}
+
+// get возвращает запись из кэша, если она есть и ещё не устарела.
+func (c *feedCache) get(key string) (*feedEntry, bool) {
+	return c.entries.Get(key)
+}
+
+// set сохраняет документ в кэше и заодно вычисляет для него ETag.
+func (c *feedCache) set(key string, body []byte, contentType string, lastModified time.Time) *feedEntry {
+	sum := sha256.Sum256(body)
+	entry := &feedEntry{
+		body:         body,
+		contentType:  contentType,
+		etag:         `"` + hex.EncodeToString(sum[:16]) + `"`,
+		lastModified: lastModified,
+	}
+	c.entries.Set(key, entry)
+	return entry
+}
+
+// Структуры для RSS 2.0 (https://www.rssboard.org/rss-specification).
+type rssFeed struct {
+	XMLName xml.Name   `xml:"rss"`
+	Version string     `xml:"version,attr"`
+	AtomNS  string     `xml:"xmlns:atom,attr"`
+	DCNS    string     `xml:"xmlns:dc,attr"`
+	Channel rssChannel `xml:"channel"`
+}
+
+type rssChannel struct {
+	Title         string    `xml:"title"`
+	Link          string    `xml:"link"`
+	Description   string    `xml:"description"`
+	AtomLink      rssLink   `xml:"atom:link"`
+	LastBuildDate string    `xml:"lastBuildDate,omitempty"`
+	Generator     string    `xml:"generator"`
+	TTL           int       `xml:"ttl,omitempty"`
+	Items         []rssItem `xml:"item"`
+}
+
+type rssLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr"`
+	Type string `xml:"type,attr"`
+}
+
+type rssItem struct {
+	Title       string         `xml:"title"`
+	Link        string         `xml:"link"`
+	Description string         `xml:"description"`
+	Creator     string         `xml:"dc:creator,omitempty"`
+	Categories  []string       `xml:"category"`
+	GUID        rssGUID        `xml:"guid"`
+	PubDate     string         `xml:"pubDate"`
+	Enclosures  []rssEnclosure `xml:"enclosure"`
+}
+
+type rssGUID struct {
+	IsPermaLink bool   `xml:"isPermaLink,attr"`
+	Value       string `xml:",chardata"`
+}
+
+type rssEnclosure struct {
+	URL    string `xml:"url,attr"`
+	Length int64  `xml:"length,attr"`
+	Type   string `xml:"type,attr"`
+}
+
+// Структуры для Atom (RFC 4287).
+type atomFeed struct {
+	XMLName   xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	Title     string      `xml:"title"`
+	ID        string      `xml:"id"`
+	Updated   string      `xml:"updated"`
+	Links     []atomLink  `xml:"link"`
+	Generator string      `xml:"generator"`
+	Entries   []atomEntry `xml:"entry"`
+}
+
+type atomLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr,omitempty"`
+	Type string `xml:"type,attr,omitempty"`
+}
+
+type atomEntry struct {
+	Title      string         `xml:"title"`
+	ID         string         `xml:"id"`
+	Updated    string         `xml:"updated"`
+	Published  string         `xml:"published"`
+	Links      []atomLink     `xml:"link"`
+	Authors    []atomPerson   `xml:"author"`
+	Categories []atomCategory `xml:"category"`
+	Content    atomContent    `xml:"content"`
+}
+
+type atomPerson struct {
+	Name string `xml:"name"`
+}
+
+type atomCategory struct {
+	Term string `xml:"term,attr"`
+}
+
+type atomContent struct {
+	Type string `xml:"type,attr"`
+	Body string `xml:",chardata"`
+}
+
+func (app *application) rssFeedHandler(w http.ResponseWriter, r *http.Request) {
+	app.serveFeed(w, r, feedFormatRSS)
+}
+
+func (app *application) atomFeedHandler(w http.ResponseWriter, r *http.Request) {
+	app.serveFeed(w, r, feedFormatAtom)
+}
+
+// serveFeed отдаёт ленту опубликованных новостей в указанном формате. Категория и
+// автор берутся из параметров маршрута, поэтому один обработчик обслуживает как
+// общую ленту, так и её варианты для отдельных категорий и авторов.
+func (app *application) serveFeed(w http.ResponseWriter, r *http.Request, format string) {
+	params := httprouter.ParamsFromContext(r.Context())
+	category := params.ByName("category")
+	author := params.ByName("author")
+
+	// Ссылки в ленте строятся от базового адреса, поэтому он входит в ключ: иначе
+	// запрос с подложным заголовком Host попал бы в кэш и достался всем клиентам.
+	baseURL := app.baseURL(r)
+	key := baseURL + "|" + format + "|" + category + "|" + author
+	entry, ok := app.feeds.get(key)
+	if !ok {
+		nf := database.NewsFilter{Status: "PUBLISHED", Author: author}
+		if category != "" {
+			nf.Categories = []string{category}
+		}
+
+		filters := database.Filters{
+			Page:         1,
+			PageSize:     app.config.feed.limit,
+			Sort:         "-updated_at",
+			SortSafelist: []string{"-updated_at"},
+		}
+
+		news, _, err := app.models.News.GetAll(nf, filters)
+		if err != nil {
+			app.serverErrorResponse(w, r, err)
+			return
+		}
+
+		// Дата последнего изменения ленты — самая поздняя дата обновления среди статей.
+		var lastModified time.Time
+		for _, n := range news {
+			if n.UpdatedAt.After(lastModified) {
+				lastModified = n.UpdatedAt
+			}
+		}
+
+		title := app.config.feed.title
+		switch {
+		case category != "":
+			title = fmt.Sprintf("%s: %s", title, category)
+		case author != "":
+			title = fmt.Sprintf("%s: %s", title, author)
+		}
+
+		var body []byte
+		var contentType string
+		selfURL := baseURL + r.URL.Path
+
+		switch format {
+		case feedFormatAtom:
+			body, err = app.buildAtomFeed(baseURL, selfURL, title, news, lastModified)
+			contentType = "application/atom+xml; charset=utf-8"
+		default:
+			body, err = app.buildRSSFeed(baseURL, selfURL, title, news, lastModified)
+			contentType = "application/rss+xml; charset=utf-8"
+		}
+		if err != nil {
+			app.serverErrorResponse(w, r, err)
+			return
+		}
+
+		entry = app.feeds.set(key, body, contentType, lastModified)
+	}
+
+	app.serveCachedDocument(w, r, entry)
+}
+
+// serveCachedDocument отправляет клиенту документ из кэша вместе с заголовками,
+// необходимыми для условных GET-запросов.
+func (app *application) serveCachedDocument(w http.ResponseWriter, r *http.Request, entry *feedEntry) {
+	w.Header().Set("Content-Type", entry.contentType)
+	w.Header().Set("ETag", entry.etag)
+	w.Header().Set("Cache-Control", fmt.Sprintf("public, max-age=%d", int(app.config.feed.cacheTTL.Seconds())))
+
+	// http.ServeContent() сам обрабатывает заголовки If-None-Match и If-Modified-Since
+	// и при совпадении отвечает 304 Not Modified без тела.
+	http.ServeContent(w, r, "", entry.lastModified, bytes.NewReader(entry.body))
+}
+
+func (app *application) buildRSSFeed(baseURL, selfURL, title string, news []*database.News, lastModified time.Time) ([]byte, error) {
+	feed := rssFeed{
+		Version: "2.0",
+		AtomNS:  "http://www.w3.org/2005/Atom",
+		DCNS:    "http://purl.org/dc/elements/1.1/",
+		Channel: rssChannel{
+			Title:       title,
+			Link:        baseURL,
+			Description: title,
+			AtomLink:    rssLink{Href: selfURL, Rel: "self", Type: "application/rss+xml"},
+			Generator:   "news-service " + version,
+			TTL:         int(app.config.feed.cacheTTL.Minutes()),
+		},
+	}
+	if !lastModified.IsZero() {
+		feed.Channel.LastBuildDate = lastModified.UTC().Format(time.RFC1123Z)
+	}
+
+	for _, n := range news {
+		link := newsURL(baseURL, n.ID)
+		item := rssItem{
+			Title:       n.Title,
+			Link:        link,
+			Description: n.ContentHTML,
+			Creator:     n.Author,
+			Categories:  n.Categories,
+			GUID:        rssGUID{IsPermaLink: true, Value: link},
+			PubDate:     publicationDate(n).UTC().Format(time.RFC1123Z),
+		}
+		for _, imageURL := range n.ImageURLs {
+			item.Enclosures = append(item.Enclosures, rssEnclosure{
+				URL:  imageURL,
+				Type: imageMediaType(imageURL),
+			})
+		}
+		feed.Channel.Items = append(feed.Channel.Items, item)
+	}
+
+	return marshalXML(feed)
+}
+
+func (app *application) buildAtomFeed(baseURL, selfURL, title string, news []*database.News, lastModified time.Time) ([]byte, error) {
+	// Поле updated в Atom обязательно, поэтому для пустой ленты используем текущее время.
+	if lastModified.IsZero() {
+		lastModified = time.Now()
+	}
+
+	feed := atomFeed{
+		Title:   title,
+		ID:      selfURL,
+		Updated: lastModified.UTC().Format(time.RFC3339),
+		Links: []atomLink{
+			{Href: selfURL, Rel: "self", Type: "application/atom+xml"},
+			{Href: baseURL, Rel: "alternate"},
+		},
+		Generator: "news-service " + version,
+	}
+
+	for _, n := range news {
+		link := newsURL(baseURL, n.ID)
+		entry := atomEntry{
+			Title:     n.Title,
+			ID:        link,
+			Updated:   n.UpdatedAt.UTC().Format(time.RFC3339),
+			Published: publicationDate(n).UTC().Format(time.RFC3339),
+			Links:     []atomLink{{Href: link, Rel: "alternate"}},
+			Authors:   []atomPerson{{Name: n.Author}},
+			Content:   atomContent{Type: "html", Body: n.ContentHTML},
+		}
+		for _, c := range n.Categories {
+			entry.Categories = append(entry.Categories, atomCategory{Term: c})
+		}
+		for _, imageURL := range n.ImageURLs {
+			entry.Links = append(entry.Links, atomLink{
+				Href: imageURL,
+				Rel:  "enclosure",
+				Type: imageMediaType(imageURL),
+			})
+		}
+		feed.Entries = append(feed.Entries, entry)
+	}
+
+	return marshalXML(feed)
+}
+
+// marshalXML кодирует документ с отступами и добавляет XML-декларацию.
+func marshalXML(v any) ([]byte, error) {
+	body, err := xml.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), append(body, '\n')...), nil
+}
+
+// imageMediaType определяет MIME-тип изображения по расширению файла в URL.
+// Если расширение неизвестно, возвращаем общий тип image/*.
+func imageMediaType(imageURL string) string {
+	u, err := url.Parse(imageURL)
+	if err == nil {
+		if t := mime.TypeByExtension(path.Ext(u.Path)); t != "" {
+			return t
+		}
+	}
+	return "image/*"
+}
+
+// newsURL возвращает канонический адрес новости.
+func newsURL(baseURL string, id int64) string {
+	return fmt.Sprintf("%s/v1/news/%d", baseURL, id)
+}
+
+// publicationDate возвращает дату публикации статьи, а для статей, опубликованных
+// до появления столбца published_at, — дату создания.
+func publicationDate(n *database.News) time.Time {
+	if n.PublishedAt != nil {
+		return *n.PublishedAt
+	}
+	return n.CreatedAt
+}
+
+// baseURL возвращает базовый адрес сайта из конфигурации. Если он не задан,
+// восстанавливаем схему и хост, по которым клиент обратился к серверу.
+func (app *application) baseURL(r *http.Request) string {
+	if app.config.baseURL != "" {
+		return strings.TrimSuffix(app.config.baseURL, "/")
+	}
+
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+	if proto := r.Header.Get("X-Forwarded-Proto"); proto == "http" || proto == "https" {
+		scheme = proto
+	}
+	return scheme + "://" + r.Host
+}
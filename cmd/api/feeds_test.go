@@ -0,0 +1,79 @@
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/AnKlvy/news-service/internal/data/database"
+	"github.com/AnKlvy/news-service/internal/jsonlog"
+)
+
+func newTestFeedApp() *application {
+	app := &application{
+		logger: jsonlog.New(&strings.Builder{}, jsonlog.LevelOff),
+		models: database.NewMockModels(),
+		feeds:  newFeedCache(10, time.Minute),
+	}
+	app.config.feed.title = "News"
+	app.config.feed.limit = 10
+	return app
+}
+
+func TestServeFeedCacheKeyIncludesHost(t *testing.T) {
+	app := newTestFeedApp()
+
+	get := func(host string) string {
+		r := httptest.NewRequest(http.MethodGet, "/feeds/rss.xml", nil)
+		r.Host = host
+		rr := httptest.NewRecorder()
+		app.rssFeedHandler(rr, r)
+		if rr.Code != http.StatusOK {
+			t.Fatalf("status = %d, want %d", rr.Code, http.StatusOK)
+		}
+		return rr.Body.String()
+	}
+
+	if body := get("evil.example"); !strings.Contains(body, "http://evil.example") {
+		t.Fatalf("feed for evil.example does not link to its host: %s", body)
+	}
+	if body := get("news.example"); strings.Contains(body, "evil.example") {
+		t.Fatalf("feed cached for a forged Host was served to another client: %s", body)
+	}
+}
+
+func TestBaseURLIgnoresUnknownForwardedProto(t *testing.T) {
+	app := newTestFeedApp()
+
+	tests := []struct {
+		proto string
+		want  string
+	}{
+		{"", "http://news.example"},
+		{"https", "https://news.example"},
+		{"javascript", "http://news.example"},
+	}
+	for _, tt := range tests {
+		r := httptest.NewRequest(http.MethodGet, "/feeds/rss.xml", nil)
+		r.Host = "news.example"
+		r.Header.Set("X-Forwarded-Proto", tt.proto)
+		if got := app.baseURL(r); got != tt.want {
+			t.Errorf("baseURL with X-Forwarded-Proto %q = %q, want %q", tt.proto, got, tt.want)
+		}
+	}
+}
+
+func TestFeedCacheIsBounded(t *testing.T) {
+	c := newFeedCache(2, time.Minute)
+	for _, key := range []string{"a", "b", "c"} {
+		c.set(key, []byte(key), "text/plain", time.Time{})
+	}
+	if _, ok := c.get("a"); ok {
+		t.Error("the least recently used feed was not evicted")
+	}
+	if entry, ok := c.get("c"); !ok || string(entry.body) != "c" {
+		t.Error("the most recent feed is missing from the cache")
+	}
+}
@@ -1,26 +1,53 @@
 package main
 
 import (
+	"context"
+	"fmt"
 	"github.com/AnKlvy/news-service/internal/data/database"
+	mediaService "github.com/AnKlvy/news-service/internal/data/grpc_service/media"
 	"github.com/AnKlvy/news-service/internal/data/grpc_service/news"
+	webhookService "github.com/AnKlvy/news-service/internal/data/grpc_service/webhooks"
+	"github.com/AnKlvy/news-service/internal/events"
+	"github.com/AnKlvy/news-service/internal/media"
 	"log"
 	"net"
-	"os"
-	"os/signal"
-	"syscall"
 
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 )
 
 type GRPCServer struct {
-	addr   string
-	model  database.Models
-	server *grpc.Server
+	addr     string
+	model    database.Models
+	uploader *media.Uploader
+	events   *events.Bus
+	news     news.Config
+	server   *grpc.Server
+	health   *health.Server
 }
 
-func NewGRPCServer(addr string, models database.Models) *GRPCServer {
-	return &GRPCServer{addr: addr,
-		model: models}
+func NewGRPCServer(addr string, models database.Models, uploader *media.Uploader, bus *events.Bus, newsConfig news.Config, opts ...grpc.ServerOption) *GRPCServer {
+	s := &GRPCServer{addr: addr,
+		model:    models,
+		uploader: uploader,
+		events:   bus,
+		news:     newsConfig}
+
+	// Сервер создаётся сразу, а не в Run: остановка может начаться раньше, чем
+	// горутина с Run успеет запуститься.
+	s.server = grpc.NewServer(opts...)
+	s.health = health.NewServer()
+	s.health.SetServingStatus("", healthpb.HealthCheckResponse_NOT_SERVING)
+	healthpb.RegisterHealthServer(s.server, s.health)
+
+	// register our grpc services
+	newsService := s.model
+	news.NewNewsService(s.server, newsService, s.events, s.news)
+	mediaService.NewMediaService(s.server, s.uploader)
+	webhookService.NewWebhookService(s.server, s.model)
+
+	return s
 }
 
 func (s *GRPCServer) Run() error {
@@ -29,24 +56,30 @@ func (s *GRPCServer) Run() error {
 		return err
 	}
 
-	s.server = grpc.NewServer()
-
-	// register our grpc services
-	newsService := s.model
-	news.NewNewsService(s.server, newsService)
-
 	log.Println("Starting gRPC server on", s.addr)
 
+	s.health.SetServingStatus("", healthpb.HealthCheckResponse_SERVING)
 	return s.server.Serve(lis)
 }
 
-// waitForShutdown блокирует до получения системного сигнала и корректно останавливает gRPC-сервер.
-func waitForShutdown(server *grpc.Server) {
-	// Создаём канал, в который пойдут сигналы ОС
-	quit := make(chan os.Signal, 1)
-	signal.Notify(quit, os.Interrupt, syscall.SIGTERM)
-	<-quit
+// Shutdown переводит сервис проверки состояния в NOT_SERVING и ждёт завершения текущих
+// вызовов. Если они не успевают завершиться до отмены ctx, соединения закрываются
+// принудительно.
+func (s *GRPCServer) Shutdown(ctx context.Context) error {
+	s.health.Shutdown()
 
-	log.Println("Received shutdown signal, gracefully stopping gRPC server...")
-	server.GracefulStop()
+	stopped := make(chan struct{})
+	go func() {
+		s.server.GracefulStop()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+		return nil
+	case <-ctx.Done():
+		s.server.Stop()
+		<-stopped
+		return fmt.Errorf("grpc server: %w", ctx.Err())
+	}
 }
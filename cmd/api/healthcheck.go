@@ -5,14 +5,19 @@ import (
 )
 
 func (app *application) healthcheckHandler(w http.ResponseWriter, r *http.Request) {
+	status, code := "available", http.StatusOK
+	// Во время остановки балансировщик должен перестать направлять сюда запросы.
+	if app.shuttingDown.Load() {
+	status, code = "unavailable", http.StatusServiceUnavailable
+	}
 	env := envelope{
-	"status": "available",
+	"status": status,
 	"system_info": map[string]string{
 	"environment": app.config.env,
 	"version": version,
 	},
 	}
-	err := app.writeJSON(w, http.StatusOK, env, nil)
+	err := app.writeJSON(w, code, env, nil)
 	if err != nil {
 	// Use the new serverErrorResponse() helper.
 	app.serverErrorResponse(w, r, err)
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/AnKlvy/news-service/internal/data/database"
+	"github.com/AnKlvy/news-service/internal/jsonlog"
+)
+
+// Клиент передаёт ключ идемпотентности в заголовке Idempotency-Key. Если запрос с этим
+// ключом уже выполнялся, ответ повторяется и помечается заголовком Idempotent-Replayed.
+const (
+	idempotencyKeyHeader     = "Idempotency-Key"
+	idempotentReplayedHeader = "Idempotent-Replayed"
+)
+
+// insertNews сохраняет новость с учётом ключа идемпотентности из запроса. replayed
+// равен true, если новость была создана раньше и загружена из базы.
+func (app *application) insertNews(r *http.Request, news *database.News) (replayed bool, err error) {
+	key := r.Header.Get(idempotencyKeyHeader)
+	if key == "" {
+		return false, app.models.News.Insert(news)
+	}
+
+	hash, err := database.HashNewsRequest(news)
+	if err != nil {
+		return false, err
+	}
+	return app.models.News.InsertIdempotent(news, database.IdempotencyKey{
+		Key:         key,
+		RequestHash: hash,
+		ExpiresAt:   time.Now().Add(app.config.idempotency.ttl),
+	})
+}
+
+// idempotencyCleaner периодически удаляет истёкшие ключи идемпотентности.
+type idempotencyCleaner struct {
+	models   database.Models
+	logger   *jsonlog.Logger
+	interval time.Duration
+
+	stop chan struct{}
+	wg   sync.WaitGroup
+}
+
+func newIdempotencyCleaner(models database.Models, logger *jsonlog.Logger, interval time.Duration) *idempotencyCleaner {
+	return &idempotencyCleaner{
+		models:   models,
+		logger:   logger,
+		interval: interval,
+		stop:     make(chan struct{}),
+	}
+}
+
+func (c *idempotencyCleaner) Start() {
+	c.wg.Add(1)
+	go func() {
+		defer c.wg.Done()
+		for {
+			select {
+			case <-c.stop:
+				return
+			case <-time.After(c.interval):
+			}
+
+			deleted, err := c.models.IdempotencyKeys.DeleteExpired()
+			if err != nil {
+				c.logger.PrintError(err, map[string]string{"component": "idempotency"})
+				continue
+			}
+			if deleted > 0 {
+				c.logger.PrintInfo("expired idempotency keys deleted", map[string]string{
+					"component": "idempotency",
+					"deleted":   strconv.FormatInt(deleted, 10),
+				})
+			}
+		}
+	}()
+}
+
+func (c *idempotencyCleaner) Shutdown() {
+	close(c.stop)
+	c.wg.Wait()
+}
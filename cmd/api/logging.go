@@ -0,0 +1,99 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/AnKlvy/news-service/internal/jsonlog"
+	"github.com/AnKlvy/news-service/internal/validator"
+)
+
+// parseComponentLevels разбирает уровни компонентов, записанные как "component=LEVEL".
+func parseComponentLevels(items []string) (map[string]jsonlog.Level, error) {
+	levels := make(map[string]jsonlog.Level, len(items))
+	for _, item := range items {
+		component, name, ok := strings.Cut(item, "=")
+		if !ok || component == "" {
+			return nil, fmt.Errorf("invalid component level %q, expected component=LEVEL", item)
+		}
+		level, err := jsonlog.ParseLevel(name)
+		if err != nil {
+			return nil, err
+		}
+		levels[component] = level
+	}
+	return levels, nil
+}
+
+// logLevelsResponse — текущие уровни журнала: общий и заданные для компонентов.
+func (app *application) logLevelsResponse() envelope {
+	components := map[string]string{}
+	for component, level := range app.logger.ComponentLevels() {
+		components[component] = level.String()
+	}
+	return envelope{"log_level": envelope{
+		"level":      app.logger.Level().String(),
+		"components": components,
+	}}
+}
+
+func (app *application) showLogLevelHandler(w http.ResponseWriter, r *http.Request) {
+	err := app.writeJSON(w, http.StatusOK, app.logLevelsResponse(), nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+// updateLogLevelHandler меняет уровни журнала без перезапуска. Поле components
+// заменяет уровни компонентов целиком; пустой объект их сбрасывает, а отсутствие
+// поля оставляет без изменений. Изменения действуют до следующей перезагрузки
+// конфигурации, если в ней изменились те же настройки.
+func (app *application) updateLogLevelHandler(w http.ResponseWriter, r *http.Request) {
+	var input struct {
+		Level      *string           `json:"level"`
+		Components map[string]string `json:"components"`
+	}
+
+	err := app.readJSON(w, r, &input)
+	if err != nil {
+		app.badRequestResponse(w, r, err)
+		return
+	}
+
+	app.reloadMu.Lock()
+	defer app.reloadMu.Unlock()
+
+	updated := *app.settings()
+	if input.Level != nil {
+		updated.logLevel = *input.Level
+	}
+	if input.Components != nil {
+		updated.logComponentLevels = nil
+		for _, component := range sortedKeys(input.Components) {
+			updated.logComponentLevels = append(updated.logComponentLevels, component+"="+input.Components[component])
+		}
+	}
+
+	v := validator.New()
+	_, err = jsonlog.ParseLevel(updated.logLevel)
+	v.Check(err == nil, "level", "must be DEBUG, INFO, WARN, ERROR, FATAL or OFF")
+	_, err = parseComponentLevels(updated.logComponentLevels)
+	v.Check(err == nil, "components", "must map component names to DEBUG, INFO, WARN, ERROR, FATAL or OFF")
+	if !v.Valid() {
+		app.failedValidationResponse(w, r, v.Errors)
+		return
+	}
+
+	app.applySettings(&updated)
+	app.logger.PrintInfo("log levels changed", map[string]string{
+		"component":  "config",
+		"level":      updated.logLevel,
+		"components": strings.Join(updated.logComponentLevels, ","),
+	})
+
+	err = app.writeJSON(w, http.StatusOK, app.logLevelsResponse(), nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
@@ -3,15 +3,31 @@ package main
 import (
 	"context"
 	"database/sql"
+	"errors"
 	"flag"
+	"fmt"
 	"github.com/AnKlvy/news-service/internal/data/database"
 	"log"
+	"net/http"
+	"net/url"
 	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
+	"github.com/AnKlvy/news-service/internal/bulk"
+	"github.com/AnKlvy/news-service/internal/certs"
+	"github.com/AnKlvy/news-service/internal/data/grpc_service/news"
+	"github.com/AnKlvy/news-service/internal/events"
 	"github.com/AnKlvy/news-service/internal/jsonlog"
-	"github.com/joho/godotenv"
+	"github.com/AnKlvy/news-service/internal/media"
+	"github.com/AnKlvy/news-service/internal/storage"
+	"github.com/AnKlvy/news-service/internal/webhooks"
 	_ "github.com/lib/pq"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
 )
 
 const version = "1.0.0"
@@ -20,12 +36,19 @@ const version = "1.0.0"
 // параметров конфигурации пула подключений.
 type config struct {
 	port int
-	env  string
-	db   struct {
-		dsn          string
-		maxOpenConns int
-		maxIdleConns int
-		maxIdleTime  string
+	// Адрес, на котором слушает gRPC-сервер.
+	grpcAddr string
+	env      string
+	// Публичный адрес сайта (например, https://news.example.com), от которого строятся
+	// абсолютные ссылки в лентах и картах сайта.
+	baseURL string
+	db      struct {
+		dsn string
+		poolConfig
+		// Адреса реплик для чтения, настройки их пулов и интервал проверки их доступности.
+		replicaDSNs          []string
+		replicas             poolConfig
+		replicaCheckInterval time.Duration
 	}
 	// Добавляем новую структуру limiter, содержащую поля для количества запросов в секунду,
 	// максимального числа запросов в очереди (burst) и булево поле, которое можно использовать
@@ -35,69 +58,346 @@ type config struct {
 		burst   int
 		enabled bool
 	}
+	// Максимальная длина автоматически формируемой выдержки из текста статьи.
+	excerptLength int
+	// Хосты, с которых разрешено подключать изображения к новостям. Пустой список
+	// снимает ограничение.
+	imageAllowedHosts []string
+	// Настройки RSS/Atom-лент: заголовок, количество статей в ленте, время, в течение
+	// которого сгенерированная лента отдаётся из памяти без обращения к базе данных,
+	// и количество хранимых в памяти документов.
+	feed struct {
+		title     string
+		limit     int
+		cacheTTL  time.Duration
+		cacheSize int
+	}
+	// Настройки загрузки файлов: каталог локального хранилища, максимальный размер
+	// файла и публичный адрес, по которому отдаются загруженные файлы.
+	// Ширины уменьшенных копий изображений и количество воркеров, которые их генерируют.
+	media struct {
+		dir              string
+		maxSize          int64
+		baseURL          string
+		thumbnailWidths  []int
+		thumbnailWorkers int
+	}
+	// Настройки Google News sitemap: название издания и язык публикаций.
+	sitemap struct {
+		publicationName string
+		language        string
+	}
+	// Количество последних событий об изменениях новостей, которые хранятся в памяти,
+	// чтобы переподключившиеся подписчики могли получить пропущенное.
+	eventsHistory int
+	// Настройки потока Server-Sent Events: интервал служебных сообщений, не дающих
+	// прокси закрыть соединение, и пауза перед переподключением браузера.
+	sse struct {
+		heartbeat time.Duration
+		retry     time.Duration
+	}
+	// Максимальное количество записей в пакетных запросах создания и получения новостей.
+	batch struct {
+		maxCreate int
+		maxGet    int
+	}
+	// Настройки массовых операций: размер порции, изменяемой в одной транзакции,
+	// и интервал проверки новых заданий.
+	bulk struct {
+		chunkSize    int
+		pollInterval time.Duration
+	}
+	// Настройки отправки webhook: количество воркеров, интервал опроса очереди, тайм-аут
+	// запроса, число попыток и границы экспоненциальной паузы между ними.
+	webhooks struct {
+		workers      int
+		pollInterval time.Duration
+		timeout      time.Duration
+		maxAttempts  int
+		backoffBase  time.Duration
+		backoffMax   time.Duration
+		// Разрешить уведомления на адреса внутренних сетей; нужно только при разработке.
+		allowPrivateTargets bool
+	}
+	// Настройки кэша новостей: размер и время жизни записей, а также кэширование
+	// страниц списка, которое по умолчанию выключено.
+	cache struct {
+		size     int
+		ttl      time.Duration
+		listSize int
+		listTTL  time.Duration
+	}
+	// Минимальный уровень записей в журнале и уровни отдельных компонентов
+	// в виде "component=LEVEL".
+	logLevel           string
+	logComponentLevels []string
+	// Значения сортировки списка новостей, доступные клиентам. Пустой список разрешает все.
+	newsSortFields []string
+	// Включённые и выключенные необязательные возможности сервиса.
+	features featureFlags
+	// Время, отведённое на остановку сервиса. Запросы, не завершившиеся за это время,
+	// прерываются.
+	shutdownTimeout time.Duration
+	// Настройки TLS, общие для HTTP- и gRPC-сервера. Если задан файл удостоверяющего
+	// центра клиентов, клиенты обязаны предъявить сертификат, а его имя становится
+	// именем вызывающего для авторизации и журнала аудита.
+	tls struct {
+		certFile       string
+		keyFile        string
+		clientCAFile   string
+		minVersion     string
+		reloadInterval time.Duration
+		allowedClients []string
+		adminClients   []string
+	}
+	// Применять ли недостающие миграции при запуске.
+	migrateOnStart bool
+	// Время хранения ключей идемпотентности и интервал удаления истёкших ключей.
+	idempotency struct {
+		ttl             time.Duration
+		cleanupInterval time.Duration
+	}
+}
+
+// poolConfig — настройки пула подключений к базе данных. Основной сервер и реплики
+// настраиваются отдельно.
+type poolConfig struct {
+	maxOpenConns int
+	maxIdleConns int
+	maxIdleTime  string
 }
 
 // Измените поле logger, чтобы оно имело тип *jsonlog.Logger вместо *log.Logger.
 type application struct {
-	config config
-	logger *jsonlog.Logger
-	models database.Models
+	config   config
+	logger   *jsonlog.Logger
+	models   database.Models
+	feeds    *feedCache
+	storage  storage.Storage
+	uploader *media.Uploader
+	events   *events.Bus
+	// Кэш новостей и реплики базы данных; nil, если они не используются.
+	newsCache *database.CachedNewsModel
+	replicas  *database.ReplicaSet
+
+	// Действующие настройки, которые меняются при перезагрузке конфигурации
+	// (см. reloadConfig), и мьютекс, не дающий перезагрузкам идти одновременно.
+	live     atomic.Pointer[config]
+	reloadMu sync.Mutex
+	// Становится true в начале остановки; проверка состояния после этого сообщает,
+	// что сервис недоступен.
+	shuttingDown atomic.Bool
 }
 
 func main() {
-	var cfg config
-	err := godotenv.Load()
+	cfg, args, err := loadConfig(os.Args[1:])
 	if err != nil {
-		log.Fatal("Ошибка при загрузке .env файла")
-	}
-	flag.IntVar(&cfg.port, "port", 4000, "API server port")
-	flag.StringVar(&cfg.env, "env", "development", "Environment (development|staging|production)")
-	flag.StringVar(&cfg.db.dsn, "db-dsn", os.Getenv("NEWS_SERVICE_DB_DSN"), "PostgreSQL DSN")
-	flag.IntVar(&cfg.db.maxOpenConns, "db-max-open-conns", 25, "PostgreSQL max open connections")
-	flag.IntVar(&cfg.db.maxIdleConns, "db-max-idle-conns", 25, "PostgreSQL max idle connections")
-	flag.StringVar(&cfg.db.maxIdleTime, "db-max-idle-time", "15m", "PostgreSQL max connection idle time")
-	// Создаем флаги командной строки для чтения значений настроек в структуру config.
-	// Обратите внимание, что по умолчанию для параметра 'enabled' установлено значение true.
-	flag.Float64Var(&cfg.limiter.rps, "limiter-rps", 2, "Rate limiter maximum requests per second")
-	flag.IntVar(&cfg.limiter.burst, "limiter-burst", 4, "Rate limiter maximum burst")
-	flag.BoolVar(&cfg.limiter.enabled, "limiter-enabled", true, "Enable rate limiter")
-	flag.Parse()
+		if errors.Is(err, flag.ErrHelp) {
+			return
+		}
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(2)
+	}
+
+	// Команда config print выводит действующие настройки и не требует базы данных.
+	if len(args) > 0 && args[0] == "config" {
+		if len(args) != 2 || args[1] != "print" {
+			fmt.Fprintln(os.Stderr, "usage: api config print")
+			os.Exit(2)
+		}
+		printConfig(os.Stdout, cfg)
+		return
+	}
+
+	database.SetAllowedImageHosts(cfg.imageAllowedHosts)
 
 	// Инициализируйте новый jsonlog.Logger, который записывает все сообщения
-	// *уровня INFO и выше* в стандартный поток вывода.
-	logger := jsonlog.New(os.Stdout, jsonlog.LevelInfo)
+	// *уровня не ниже заданного в настройках* в стандартный поток вывода.
+	logLevel, _ := jsonlog.ParseLevel(cfg.logLevel)
+	logger := jsonlog.New(os.Stdout, logLevel)
+	componentLevels, _ := parseComponentLevels(cfg.logComponentLevels)
+	logger.SetComponentLevels(componentLevels)
 
-	db, err := openDB(cfg)
+	db, err := openDB(cfg.db.dsn, cfg.db.poolConfig)
 	if err != nil {
 		// Используйте метод PrintFatal(), чтобы записать сообщение об ошибке
 		// с уровнем FATAL и завершить работу. У нас нет дополнительных параметров
 		// для включения в запись лога, поэтому мы передаем nil как второй параметр.
 		logger.PrintFatal(err, nil)
 	}
-	defer db.Close()
 
 	// Аналогично, используем метод PrintInfo() для записи сообщения уровня INFO.
 	logger.PrintInfo("database connection pool established", nil)
 
+	// Подкоманды выполняются вместо запуска сервера.
+	if len(args) > 0 {
+		if args[0] != "migrate" {
+			logger.PrintFatal(fmt.Errorf("unknown command %q", args[0]), nil)
+		}
+		err := migrateCommand(db, logger, args[1:])
+		db.Close()
+		if err != nil {
+			logger.PrintFatal(err, nil)
+		}
+		return
+	}
+
+	// Компоненты регистрируются для остановки по мере запуска, а останавливаются
+	// в обратном порядке.
+	shutdown := newShutdownCoordinator(logger, cfg.shutdownTimeout)
+	shutdown.add("database", func(context.Context) error { return db.Close() })
+
+	if err := prepareSchema(db, logger, cfg.migrateOnStart); err != nil {
+		logger.PrintFatal(err, nil)
+	}
+
+	// Ограничения базы данных должны совпадать с правилами проверки, иначе часть
+	// некорректных запросов дойдёт до базы и завершится ошибкой записи.
+	mismatches, err := database.CheckNewsConstraints(db)
+	if err != nil {
+		logger.PrintError(err, map[string]string{"component": "schema"})
+	}
+	for _, mismatch := range mismatches {
+		logger.PrintWarn(mismatch, map[string]string{"component": "schema"})
+	}
+
+	store, err := storage.NewLocal(cfg.media.dir)
+	if err != nil {
+		logger.PrintFatal(err, nil)
+	}
+	bus := events.NewBus(cfg.eventsHistory)
+
+	// Реплики подключаются без проверки: недоступная реплика не мешает запуску, а чтение
+	// идёт с основного сервера, пока она не пройдёт проверку.
+	var replicas *database.ReplicaSet
+	if len(cfg.db.replicaDSNs) > 0 {
+		var pools []*sql.DB
+		var names []string
+		for i, dsn := range cfg.db.replicaDSNs {
+			pool, err := newPool(strings.TrimSpace(dsn), cfg.db.replicas)
+			if err != nil {
+				logger.PrintFatal(err, nil)
+			}
+			pools = append(pools, pool)
+			names = append(names, replicaName(i, dsn))
+		}
+		replicas = database.NewReplicaSet(db, pools, names, logger)
+		replicas.Start(cfg.db.replicaCheckInterval)
+		shutdown.addFunc("replicas", replicas.Shutdown)
+	}
+
+	models := database.NewModels(db, replicas, cfg.excerptLength, bus)
+
+	// Изменения, сделанные другими экземплярами сервиса, приходят через LISTEN/NOTIFY.
+	listener, err := events.NewListener(cfg.db.dsn, models.Primary(), bus, logger)
+	if err != nil {
+		logger.PrintFatal(err, nil)
+	}
+	go listener.Run()
+	shutdown.add("listener", func(context.Context) error { return listener.Close() })
+
+	// Кэш подключается после создания listener: тот должен читать изменения других
+	// экземпляров напрямую из базы данных.
+	var newsCache *database.CachedNewsModel
+	if cfg.cache.size > 0 {
+		newsCache = database.NewCachedNewsModel(models.News, database.CacheConfig{
+			Size:     cfg.cache.size,
+			TTL:      cfg.cache.ttl,
+			ListSize: cfg.cache.listSize,
+			ListTTL:  cfg.cache.listTTL,
+		})
+		models.News = newsCache
+		go invalidateCacheOnEvents(bus, newsCache, logger)
+	}
+
+	thumbnails := media.NewThumbnailer(store, models, logger, cfg.media.thumbnailWidths, cfg.media.thumbnailWorkers)
+	shutdown.addFunc("thumbnails", thumbnails.Shutdown)
+
+	dispatcher := webhooks.NewDispatcher(models, logger, webhooks.Config{
+		Workers:      cfg.webhooks.workers,
+		PollInterval: cfg.webhooks.pollInterval,
+		Timeout:      cfg.webhooks.timeout,
+		MaxAttempts:  cfg.webhooks.maxAttempts,
+		BackoffBase:  cfg.webhooks.backoffBase,
+		BackoffMax:   cfg.webhooks.backoffMax,
+
+		AllowPrivateTargets: cfg.webhooks.allowPrivateTargets,
+	})
+	dispatcher.Start()
+	shutdown.addFunc("webhook dispatcher", dispatcher.Shutdown)
+
+	bulkRunner := bulk.NewRunner(models, logger, bulk.Config{
+		ChunkSize:    cfg.bulk.chunkSize,
+		PollInterval: cfg.bulk.pollInterval,
+	})
+	bulkRunner.Start()
+	shutdown.addFunc("bulk runner", bulkRunner.Shutdown)
+
+	idempotency := newIdempotencyCleaner(models, logger, cfg.idempotency.cleanupInterval)
+	idempotency.Start()
+	shutdown.addFunc("idempotency cleaner", idempotency.Shutdown)
+
+	uploader := &media.Uploader{
+		Storage:    store,
+		MaxSize:    cfg.media.maxSize,
+		BaseURL:    cfg.media.baseURL,
+		Thumbnails: thumbnails,
+	}
+
 	app := &application{
-		config: cfg,
-		logger: logger,
-		models: database.NewModels(db),
-	}
-
-	//srv := &http.Server{
-	//	Addr:    fmt.Sprintf(":%d", cfg.port),
-	//	Handler: app.routes(),
-	//	// Создается новый экземпляр Go log.Logger с помощью log.New(),
-	//	// передавая кастомный Logger в качестве первого параметра.
-	//	// Пустая строка и 0 указывают, что экземпляр log.Logger
-	//	// не должен использовать префикс или какие-либо флаги.
-	//	ErrorLog:     log.New(logger, "", 0),
-	//	IdleTimeout:  time.Minute,
-	//	ReadTimeout:  10 * time.Second,
-	//	WriteTimeout: 30 * time.Second,
-	//}
-	grpcServer := NewGRPCServer(":9000", app.models)
+		config:    cfg,
+		logger:    logger,
+		models:    models,
+		feeds:     newFeedCache(cfg.feed.cacheSize, cfg.feed.cacheTTL),
+		storage:   store,
+		uploader:  uploader,
+		events:    bus,
+		newsCache: newsCache,
+		replicas:  replicas,
+	}
+	settings := cfg
+	app.applySettings(&settings)
+
+	srv := &http.Server{
+		Addr:    fmt.Sprintf(":%d", cfg.port),
+		Handler: app.routes(),
+		// Создается новый экземпляр Go log.Logger с помощью log.New(),
+		// передавая кастомный Logger в качестве первого параметра.
+		// Пустая строка и 0 указывают, что экземпляр log.Logger
+		// не должен использовать префикс или какие-либо флаги.
+		ErrorLog:     log.New(logger, "", 0),
+		IdleTimeout:  time.Minute,
+		ReadTimeout:  10 * time.Second,
+		WriteTimeout: 30 * time.Second,
+	}
+	grpcOptions := app.grpcClientInterceptors()
+
+	// Оба сервера используют один сертификат. Он перечитывается при изменении файлов,
+	// поэтому его можно обновить без перезапуска.
+	if cfg.tls.certFile != "" {
+		minVersion, _ := certs.ParseVersion(cfg.tls.minVersion)
+		reloader, err := certs.NewReloader(certs.Config{
+			CertFile:       cfg.tls.certFile,
+			KeyFile:        cfg.tls.keyFile,
+			ClientCAFile:   cfg.tls.clientCAFile,
+			MinVersion:     minVersion,
+			ReloadInterval: cfg.tls.reloadInterval,
+		}, logger)
+		if err != nil {
+			logger.PrintFatal(err, nil)
+		}
+		reloader.Start()
+		shutdown.addFunc("certificates", reloader.Shutdown)
+
+		srv.TLSConfig = reloader.TLSConfig()
+		grpcOptions = append(grpcOptions, grpc.Creds(credentials.NewTLS(reloader.TLSConfig())))
+	}
+
+	grpcServer := NewGRPCServer(cfg.grpcAddr, app.models, app.uploader, app.events, news.Config{
+		MaxBatchCreate: cfg.batch.maxCreate,
+		MaxBatchGet:    cfg.batch.maxGet,
+		IdempotencyTTL: cfg.idempotency.ttl,
+	}, grpcOptions...)
 
 	// Снова используем метод PrintInfo() для записи сообщения "starting server"
 	// на уровне INFO. Но на этот раз передаем карту с дополнительными параметрами
@@ -107,51 +407,123 @@ func main() {
 		"env":  cfg.env,
 	})
 
-	//err = srv.ListenAndServe()
+	// Ошибки запуска серверов не завершают процесс сразу, а запускают ту же остановку,
+	// что и сигнал завершения.
+	serveErrors := make(chan error, 2)
+
+	// HTTP-сервер обслуживает ленты и служебные эндпоинты и работает параллельно с gRPC.
+	go func() {
+		logger.PrintInfo("starting HTTP server", map[string]string{
+			"addr": srv.Addr,
+			"env":  cfg.env,
+			"tls":  strconv.FormatBool(srv.TLSConfig != nil),
+		})
+		var serveErr error
+		if srv.TLSConfig != nil {
+			// Сертификат берётся из TLSConfig, поэтому пути к файлам не передаются.
+			serveErr = srv.ListenAndServeTLS("", "")
+		} else {
+			serveErr = srv.ListenAndServe()
+		}
+		if serveErr != nil && !errors.Is(serveErr, http.ErrServerClosed) {
+			serveErrors <- serveErr
+		}
+	}()
+
 	// Запускаем gRPC-сервер в отдельном горутине
 	go func() {
-		if serveErr := grpcServer.Run(); serveErr != nil {
-			// Используйте метод PrintFatal() для логирования ошибки и завершения работы.
-			logger.PrintFatal(err, nil)
+		if serveErr := grpcServer.Run(); serveErr != nil && !errors.Is(serveErr, grpc.ErrServerStopped) {
+			serveErrors <- serveErr
 		}
 	}()
 
+	// Серверы останавливаются первыми. Состояние переключается на недоступное до того,
+	// как они перестанут принимать соединения, а шина событий закрывается до ожидания
+	// запросов: иначе потоки WatchNews и Server-Sent Events не дали бы им завершиться.
+	shutdown.add("servers", func(ctx context.Context) error {
+		app.shuttingDown.Store(true)
+		bus.Close()
+
+		httpErr := make(chan error, 1)
+		go func() {
+			err := srv.Shutdown(ctx)
+			if err != nil {
+				srv.Close()
+				err = fmt.Errorf("http server: %w", err)
+			}
+			httpErr <- err
+		}()
+		grpcErr := grpcServer.Shutdown(ctx)
+		return errors.Join(<-httpErr, grpcErr)
+	})
+
 	// Ждём сигнала завершения (Ctrl+C или SIGTERM в Kubernetes)
-	waitForShutdown(grpcServer.server)
-	log.Println("Server gracefully stopped")
+	err = waitForShutdown(logger, serveErrors, func() {
+		if _, err := app.reloadConfig(); err != nil {
+			logger.PrintError(err, map[string]string{"component": "config"})
+		}
+	})
+	if err != nil {
+		logger.PrintError(err, nil)
+	}
+
+	logger.PrintInfo("shutting down server", map[string]string{"timeout": cfg.shutdownTimeout.String()})
+	if shutdownErr := shutdown.shutdown(); shutdownErr != nil || err != nil {
+		os.Exit(1)
+	}
+	logger.PrintInfo("stopped server", nil)
+}
+
+func openDB(dsn string, pool poolConfig) (*sql.DB, error) {
+	db, err := newPool(dsn, pool)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	// Проверяем соединение с базой данных.
+	err = db.PingContext(ctx)
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	return db, nil
 }
 
-func openDB(cfg config) (*sql.DB, error) {
-	db, err := sql.Open("postgres", cfg.db.dsn)
+// newPool создаёт пул подключений с заданными настройками, не подключаясь к базе данных.
+func newPool(dsn string, pool poolConfig) (*sql.DB, error) {
+	db, err := sql.Open("postgres", dsn)
 	if err != nil {
 		return nil, err
 	}
 
 	// Устанавливаем максимальное количество открытых (используемых + свободных) соединений в пуле.
 	// Если передано значение меньше или равное 0, ограничение не устанавливается.
-	db.SetMaxOpenConns(cfg.db.maxOpenConns)
+	db.SetMaxOpenConns(pool.maxOpenConns)
 
 	// Устанавливаем максимальное количество свободных соединений в пуле.
 	// Если передано значение меньше или равное 0, ограничение не устанавливается.
-	db.SetMaxIdleConns(cfg.db.maxIdleConns)
+	db.SetMaxIdleConns(pool.maxIdleConns)
 
 	// Используем функцию time.ParseDuration() для преобразования строки с таймаутом простоя
 	// в тип time.Duration.
-	duration, err := time.ParseDuration(cfg.db.maxIdleTime)
+	duration, err := time.ParseDuration(pool.maxIdleTime)
 	if err != nil {
+		db.Close()
 		return nil, err
 	}
 
 	// Устанавливаем максимальное время простоя соединений.
 	db.SetConnMaxIdleTime(duration)
+	return db, nil
+}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-	defer cancel()
-
-	// Проверяем соединение с базой данных.
-	err = db.PingContext(ctx)
-	if err != nil {
-		return nil, err
+// replicaName возвращает имя реплики для логов: хост из DSN без логина и пароля.
+func replicaName(i int, dsn string) string {
+	if u, err := url.Parse(strings.TrimSpace(dsn)); err == nil && u.Host != "" {
+		return u.Host
 	}
-	return db, nil
+	return fmt.Sprintf("replica-%d", i+1)
 }
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/AnKlvy/news-service/internal/media"
+	"github.com/AnKlvy/news-service/internal/storage"
+	"github.com/julienschmidt/httprouter"
+)
+
+// uploadMediaHandler принимает файл из поля "file" формы multipart/form-data и
+// возвращает адрес, который можно указать в image_urls или media новости.
+func (app *application) uploadMediaHandler(w http.ResponseWriter, r *http.Request) {
+	// Ограничиваем размер тела запроса: к размеру файла добавляем запас на заголовки формы.
+	r.Body = http.MaxBytesReader(w, r.Body, app.config.media.maxSize+1<<20)
+
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		var maxBytesError *http.MaxBytesError
+		switch {
+		case errors.As(err, &maxBytesError):
+			app.failedValidationResponse(w, r, map[string]string{"file": media.ErrTooLarge.Error()})
+		default:
+			app.badRequestResponse(w, r, fmt.Errorf("body must be multipart/form-data with a \"file\" field: %w", err))
+		}
+		return
+	}
+	defer file.Close()
+
+	obj, err := app.uploader.Upload(r.Context(), file, header.Header.Get("Content-Type"))
+	if err != nil {
+		switch {
+		case errors.Is(err, media.ErrTooLarge),
+			errors.Is(err, media.ErrEmpty),
+			errors.Is(err, media.ErrUnsupportedType),
+			errors.Is(err, media.ErrContentTypeMismatch):
+			app.failedValidationResponse(w, r, map[string]string{"file": err.Error()})
+		default:
+			app.serverErrorResponse(w, r, err)
+		}
+		return
+	}
+
+	headers := make(http.Header)
+	headers.Set("Location", obj.URL)
+
+	err = app.writeJSON(w, http.StatusCreated, envelope{"media": obj}, headers)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+// serveMediaHandler отдаёт загруженный файл. Имена файлов построены из хеша
+// содержимого и никогда не меняются, поэтому клиенты могут кэшировать их бессрочно.
+func (app *application) serveMediaHandler(w http.ResponseWriter, r *http.Request) {
+	name := httprouter.ParamsFromContext(r.Context()).ByName("name")
+
+	f, info, err := app.storage.Open(r.Context(), name)
+	if err != nil {
+		switch {
+		case errors.Is(err, storage.ErrNotFound), errors.Is(err, storage.ErrInvalidName):
+			app.notFoundResponse(w, r)
+		default:
+			app.serverErrorResponse(w, r, err)
+		}
+		return
+	}
+	defer f.Close()
+
+	w.Header().Set("Cache-Control", "public, max-age=31536000, immutable")
+	w.Header().Set("ETag", `"`+name+`"`)
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+
+	http.ServeContent(w, r, info.Name, info.ModTime, f)
+}
@@ -66,7 +66,10 @@ func (app *application) rateLimit(next http.Handler) http.Handler {
 
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		// Выполняем проверку только в том случае, если ограничение запросов включено.
-		if app.config.limiter.enabled {
+		// Настройки ограничителя читаются при каждом запросе: они меняются при
+		// перезагрузке конфигурации.
+		limiter := app.settings().limiter
+		if limiter.enabled {
 			ip, _, err := net.SplitHostPort(r.RemoteAddr)
 			if err != nil {
 				app.serverErrorResponse(w, r, err)
@@ -76,9 +79,14 @@ func (app *application) rateLimit(next http.Handler) http.Handler {
 			if _, found := clients[ip]; !found {
 				clients[ip] = &client{
 					// Используем значения количества запросов в секунду и burst из структуры config.
-					limiter: rate.NewLimiter(rate.Limit(app.config.limiter.rps), app.config.limiter.burst),
+					limiter: rate.NewLimiter(rate.Limit(limiter.rps), limiter.burst),
 				}
 			}
+			// Ограничители уже известных клиентов подстраиваются под новые настройки.
+			if l := clients[ip].limiter; l.Limit() != rate.Limit(limiter.rps) || l.Burst() != limiter.burst {
+				l.SetLimit(rate.Limit(limiter.rps))
+				l.SetBurst(limiter.burst)
+			}
 			clients[ip].lastSeen = time.Now()
 			if !clients[ip].limiter.Allow() {
 				mu.Unlock()
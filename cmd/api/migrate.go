@@ -0,0 +1,96 @@
+package main
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+
+	"github.com/AnKlvy/news-service/internal/jsonlog"
+	"github.com/AnKlvy/news-service/internal/migrate"
+	"github.com/AnKlvy/news-service/migrations"
+)
+
+const migrateUsage = "usage: api [flags] migrate up | down [steps] | status | goto <version>"
+
+// migrateCommand выполняет подкоманду migrate и возвращает ошибку, если она не удалась.
+func migrateCommand(db *sql.DB, logger *jsonlog.Logger, args []string) error {
+	migrator, err := migrate.New(db, migrations.Files, logger)
+	if err != nil {
+		return err
+	}
+	if len(args) == 0 {
+		return errors.New(migrateUsage)
+	}
+
+	switch args[0] {
+	case "up":
+		return migrator.Up()
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			steps, err = strconv.Atoi(args[1])
+			if err != nil || steps < 1 {
+				return fmt.Errorf("invalid number of steps %q", args[1])
+			}
+		}
+		return migrator.Down(steps)
+	case "goto":
+		if len(args) < 2 {
+			return errors.New(migrateUsage)
+		}
+		version, err := strconv.ParseInt(args[1], 10, 64)
+		if err != nil || version < 0 {
+			return fmt.Errorf("invalid version %q", args[1])
+		}
+		return migrator.Goto(version)
+	case "status":
+		status, err := migrator.Status()
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(os.Stdout, "version: %d (latest %d)\n", status.Version, status.Latest)
+		if status.Dirty {
+			fmt.Fprintln(os.Stdout, "dirty: true")
+		}
+		for _, m := range status.Pending {
+			fmt.Fprintf(os.Stdout, "pending: %06d_%s\n", m.Version, m.Name)
+		}
+		return nil
+	default:
+		return errors.New(migrateUsage)
+	}
+}
+
+// prepareSchema применяет миграции при запуске, если это разрешено флагом
+// -migrate-on-start, и не даёт запустить сервис со схемой старее встроенных миграций.
+func prepareSchema(db *sql.DB, logger *jsonlog.Logger, migrateOnStart bool) error {
+	migrator, err := migrate.New(db, migrations.Files, logger)
+	if err != nil {
+		return err
+	}
+
+	if migrateOnStart {
+		if err := migrator.Up(); err != nil {
+			return err
+		}
+	}
+
+	status, err := migrator.Status()
+	if err != nil {
+		return err
+	}
+	switch {
+	case status.Behind():
+		return fmt.Errorf("database schema is behind: version %d (dirty %t), expected %d; run \"migrate up\" or start with -migrate-on-start",
+			status.Version, status.Dirty, status.Latest)
+	case status.Version > status.Latest && !migrateOnStart:
+		// С -migrate-on-start об этом уже сообщил migrator.Up.
+		logger.PrintInfo("database schema is newer than this build", map[string]string{
+			"version": strconv.FormatInt(status.Version, 10),
+			"latest":  strconv.FormatInt(status.Latest, 10),
+		})
+	}
+	return nil
+}
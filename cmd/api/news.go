@@ -1,218 +1,448 @@
 package main
 
-//
-//import (
-//	"errors"
-//	"fmt"
-//	"github.com/AnKlvy/news-service/internal/data/database"
-//	"net/http"
-//	"strconv"
-//
-//	"github.com/AnKlvy/news-service/internal/validator"
-//)
-//
-//func (app *application) createNewsHandler(w http.ResponseWriter, r *http.Request) {
-//	var input struct {
-//		Title      string   `json:"title"`
-//		Content    string   `json:"content"`
-//		Categories []string `json:"categories"`
-//		Status     string   `json:"status"`
-//		ImageURLs  *string  `json:"image_url,omitempty"`
-//	}
-//
-//	err := app.readJSON(w, r, &input)
-//	if err != nil {
-//		app.badRequestResponse(w, r, err)
-//		return
-//	}
-//
-//	news := &database.News{
-//		Title:      input.Title,
-//		Content:    input.Content,
-//		Categories: input.Categories,
-//		Status:     input.Status,
-//		ImageURLs:  input.ImageURLs,
-//	}
-//
-//	v := validator.New()
-//	if database.ValidateNews(v, news); !v.Valid() {
-//		app.failedValidationResponse(w, r, v.Errors)
-//		return
-//	}
-//
-//	err = app.models.News.Insert(news)
-//	if err != nil {
-//		app.serverErrorResponse(w, r, err)
-//		return
-//	}
-//
-//	headers := make(http.Header)
-//	headers.Set("Location", fmt.Sprintf("/v1/news/%d", news.ID))
-//
-//	err = app.writeJSON(w, http.StatusCreated, envelope{"news": news}, headers)
-//	if err != nil {
-//		app.serverErrorResponse(w, r, err)
-//	}
-//}
-//
-//func (app *application) showNewsHandler(w http.ResponseWriter, r *http.Request) {
-//	id, err := app.readIDParam(r)
-//	if err != nil {
-//		app.notFoundResponse(w, r)
-//		return
-//	}
-//
-//	news, err := app.models.News.Get(id)
-//	if err != nil {
-//		switch {
-//		case errors.Is(err, database.ErrRecordNotFound):
-//			app.notFoundResponse(w, r)
-//		default:
-//			app.serverErrorResponse(w, r, err)
-//		}
-//		return
-//	}
-//
-//	err = app.writeJSON(w, http.StatusOK, envelope{"news": news}, nil)
-//	if err != nil {
-//		app.serverErrorResponse(w, r, err)
-//	}
-//}
-//
-//func (app *application) updateNewsHandler(w http.ResponseWriter, r *http.Request) {
-//	id, err := app.readIDParam(r)
-//	if err != nil {
-//		app.notFoundResponse(w, r)
-//		return
-//	}
-//
-//	news, err := app.models.News.Get(id)
-//	if err != nil {
-//		switch {
-//		case errors.Is(err, database.ErrRecordNotFound):
-//			app.notFoundResponse(w, r)
-//		default:
-//			app.serverErrorResponse(w, r, err)
-//		}
-//		return
-//	}
-//
-//	if r.Header.Get("X-Expected-Version") != "" {
-//		if strconv.FormatInt(int64(news.Version), 10) != r.Header.Get("X-Expected-Version") {
-//			app.editConflictResponse(w, r)
-//			return
-//		}
-//	}
-//
-//	var input struct {
-//		Title      *string  `json:"title"`
-//		Content    *string  `json:"content"`
-//		Categories []string `json:"categories"`
-//		Status     *string  `json:"status"`
-//		ImageURLs  *string  `json:"image_url,omitempty"`
-//	}
-//
-//	err = app.readJSON(w, r, &input)
-//	if err != nil {
-//		app.badRequestResponse(w, r, err)
-//		return
-//	}
-//
-//	if input.Title != nil {
-//		news.Title = *input.Title
-//	}
-//	if input.Content != nil {
-//		news.Content = *input.Content
-//	}
-//	if input.Categories != nil {
-//		news.Categories = input.Categories
-//	}
-//	if input.Status != nil {
-//		news.Status = *input.Status
-//	}
-//	if input.ImageURLs != nil {
-//		news.ImageURLs = input.ImageURLs
-//	}
-//
-//	v := validator.New()
-//	if database.ValidateNews(v, news); !v.Valid() {
-//		app.failedValidationResponse(w, r, v.Errors)
-//		return
-//	}
-//
-//	err = app.models.News.Update(news)
-//	if err != nil {
-//		switch {
-//		case errors.Is(err, database.ErrEditConflict):
-//			app.editConflictResponse(w, r)
-//		default:
-//			app.serverErrorResponse(w, r, err)
-//		}
-//		return
-//	}
-//
-//	err = app.writeJSON(w, http.StatusOK, envelope{"news": news}, nil)
-//	if err != nil {
-//		app.serverErrorResponse(w, r, err)
-//	}
-//}
-//
-//func (app *application) deleteNewsHandler(w http.ResponseWriter, r *http.Request) {
-//	id, err := app.readIDParam(r)
-//	if err != nil {
-//		app.notFoundResponse(w, r)
-//		return
-//	}
-//
-//	err = app.models.News.Delete(id)
-//	if err != nil {
-//		switch {
-//		case errors.Is(err, database.ErrRecordNotFound):
-//			app.notFoundResponse(w, r)
-//		default:
-//			app.serverErrorResponse(w, r, err)
-//		}
-//		return
-//	}
-//
-//	err = app.writeJSON(w, http.StatusOK, envelope{"message": "news deleted successfully"}, nil)
-//	if err != nil {
-//		app.serverErrorResponse(w, r, err)
-//	}
-//}
-//
-//func (app *application) listNewsHandler(w http.ResponseWriter, r *http.Request) {
-//	var input struct {
-//		Title      string
-//		Categories []string
-//		Status     string
-//		database.Filters
-//	}
-//
-//	v := validator.New()
-//	qs := r.URL.Query()
-//
-//	input.Title = app.readString(qs, "title", "")
-//	input.Categories = app.readCSV(qs, "categories", []string{})
-//	input.Status = app.readString(qs, "status", "")
-//	input.Filters.Page = app.readInt(qs, "page", 1, v)
-//	input.Filters.PageSize = app.readInt(qs, "page_size", 20, v)
-//	input.Filters.Sort = app.readString(qs, "sort", "id")
-//	input.Filters.SortSafelist = []string{"id", "title", "status", "-id", "-title", "-status"}
-//
-//	if database.ValidateFilters(v, input.Filters); !v.Valid() {
-//		app.failedValidationResponse(w, r, v.Errors)
-//		return
-//	}
-//
-//	news, metadata, err := app.models.News.GetAll(input.Title, input.Categories, input.Status, input.Filters)
-//	if err != nil {
-//		app.serverErrorResponse(w, r, err)
-//		return
-//	}
-//
-//	err = app.writeJSON(w, http.StatusOK, envelope{"news": news, "metadata": metadata}, nil)
-//	if err != nil {
-//		app.serverErrorResponse(w, r, err)
-//	}
-//}
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/AnKlvy/news-service/internal/data/database"
+	"github.com/AnKlvy/news-service/internal/markup"
+	"github.com/AnKlvy/news-service/internal/validator"
+)
+
+// createNewsInput — тело запроса на создание новости. Используется и при пакетном создании.
+type createNewsInput struct {
+	Title         string           `json:"title"`
+	Content       string           `json:"content"`
+	ContentFormat string           `json:"content_format"`
+	Categories    []string         `json:"categories"`
+	Status        string           `json:"status"`
+	ImageURLs     []string         `json:"image_urls,omitempty"`
+	Media         []database.Media `json:"media,omitempty"`
+	Author        string           `json:"author"`
+}
+
+func (input createNewsInput) news() *database.News {
+	news := &database.News{
+		Title:         input.Title,
+		Content:       input.Content,
+		ContentFormat: input.ContentFormat,
+		Categories:    input.Categories,
+		Status:        input.Status,
+		ImageURLs:     input.ImageURLs,
+		Media:         input.Media,
+		Author:        input.Author,
+	}
+	if news.ContentFormat == "" {
+		news.ContentFormat = markup.FormatPlain
+	}
+	database.NormalizeMedia(news)
+	return news
+}
+
+func (app *application) createNewsHandler(w http.ResponseWriter, r *http.Request) {
+	var input createNewsInput
+
+	err := app.readJSON(w, r, &input)
+	if err != nil {
+		app.badRequestResponse(w, r, err)
+		return
+	}
+
+	news := input.news()
+
+	v := validator.New()
+	database.ValidateIdempotencyKey(v, r.Header.Get(idempotencyKeyHeader))
+	if database.ValidateNews(v, news); !v.Valid() {
+		app.failedValidationResponse(w, r, v.Errors)
+		return
+	}
+
+	replayed, err := app.insertNews(r, news)
+	if err != nil {
+		var violation *database.ViolationError
+		switch {
+		case errors.As(err, &violation):
+			app.failedValidationResponse(w, r, map[string]string{violation.Field: violation.Message})
+		case errors.Is(err, database.ErrIdempotencyMismatch):
+			app.idempotencyMismatchResponse(w, r)
+		case errors.Is(err, database.ErrRecordNotFound):
+			app.notFoundResponse(w, r)
+		default:
+			app.serverErrorResponse(w, r, err)
+		}
+		return
+	}
+
+	headers := make(http.Header)
+	headers.Set("Location", fmt.Sprintf("/v1/news/%d", news.ID))
+	if replayed {
+		headers.Set(idempotentReplayedHeader, "true")
+	}
+
+	err = app.writeJSON(w, http.StatusCreated, envelope{"news": news}, headers)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+func (app *application) showNewsHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := app.readIDParam(r)
+	if err != nil {
+		app.notFoundResponse(w, r)
+		return
+	}
+
+	news, err := app.models.News.Get(id)
+	if err != nil {
+		switch {
+		case errors.Is(err, database.ErrRecordNotFound):
+			app.notFoundResponse(w, r)
+		default:
+			app.serverErrorResponse(w, r, err)
+		}
+		return
+	}
+
+	if app.notModified(w, r, newsETag(news), news.UpdatedAt) {
+		return
+	}
+
+	if !app.includeContentHTML(r) {
+		news.ContentHTML = ""
+	}
+
+	err = app.writeJSON(w, http.StatusOK, envelope{"news": news}, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+func (app *application) updateNewsHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := app.readIDParam(r)
+	if err != nil {
+		app.notFoundResponse(w, r)
+		return
+	}
+
+	// Новость читается с основного сервера: реплика может вернуть устаревшую версию.
+	news, err := app.models.Primary().News.Get(id)
+	if err != nil {
+		switch {
+		case errors.Is(err, database.ErrRecordNotFound):
+			app.notFoundResponse(w, r)
+		default:
+			app.serverErrorResponse(w, r, err)
+		}
+		return
+	}
+
+	// Клиент, передавший If-Match, изменяет новость только в той версии, которую получил.
+	if !preconditionMet(r, newsETag(news)) {
+		app.preconditionFailedResponse(w, r)
+		return
+	}
+
+	// Тело запроса — JSON Merge Patch (RFC 7386): изменяются только переданные поля,
+	// null очищает поле, а массивы заменяются целиком, поэтому пустой массив удаляет
+	// все изображения или категории.
+	var patch map[string]json.RawMessage
+	err = app.readJSON(w, r, &patch)
+	if err != nil {
+		app.badRequestResponse(w, r, err)
+		return
+	}
+
+	err = applyNewsMergePatch(news, patch)
+	if err != nil {
+		app.badRequestResponse(w, r, err)
+		return
+	}
+
+	v := validator.New()
+	if database.ValidateNews(v, news); !v.Valid() {
+		app.failedValidationResponse(w, r, v.Errors)
+		return
+	}
+
+	err = app.models.News.Update(news)
+	if err != nil {
+		var violation *database.ViolationError
+		switch {
+		case errors.As(err, &violation):
+			app.failedValidationResponse(w, r, map[string]string{violation.Field: violation.Message})
+		case errors.Is(err, database.ErrEditConflict) && r.Header.Get("If-Match") != "":
+			app.preconditionFailedResponse(w, r)
+		case errors.Is(err, database.ErrEditConflict):
+			app.editConflictResponse(w, r)
+		default:
+			app.serverErrorResponse(w, r, err)
+		}
+		return
+	}
+
+	headers := make(http.Header)
+	headers.Set("ETag", newsETag(news))
+
+	err = app.writeJSON(w, http.StatusOK, envelope{"news": news}, headers)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+func (app *application) deleteNewsHandler(w http.ResponseWriter, r *http.Request) {
+	id, err := app.readIDParam(r)
+	if err != nil {
+		app.notFoundResponse(w, r)
+		return
+	}
+
+	// Без If-Match новость удаляется в любой версии.
+	var version int32
+	if r.Header.Get("If-Match") != "" {
+		news, err := app.models.Primary().News.Get(id)
+		if err != nil {
+			switch {
+			case errors.Is(err, database.ErrRecordNotFound):
+				app.notFoundResponse(w, r)
+			default:
+				app.serverErrorResponse(w, r, err)
+			}
+			return
+		}
+		if !preconditionMet(r, newsETag(news)) {
+			app.preconditionFailedResponse(w, r)
+			return
+		}
+		version = news.Version
+	}
+
+	err = app.models.News.Delete(id, version)
+	if err != nil {
+		switch {
+		case errors.Is(err, database.ErrRecordNotFound):
+			app.notFoundResponse(w, r)
+		case errors.Is(err, database.ErrEditConflict):
+			app.preconditionFailedResponse(w, r)
+		default:
+			app.serverErrorResponse(w, r, err)
+		}
+		return
+	}
+
+	err = app.writeJSON(w, http.StatusOK, envelope{"message": "news deleted successfully"}, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+func (app *application) listNewsHandler(w http.ResponseWriter, r *http.Request) {
+	var input struct {
+		database.NewsFilter
+		database.Filters
+	}
+
+	v := validator.New()
+	qs := r.URL.Query()
+
+	input.Title = app.readString(qs, "title", "")
+	input.Categories = app.readCSV(qs, "categories", []string{})
+	input.Status = app.readString(qs, "status", "")
+	input.Author = app.readString(qs, "author", "")
+	input.MinReadingTime = database.Runtime(app.readInt(qs, "min_reading_time", 0, v))
+	input.MaxReadingTime = database.Runtime(app.readInt(qs, "max_reading_time", 0, v))
+	input.Filters.Page = app.readInt(qs, "page", 1, v)
+	input.Filters.PageSize = app.readInt(qs, "page_size", 20, v)
+	input.Filters.Sort = app.readString(qs, "sort", "id")
+	input.Filters.SortSafelist = database.NewsSortFields()
+
+	database.ValidateNewsFilter(v, input.NewsFilter)
+	if database.ValidateFilters(v, input.Filters); !v.Valid() {
+		app.failedValidationResponse(w, r, v.Errors)
+		return
+	}
+
+	news, metadata, err := app.models.News.GetAll(input.NewsFilter, input.Filters)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	if app.notModified(w, r, newsListETag(news, metadata), newsListLastModified(news)) {
+		return
+	}
+
+	if !app.includeContentHTML(r) {
+		for _, n := range news {
+			n.ContentHTML = ""
+		}
+	}
+
+	err = app.writeJSON(w, http.StatusOK, envelope{"news": news, "metadata": metadata}, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+// applyNewsMergePatch применяет к новости JSON Merge Patch. Изображения можно передать
+// как media или как image_urls; если переданы оба поля, используется media.
+func applyNewsMergePatch(news *database.News, patch map[string]json.RawMessage) error {
+	for key, raw := range patch {
+		if !validator.PermittedValue(key, database.NewsUpdatableFields...) {
+			return fmt.Errorf("body contains unknown key %q", key)
+		}
+
+		var err error
+		switch key {
+		case "title":
+			err = unmarshalPatchValue(raw, &news.Title)
+		case "content":
+			err = unmarshalPatchValue(raw, &news.Content)
+		case "content_format":
+			err = unmarshalPatchValue(raw, &news.ContentFormat)
+		case "categories":
+			err = unmarshalPatchValue(raw, &news.Categories)
+		case "status":
+			err = unmarshalPatchValue(raw, &news.Status)
+		case "author":
+			err = unmarshalPatchValue(raw, &news.Author)
+		case "media":
+			news.Media = nil
+			err = unmarshalPatchValue(raw, &news.Media)
+		case "image_urls":
+			if _, ok := patch["media"]; ok {
+				continue
+			}
+			var urls []string
+			err = unmarshalPatchValue(raw, &urls)
+			news.Media = database.MediaFromURLs(urls, news.Media)
+		}
+		if key == "media" || key == "image_urls" {
+			// Иначе NormalizeMedia восстановит удалённые изображения из старого ImageURLs.
+			news.ImageURLs = nil
+		}
+		if err != nil {
+			return fmt.Errorf("body contains incorrect JSON type for field %q", key)
+		}
+	}
+
+	database.NormalizeMedia(news)
+	return nil
+}
+
+// unmarshalPatchValue записывает значение поля из Merge Patch в dst. null сбрасывает
+// dst в нулевое значение.
+func unmarshalPatchValue[T any](raw json.RawMessage, dst *T) error {
+	var zero T
+	*dst = zero
+	if string(raw) == "null" {
+		return nil
+	}
+	return json.Unmarshal(raw, dst)
+}
+
+// includeContentHTML сообщает, запросил ли клиент HTML-представление содержимого
+// параметром строки запроса include=content_html.
+func (app *application) includeContentHTML(r *http.Request) bool {
+	return validator.PermittedValue("content_html", app.readCSV(r.URL.Query(), "include", []string{})...)
+}
+
+// batchCreateNewsHandler создаёт несколько новостей в одной транзакции. Если хотя бы
+// одна новость не проходит проверку, не создаётся ни одна, а ошибки возвращаются
+// с ключами вида "items[<позиция>].<поле>".
+func (app *application) batchCreateNewsHandler(w http.ResponseWriter, r *http.Request) {
+	var input struct {
+		Items []createNewsInput `json:"items"`
+	}
+
+	err := app.readJSON(w, r, &input)
+	if err != nil {
+		app.badRequestResponse(w, r, err)
+		return
+	}
+
+	v := validator.New()
+	v.Check(len(input.Items) >= 1, "items", "must contain at least 1 item")
+	v.Check(len(input.Items) <= app.config.batch.maxCreate, "items", fmt.Sprintf("must not contain more than %d items", app.config.batch.maxCreate))
+	if !v.Valid() {
+		app.failedValidationResponse(w, r, v.Errors)
+		return
+	}
+
+	news := make([]*database.News, 0, len(input.Items))
+	for _, item := range input.Items {
+		news = append(news, item.news())
+	}
+
+	if database.ValidateNewsBatch(v, news); !v.Valid() {
+		app.failedValidationResponse(w, r, v.Errors)
+		return
+	}
+
+	err = app.models.News.InsertBatch(news)
+	if err != nil {
+		var violation *database.ViolationError
+		switch {
+		case errors.As(err, &violation):
+			app.failedValidationResponse(w, r, map[string]string{violation.Field: violation.Message})
+		default:
+			app.serverErrorResponse(w, r, err)
+		}
+		return
+	}
+
+	err = app.writeJSON(w, http.StatusCreated, envelope{"news": news}, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
+
+// batchGetNewsHandler возвращает новости по списку идентификаторов ids в том же порядке,
+// в котором они запрошены. Идентификаторы несуществующих новостей перечисляются
+// в missing_ids.
+func (app *application) batchGetNewsHandler(w http.ResponseWriter, r *http.Request) {
+	v := validator.New()
+
+	var ids []int64
+	for _, s := range app.readCSV(r.URL.Query(), "ids", []string{}) {
+		id, err := strconv.ParseInt(s, 10, 64)
+		if err != nil || id < 1 {
+			v.AddError("ids", "must contain only positive integers")
+			break
+		}
+		ids = append(ids, id)
+	}
+	v.Check(len(ids) >= 1, "ids", "must contain at least 1 id")
+	v.Check(len(ids) <= app.config.batch.maxGet, "ids", fmt.Sprintf("must not contain more than %d ids", app.config.batch.maxGet))
+	if !v.Valid() {
+		app.failedValidationResponse(w, r, v.Errors)
+		return
+	}
+
+	found, err := app.models.News.GetMany(ids)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+		return
+	}
+
+	includeHTML := app.includeContentHTML(r)
+	news := []*database.News{}
+	missing := []int64{}
+	for i, n := range found {
+		if n == nil {
+			missing = append(missing, ids[i])
+			continue
+		}
+		if !includeHTML {
+			n.ContentHTML = ""
+		}
+		news = append(news, n)
+	}
+
+	err = app.writeJSON(w, http.StatusOK, envelope{"news": news, "missing_ids": missing}, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
@@ -0,0 +1,114 @@
+package main
+
+import (
+	"encoding/json"
+	"slices"
+	"testing"
+
+	"github.com/AnKlvy/news-service/internal/data/database"
+)
+
+func newTestPatchNews() *database.News {
+	news := &database.News{
+		Title:      "Title",
+		Content:    "Content",
+		Categories: []string{"world"},
+		Status:     "DRAFT",
+		Author:     "Author",
+		Media: []database.Media{
+			{URL: "https://img.example.com/a.jpg", AltText: "A"},
+			{URL: "https://img.example.com/b.jpg", AltText: "B"},
+		},
+	}
+	database.NormalizeMedia(news)
+	return news
+}
+
+func applyTestPatch(t *testing.T, news *database.News, body string) error {
+	t.Helper()
+
+	var patch map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(body), &patch); err != nil {
+		t.Fatal(err)
+	}
+	return applyNewsMergePatch(news, patch)
+}
+
+func TestApplyNewsMergePatch(t *testing.T) {
+	news := newTestPatchNews()
+	err := applyTestPatch(t, news, `{"title": "New title", "author": null, "categories": ["sport", "world"]}`)
+	if err != nil {
+		t.Fatalf("applyNewsMergePatch() error = %v", err)
+	}
+
+	if news.Title != "New title" {
+		t.Errorf("Title = %q, want %q", news.Title, "New title")
+	}
+	if news.Author != "" {
+		t.Errorf("Author = %q, want null to clear it", news.Author)
+	}
+	if !slices.Equal(news.Categories, []string{"sport", "world"}) {
+		t.Errorf("Categories = %v, want [sport world]", news.Categories)
+	}
+	if news.Content != "Content" || news.Status != "DRAFT" || len(news.Media) != 2 {
+		t.Errorf("fields missing from the patch were changed: %+v", news)
+	}
+}
+
+func TestApplyNewsMergePatchImages(t *testing.T) {
+	tests := []struct {
+		name    string
+		patch   string
+		want    []string
+		wantAlt string
+	}{
+		{"empty image_urls clears images", `{"image_urls": []}`, []string{}, ""},
+		{"null image_urls clears images", `{"image_urls": null}`, []string{}, ""},
+		{"empty media clears images", `{"media": []}`, []string{}, ""},
+		{"image_urls keeps known metadata", `{"image_urls": ["https://img.example.com/b.jpg"]}`, []string{"https://img.example.com/b.jpg"}, "B"},
+		{
+			"media takes precedence over image_urls",
+			`{"image_urls": ["https://img.example.com/a.jpg"], "media": [{"url": "https://img.example.com/c.jpg", "alt_text": "C"}]}`,
+			[]string{"https://img.example.com/c.jpg"}, "C",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			news := newTestPatchNews()
+			if err := applyTestPatch(t, news, tt.patch); err != nil {
+				t.Fatalf("applyNewsMergePatch() error = %v", err)
+			}
+
+			if !slices.Equal(news.ImageURLs, tt.want) {
+				t.Fatalf("ImageURLs = %v, want %v", news.ImageURLs, tt.want)
+			}
+			if len(news.Media) != len(tt.want) {
+				t.Fatalf("Media = %+v, want %d items", news.Media, len(tt.want))
+			}
+			if len(news.Media) > 0 && news.Media[0].AltText != tt.wantAlt {
+				t.Errorf("AltText = %q, want %q", news.Media[0].AltText, tt.wantAlt)
+			}
+		})
+	}
+}
+
+func TestApplyNewsMergePatchErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		patch string
+	}{
+		{"unknown key", `{"id": 5}`},
+		{"read-only key", `{"version": 2}`},
+		{"wrong type", `{"title": 5}`},
+		{"wrong element type", `{"categories": [1, 2]}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := applyTestPatch(t, newTestPatchNews(), tt.patch); err == nil {
+				t.Fatalf("applyNewsMergePatch(%s) error = nil, want an error", tt.patch)
+			}
+		})
+	}
+}
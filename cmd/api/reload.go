@@ -0,0 +1,113 @@
+package main
+
+import (
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/AnKlvy/news-service/internal/data/database"
+	"github.com/AnKlvy/news-service/internal/jsonlog"
+)
+
+// reloadable перечисляет настройки, которые применяются без перезапуска, и переносит
+// каждую из них из перечитанной конфигурации в действующую. Изменения остальных
+// настроек только записываются в журнал.
+var reloadable = map[string]func(dst, src *config){
+	"limiter-enabled":      func(dst, src *config) { dst.limiter.enabled = src.limiter.enabled },
+	"limiter-rps":          func(dst, src *config) { dst.limiter.rps = src.limiter.rps },
+	"limiter-burst":        func(dst, src *config) { dst.limiter.burst = src.limiter.burst },
+	"log-level":            func(dst, src *config) { dst.logLevel = src.logLevel },
+	"log-component-levels": func(dst, src *config) { dst.logComponentLevels = src.logComponentLevels },
+	"cache-ttl":            func(dst, src *config) { dst.cache.ttl = src.cache.ttl },
+	"cache-list-ttl":       func(dst, src *config) { dst.cache.listTTL = src.cache.listTTL },
+	"news-sort-fields":     func(dst, src *config) { dst.newsSortFields = src.newsSortFields },
+	"features":             func(dst, src *config) { dst.features = src.features },
+}
+
+// reloadResult перечисляет изменившиеся настройки: применённые сразу и те, что вступят
+// в силу только после перезапуска.
+type reloadResult struct {
+	Applied         []string `json:"applied"`
+	RestartRequired []string `json:"restart_required"`
+}
+
+// settings возвращает действующие настройки. В отличие от app.config, они меняются
+// при перезагрузке конфигурации.
+func (app *application) settings() *config {
+	return app.live.Load()
+}
+
+// applySettings делает настройки действующими и передаёт их компонентам, которые
+// хранят свою копию.
+func (app *application) applySettings(cfg *config) {
+	app.live.Store(cfg)
+
+	if level, err := jsonlog.ParseLevel(cfg.logLevel); err == nil {
+		app.logger.SetLevel(level)
+	}
+	if levels, err := parseComponentLevels(cfg.logComponentLevels); err == nil {
+		app.logger.SetComponentLevels(levels)
+	}
+	database.SetNewsSortFields(cfg.newsSortFields)
+	if app.newsCache != nil {
+		app.newsCache.SetTTL(cfg.cache.ttl, cfg.cache.listTTL)
+	}
+}
+
+// reloadConfig перечитывает файл настроек, переменные окружения и флаги запуска
+// и применяет изменения, которые не требуют перезапуска. Если новая конфигурация
+// не проходит проверку, действующие настройки не меняются.
+func (app *application) reloadConfig() (reloadResult, error) {
+	app.reloadMu.Lock()
+	defer app.reloadMu.Unlock()
+
+	next, _, err := loadConfig(os.Args[1:])
+	if err != nil {
+		return reloadResult{}, err
+	}
+
+	current := app.settings()
+	updated := *current
+	before, after := configValues(*current), configValues(next)
+
+	result := reloadResult{Applied: []string{}, RestartRequired: []string{}}
+	for _, name := range sortedKeys(after) {
+		if before[name] == after[name] {
+			continue
+		}
+		if apply, ok := reloadable[name]; ok {
+			apply(&updated, &next)
+			result.Applied = append(result.Applied, name)
+		} else {
+			result.RestartRequired = append(result.RestartRequired, name)
+		}
+	}
+	properties := map[string]string{
+		"component":        "config",
+		"applied":          strings.Join(result.Applied, ","),
+		"restart_required": strings.Join(result.RestartRequired, ","),
+	}
+	if len(result.RestartRequired) > 0 {
+		app.logger.PrintWarn("configuration reloaded, some changes require a restart", properties)
+	} else {
+		app.logger.PrintInfo("configuration reloaded", properties)
+	}
+	// Запись о перезагрузке делается до смены уровня журнала, иначе она может потеряться.
+	app.applySettings(&updated)
+	return result, nil
+}
+
+// reloadConfigHandler перезагружает конфигурацию так же, как сигнал SIGHUP. Маршрут
+// доступен только клиентам из -tls-admin-clients.
+func (app *application) reloadConfigHandler(w http.ResponseWriter, r *http.Request) {
+	result, err := app.reloadConfig()
+	if err != nil {
+		app.errorResponse(w, r, http.StatusUnprocessableEntity, err.Error())
+		return
+	}
+
+	err = app.writeJSON(w, http.StatusOK, envelope{"reload": result}, nil)
+	if err != nil {
+		app.serverErrorResponse(w, r, err)
+	}
+}
@@ -11,12 +11,41 @@ func (app *application) routes() http.Handler {
 	router.NotFound = http.HandlerFunc(app.notFoundResponse)
 	router.MethodNotAllowed = http.HandlerFunc(app.methodNotAllowedResponse)
 	router.HandlerFunc(http.MethodGet, "/v1/healthcheck", app.healthcheckHandler)
-	//router.HandlerFunc(http.MethodGet, "/v1/news", app.listNewsHandler)
-	//router.HandlerFunc(http.MethodPost, "/v1/news", app.createNewsHandler)
-	//router.HandlerFunc(http.MethodGet, "/v1/news/:id", app.showNewsHandler)
-	//router.HandlerFunc(http.MethodPatch, "/v1/news/:id", app.updateNewsHandler)
-	//router.HandlerFunc(http.MethodDelete, "/v1/news/:id", app.deleteNewsHandler)
-
-	// Оборачиваем роутер в middleware rateLimit().
-	return app.recoverPanic(app.rateLimit(router))
+	router.HandlerFunc(http.MethodGet, "/v1/admin/stats", app.requireAdmin(app.showStatsHandler))
+	router.HandlerFunc(http.MethodPost, "/v1/admin/config/reload", app.requireAdmin(app.reloadConfigHandler))
+	router.HandlerFunc(http.MethodGet, "/v1/admin/log-level", app.requireAdmin(app.showLogLevelHandler))
+	router.HandlerFunc(http.MethodPut, "/v1/admin/log-level", app.requireAdmin(app.updateLogLevelHandler))
+	router.HandlerFunc(http.MethodGet, "/v1/news", app.listNewsHandler)
+	router.HandlerFunc(http.MethodPost, "/v1/news", app.createNewsHandler)
+	router.HandlerFunc(http.MethodPost, "/v1/news/batch", app.batchCreateNewsHandler)
+	router.HandlerFunc(http.MethodPost, "/v1/news/bulk", app.bulkNewsHandler)
+	router.HandlerFunc(http.MethodGet, "/v1/bulk-jobs/:id", app.showBulkJobHandler)
+	router.HandlerFunc(http.MethodGet, "/v1/news/:id", app.showNewsOrStaticHandler)
+	router.HandlerFunc(http.MethodPatch, "/v1/news/:id", app.updateNewsHandler)
+	router.HandlerFunc(http.MethodDelete, "/v1/news/:id", app.deleteNewsHandler)
+
+	router.HandlerFunc(http.MethodGet, "/v1/webhooks", app.requireAdmin(app.listWebhooksHandler))
+	router.HandlerFunc(http.MethodPost, "/v1/webhooks", app.requireAdmin(app.createWebhookHandler))
+	router.HandlerFunc(http.MethodGet, "/v1/webhooks/:id", app.requireAdmin(app.showWebhookHandler))
+	router.HandlerFunc(http.MethodPatch, "/v1/webhooks/:id", app.requireAdmin(app.updateWebhookHandler))
+	router.HandlerFunc(http.MethodDelete, "/v1/webhooks/:id", app.requireAdmin(app.deleteWebhookHandler))
+	router.HandlerFunc(http.MethodGet, "/v1/webhooks/:id/deliveries", app.requireAdmin(app.listWebhookDeliveriesHandler))
+	router.HandlerFunc(http.MethodPost, "/v1/webhooks/:id/deliveries/:delivery_id/replay", app.requireAdmin(app.replayWebhookDeliveryHandler))
+
+	router.HandlerFunc(http.MethodPost, "/v1/media", app.uploadMediaHandler)
+	router.HandlerFunc(http.MethodGet, "/media/:name", app.serveMediaHandler)
+
+	router.HandlerFunc(http.MethodGet, "/feeds/rss.xml", app.requireFeature(featureFeeds, app.rssFeedHandler))
+	router.HandlerFunc(http.MethodGet, "/feeds/atom.xml", app.requireFeature(featureFeeds, app.atomFeedHandler))
+	router.HandlerFunc(http.MethodGet, "/feeds/categories/:category/rss.xml", app.requireFeature(featureFeeds, app.rssFeedHandler))
+	router.HandlerFunc(http.MethodGet, "/feeds/categories/:category/atom.xml", app.requireFeature(featureFeeds, app.atomFeedHandler))
+	router.HandlerFunc(http.MethodGet, "/feeds/authors/:author/rss.xml", app.requireFeature(featureFeeds, app.rssFeedHandler))
+	router.HandlerFunc(http.MethodGet, "/feeds/authors/:author/atom.xml", app.requireFeature(featureFeeds, app.atomFeedHandler))
+
+	router.HandlerFunc(http.MethodGet, "/sitemap.xml", app.requireFeature(featureSitemaps, app.sitemapIndexHandler))
+	router.HandlerFunc(http.MethodGet, "/sitemaps/articles/:file", app.requireFeature(featureSitemaps, app.articlesSitemapHandler))
+	router.HandlerFunc(http.MethodGet, "/sitemaps/google-news.xml", app.requireFeature(featureSitemaps, app.googleNewsSitemapHandler))
+
+	// Оборачиваем роутер в middleware rateLimit() и identifyClient().
+	return app.recoverPanic(app.identifyClient(app.rateLimit(router)))
 }
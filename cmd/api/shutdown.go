@@ -0,0 +1,104 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/AnKlvy/news-service/internal/jsonlog"
+)
+
+// waitForShutdown блокирует до получения сигнала завершения или до ошибки одного из
+// серверов и возвращает эту ошибку. На SIGHUP вызывается reload, и ожидание продолжается.
+func waitForShutdown(logger *jsonlog.Logger, serveErrors <-chan error, reload func()) error {
+	// Создаём канал, в который пойдут сигналы ОС
+	quit := make(chan os.Signal, 1)
+	signal.Notify(quit, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(quit)
+	// SIGHUP не завершает работу, а перечитывает настройки.
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	defer signal.Stop(hup)
+
+	for {
+		select {
+		case <-hup:
+			reload()
+		case sig := <-quit:
+			logger.PrintInfo("received shutdown signal", map[string]string{"component": "shutdown", "signal": sig.String()})
+			return nil
+		case err := <-serveErrors:
+			return err
+		}
+	}
+}
+
+// shutdownStep — одна стадия остановки сервиса.
+type shutdownStep struct {
+	name string
+	stop func(ctx context.Context) error
+}
+
+// shutdownCoordinator останавливает компоненты сервиса в порядке, обратном порядку
+// регистрации: сначала серверы перестают принимать запросы и завершают текущие,
+// затем останавливаются фоновые воркеры, и последним закрывается пул подключений
+// к базе данных. На всю остановку отводится timeout.
+type shutdownCoordinator struct {
+	logger  *jsonlog.Logger
+	timeout time.Duration
+	steps   []shutdownStep
+}
+
+func newShutdownCoordinator(logger *jsonlog.Logger, timeout time.Duration) *shutdownCoordinator {
+	return &shutdownCoordinator{logger: logger, timeout: timeout}
+}
+
+// add регистрирует стадию, которая сама прекращает ожидание при отмене ctx.
+func (c *shutdownCoordinator) add(name string, stop func(ctx context.Context) error) {
+	c.steps = append(c.steps, shutdownStep{name: name, stop: stop})
+}
+
+// addFunc регистрирует стадию для компонента, метод остановки которого не принимает
+// контекст. Если он не укладывается в отведённое время, остановка переходит к
+// следующей стадии, не дожидаясь его.
+func (c *shutdownCoordinator) addFunc(name string, stop func()) {
+	c.add(name, func(ctx context.Context) error {
+		done := make(chan struct{})
+		go func() {
+			stop()
+			close(done)
+		}()
+
+		select {
+		case <-done:
+			return nil
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	})
+}
+
+// shutdown выполняет все стадии и возвращает их ошибки. Стадии после истечения
+// времени всё равно запускаются, чтобы освободить то, что можно освободить быстро.
+func (c *shutdownCoordinator) shutdown() error {
+	ctx, cancel := context.WithTimeout(context.Background(), c.timeout)
+	defer cancel()
+
+	var errs []error
+	for i := len(c.steps) - 1; i >= 0; i-- {
+		step := c.steps[i]
+		properties := map[string]string{"component": "shutdown", "step": step.name}
+
+		if err := step.stop(ctx); err != nil {
+			c.logger.PrintError(err, properties)
+			errs = append(errs, fmt.Errorf("%s: %w", step.name, err))
+			continue
+		}
+		c.logger.PrintInfo("stopped", properties)
+	}
+	return errors.Join(errs...)
+}
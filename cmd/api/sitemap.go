@@ -0,0 +1,228 @@
+package main
+
+import (
+	"encoding/xml"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/AnKlvy/news-service/internal/data/database"
+	"github.com/julienschmidt/httprouter"
+)
+
+const (
+	// Протокол sitemaps.org допускает не более 50 000 адресов в одном файле.
+	sitemapMaxURLs = 50_000
+	// Google News учитывает только статьи за последние двое суток и не более 1000 адресов.
+	googleNewsWindow  = 48 * time.Hour
+	googleNewsMaxURLs = 1000
+)
+
+// Структуры для карт сайта (https://www.sitemaps.org/protocol.html) и расширений
+// Google для изображений и новостей.
+type sitemapIndex struct {
+	XMLName  xml.Name     `xml:"sitemapindex"`
+	XMLNS    string       `xml:"xmlns,attr"`
+	Sitemaps []sitemapRef `xml:"sitemap"`
+}
+
+type sitemapRef struct {
+	Loc     string `xml:"loc"`
+	LastMod string `xml:"lastmod,omitempty"`
+}
+
+type sitemapURLSet struct {
+	XMLName xml.Name     `xml:"urlset"`
+	XMLNS   string       `xml:"xmlns,attr"`
+	ImageNS string       `xml:"xmlns:image,attr"`
+	NewsNS  string       `xml:"xmlns:news,attr,omitempty"`
+	URLs    []sitemapURL `xml:"url"`
+}
+
+type sitemapURL struct {
+	Loc     string         `xml:"loc"`
+	LastMod string         `xml:"lastmod,omitempty"`
+	Images  []sitemapImage `xml:"image:image"`
+	News    *sitemapNews   `xml:"news:news,omitempty"`
+}
+
+type sitemapImage struct {
+	Loc string `xml:"image:loc"`
+}
+
+type sitemapNews struct {
+	Publication     sitemapPublication `xml:"news:publication"`
+	PublicationDate string             `xml:"news:publication_date"`
+	Title           string             `xml:"news:title"`
+}
+
+type sitemapPublication struct {
+	Name     string `xml:"news:name"`
+	Language string `xml:"news:language"`
+}
+
+// sitemapIndexHandler отдаёт индекс карт сайта: список постраничных карт со всеми
+// опубликованными статьями и отдельную карту для Google News.
+func (app *application) sitemapIndexHandler(w http.ResponseWriter, r *http.Request) {
+	// Адреса в карте строятся от базового адреса, поэтому он входит в ключ кэша
+	// так же, как для лент.
+	baseURL := app.baseURL(r)
+	key := baseURL + "|sitemap|index"
+	entry, ok := app.feeds.get(key)
+	if !ok {
+		// Для каждой страницы нужна только дата последнего изменения её статей.
+		pages, err := app.models.News.PublishedPages(sitemapMaxURLs)
+		if err != nil {
+			app.serverErrorResponse(w, r, err)
+			return
+		}
+
+		index := sitemapIndex{XMLNS: "http://www.sitemaps.org/schemas/sitemap/0.9"}
+
+		for i, lastMod := range pages {
+			index.Sitemaps = append(index.Sitemaps, sitemapRef{
+				Loc:     fmt.Sprintf("%s/sitemaps/articles/%d.xml", baseURL, i+1),
+				LastMod: lastMod.UTC().Format(time.RFC3339),
+			})
+		}
+		index.Sitemaps = append(index.Sitemaps, sitemapRef{
+			Loc: baseURL + "/sitemaps/google-news.xml",
+		})
+
+		body, err := marshalXML(index)
+		if err != nil {
+			app.serverErrorResponse(w, r, err)
+			return
+		}
+		entry = app.feeds.set(key, body, "application/xml; charset=utf-8", time.Time{})
+	}
+
+	app.serveCachedDocument(w, r, entry)
+}
+
+// articlesSitemapHandler отдаёт одну страницу карты сайта (до 50 000 статей) вместе
+// с изображениями статей. Имя файла в маршруте имеет вид "<номер страницы>.xml".
+func (app *application) articlesSitemapHandler(w http.ResponseWriter, r *http.Request) {
+	file := httprouter.ParamsFromContext(r.Context()).ByName("file")
+	page, err := strconv.Atoi(strings.TrimSuffix(file, ".xml"))
+	if err != nil || page < 1 || !strings.HasSuffix(file, ".xml") {
+		app.notFoundResponse(w, r)
+		return
+	}
+
+	baseURL := app.baseURL(r)
+	key := baseURL + "|sitemap|articles|" + strconv.Itoa(page)
+	entry, ok := app.feeds.get(key)
+	if !ok {
+		// Номер страницы проверяется до выборки статей: иначе произвольные номера
+		// переполняли бы смещение в запросе и заполняли кэш пустыми страницами.
+		pages, err := app.models.News.PublishedPages(sitemapMaxURLs)
+		if err != nil {
+			app.serverErrorResponse(w, r, err)
+			return
+		}
+		// Первая страница существует всегда, даже если статей ещё нет.
+		if page > max(len(pages), 1) {
+			app.notFoundResponse(w, r)
+			return
+		}
+
+		news, _, err := app.models.News.GetPublished(time.Time{}, database.Filters{
+			Page:         page,
+			PageSize:     sitemapMaxURLs,
+			Sort:         "id",
+			SortSafelist: []string{"id"},
+		})
+		if err != nil {
+			app.serverErrorResponse(w, r, err)
+			return
+		}
+		urlSet := sitemapURLSet{
+			XMLNS:   "http://www.sitemaps.org/schemas/sitemap/0.9",
+			ImageNS: "http://www.google.com/schemas/sitemap-image/1.1",
+		}
+
+		var lastModified time.Time
+		for _, n := range news {
+			if n.UpdatedAt.After(lastModified) {
+				lastModified = n.UpdatedAt
+			}
+			urlSet.URLs = append(urlSet.URLs, newSitemapURL(baseURL, n))
+		}
+
+		body, err := marshalXML(urlSet)
+		if err != nil {
+			app.serverErrorResponse(w, r, err)
+			return
+		}
+		entry = app.feeds.set(key, body, "application/xml; charset=utf-8", lastModified)
+	}
+
+	app.serveCachedDocument(w, r, entry)
+}
+
+// googleNewsSitemapHandler отдаёт карту Google News со статьями, опубликованными
+// за последние 48 часов.
+func (app *application) googleNewsSitemapHandler(w http.ResponseWriter, r *http.Request) {
+	baseURL := app.baseURL(r)
+	key := baseURL + "|sitemap|google-news"
+	entry, ok := app.feeds.get(key)
+	if !ok {
+		news, _, err := app.models.News.GetPublished(time.Now().Add(-googleNewsWindow), database.Filters{
+			Page:         1,
+			PageSize:     googleNewsMaxURLs,
+			Sort:         "-published_at",
+			SortSafelist: []string{"-published_at"},
+		})
+		if err != nil {
+			app.serverErrorResponse(w, r, err)
+			return
+		}
+
+		urlSet := sitemapURLSet{
+			XMLNS:   "http://www.sitemaps.org/schemas/sitemap/0.9",
+			ImageNS: "http://www.google.com/schemas/sitemap-image/1.1",
+			NewsNS:  "http://www.google.com/schemas/sitemap-news/0.9",
+		}
+
+		var lastModified time.Time
+		for _, n := range news {
+			if n.UpdatedAt.After(lastModified) {
+				lastModified = n.UpdatedAt
+			}
+			u := newSitemapURL(baseURL, n)
+			u.News = &sitemapNews{
+				Publication: sitemapPublication{
+					Name:     app.config.sitemap.publicationName,
+					Language: app.config.sitemap.language,
+				},
+				PublicationDate: publicationDate(n).UTC().Format(time.RFC3339),
+				Title:           n.Title,
+			}
+			urlSet.URLs = append(urlSet.URLs, u)
+		}
+
+		body, err := marshalXML(urlSet)
+		if err != nil {
+			app.serverErrorResponse(w, r, err)
+			return
+		}
+		entry = app.feeds.set(key, body, "application/xml; charset=utf-8", lastModified)
+	}
+
+	app.serveCachedDocument(w, r, entry)
+}
+
+// newSitemapURL формирует запись карты сайта для статьи вместе с её изображениями.
+func newSitemapURL(baseURL string, n *database.News) sitemapURL {
+	u := sitemapURL{
+		Loc:     newsURL(baseURL, n.ID),
+		LastMod: n.UpdatedAt.UTC().Format(time.RFC3339),
+	}
+	for _, imageURL := range n.ImageURLs {
+		u.Images = append(u.Images, sitemapImage{Loc: imageURL})
+	}
+	return u
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"context"
+	"encoding/xml"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/AnKlvy/news-service/internal/data/database"
+	"github.com/julienschmidt/httprouter"
+)
+
+func TestSitemapIndexCacheKeyIncludesHost(t *testing.T) {
+	app := newTestFeedApp()
+
+	get := func(host string) string {
+		r := httptest.NewRequest(http.MethodGet, "/sitemap.xml", nil)
+		r.Host = host
+		rr := httptest.NewRecorder()
+		app.sitemapIndexHandler(rr, r)
+		if rr.Code != http.StatusOK {
+			t.Fatalf("status = %d, want %d", rr.Code, http.StatusOK)
+		}
+		return rr.Body.String()
+	}
+
+	if body := get("evil.example"); !strings.Contains(body, "http://evil.example/sitemaps/google-news.xml") {
+		t.Fatalf("sitemap index for evil.example does not link to its host: %s", body)
+	}
+	if body := get("news.example"); strings.Contains(body, "evil.example") {
+		t.Fatalf("sitemap index cached for a forged Host was served to another client: %s", body)
+	}
+}
+
+// sitemapNewsStore отдаёт заданные страницы карты сайта и считает выборки статей.
+type sitemapNewsStore struct {
+	database.MockNewsModel
+	pages   []time.Time
+	fetched int
+}
+
+func (s *sitemapNewsStore) PublishedPages(pageSize int) ([]time.Time, error) {
+	return s.pages, nil
+}
+
+func (s *sitemapNewsStore) GetPublished(since time.Time, filters database.Filters) ([]*database.News, database.Metadata, error) {
+	s.fetched++
+	return []*database.News{{ID: 1, UpdatedAt: s.pages[0]}}, database.Metadata{}, nil
+}
+
+func newTestSitemapApp(pages ...time.Time) (*application, *sitemapNewsStore) {
+	app := newTestFeedApp()
+	store := &sitemapNewsStore{pages: pages}
+	app.models.News = store
+	return app, store
+}
+
+func TestSitemapIndexLastMod(t *testing.T) {
+	first := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+	second := time.Date(2024, 3, 2, 12, 30, 0, 0, time.UTC)
+	app, _ := newTestSitemapApp(first, second)
+
+	rr := httptest.NewRecorder()
+	app.sitemapIndexHandler(rr, httptest.NewRequest(http.MethodGet, "/sitemap.xml", nil))
+
+	var index sitemapIndex
+	if err := xml.Unmarshal(rr.Body.Bytes(), &index); err != nil {
+		t.Fatalf("invalid sitemap index: %v", err)
+	}
+	want := []sitemapRef{
+		{Loc: "http://example.com/sitemaps/articles/1.xml", LastMod: "2024-03-01T10:00:00Z"},
+		{Loc: "http://example.com/sitemaps/articles/2.xml", LastMod: "2024-03-02T12:30:00Z"},
+		{Loc: "http://example.com/sitemaps/google-news.xml"},
+	}
+	if !slices.Equal(index.Sitemaps, want) {
+		t.Fatalf("sitemaps = %+v, want %+v", index.Sitemaps, want)
+	}
+}
+
+func TestArticlesSitemapRejectsPagesOutOfRange(t *testing.T) {
+	app, store := newTestSitemapApp(time.Now(), time.Now())
+
+	get := func(file string) int {
+		r := httptest.NewRequest(http.MethodGet, "/sitemaps/articles/"+file, nil)
+		params := httprouter.Params{{Key: "file", Value: file}}
+		r = r.WithContext(context.WithValue(r.Context(), httprouter.ParamsKey, params))
+		rr := httptest.NewRecorder()
+		app.articlesSitemapHandler(rr, r)
+		return rr.Code
+	}
+
+	for _, file := range []string{"3.xml", "9223372036854775807.xml", "0.xml", "1.txt"} {
+		if code := get(file); code != http.StatusNotFound {
+			t.Errorf("%s status = %d, want %d", file, code, http.StatusNotFound)
+		}
+	}
+	if store.fetched != 0 {
+		t.Fatalf("articles were fetched %d times for pages out of range", store.fetched)
+	}
+
+	if code := get("2.xml"); code != http.StatusOK {
+		t.Fatalf("2.xml status = %d, want %d", code, http.StatusOK)
+	}
+}
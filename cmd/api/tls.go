@@ -0,0 +1,155 @@
+package main
+
+import (
+	"context"
+	"crypto/x509"
+	"net/http"
+	"path"
+	"strings"
+
+	"github.com/AnKlvy/news-service/internal/certs"
+	news_proto "github.com/AnKlvy/news-service/protobuf/gen_news"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// identifyClient сохраняет в контексте запроса имя клиента из его сертификата,
+// отклоняет клиентов, которых нет в списке разрешённых, и записывает изменяющие
+// запросы в журнал аудита. Без взаимной аутентификации запрос передаётся дальше
+// без изменений.
+func (app *application) identifyClient(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if app.config.tls.clientCAFile == "" || r.TLS == nil || len(r.TLS.PeerCertificates) == 0 {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		cert := r.TLS.PeerCertificates[0]
+		if !certs.Permitted(cert, app.config.tls.allowedClients) {
+			app.forbiddenResponse(w, r)
+			return
+		}
+
+		caller := certs.Identity(cert)
+		r = r.WithContext(certs.NewContext(r.Context(), caller))
+		if r.Method != http.MethodGet && r.Method != http.MethodHead && r.Method != http.MethodOptions {
+			app.audit(caller, r.Method, r.URL.String())
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// requireAdmin пропускает к административным маршрутам только клиентов, чьи
+// сертификаты перечислены в -tls-admin-clients. Если список пуст или взаимная
+// аутентификация не настроена, эти маршруты недоступны никому.
+func (app *application) requireAdmin(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if certs.FromContext(r.Context()) == "" {
+			app.clientCertificateRequiredResponse(w, r)
+			return
+		}
+		if !app.adminClient(r.TLS.PeerCertificates[0]) {
+			app.forbiddenResponse(w, r)
+			return
+		}
+		next(w, r)
+	}
+}
+
+func (app *application) adminClient(cert *x509.Certificate) bool {
+	return len(app.config.tls.adminClients) > 0 && certs.Permitted(cert, app.config.tls.adminClients)
+}
+
+// audit записывает в журнал, кто и какую операцию выполнил.
+func (app *application) audit(caller, method, target string) {
+	app.logger.PrintInfo("request", map[string]string{
+		"component": "audit",
+		"caller":    caller,
+		"method":    method,
+		"target":    target,
+	})
+}
+
+// grpcClientInterceptors — то же, что identifyClient, для вызовов gRPC.
+func (app *application) grpcClientInterceptors() []grpc.ServerOption {
+	unary := func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		ctx, err := app.identifyGRPCClient(ctx, info.FullMethod)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+	stream := func(srv any, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := app.identifyGRPCClient(ss.Context(), info.FullMethod)
+		if err != nil {
+			return err
+		}
+		return handler(srv, &identifiedStream{ServerStream: ss, ctx: ctx})
+	}
+	return []grpc.ServerOption{grpc.ChainUnaryInterceptor(unary), grpc.ChainStreamInterceptor(stream)}
+}
+
+func (app *application) identifyGRPCClient(ctx context.Context, fullMethod string) (context.Context, error) {
+	var cert *x509.Certificate
+	if app.config.tls.clientCAFile != "" {
+		cert = peerCertificate(ctx)
+	}
+	if cert == nil {
+		if adminMethod(fullMethod) {
+			return nil, status.Error(codes.Unauthenticated, "this method requires a client certificate")
+		}
+		return ctx, nil
+	}
+	if !certs.Permitted(cert, app.config.tls.allowedClients) ||
+		adminMethod(fullMethod) && !app.adminClient(cert) {
+		return nil, status.Error(codes.PermissionDenied, "client certificate is not permitted to call this method")
+	}
+
+	caller := certs.Identity(cert)
+	if !readOnlyMethod(fullMethod) {
+		app.audit(caller, "grpc", fullMethod)
+	}
+	return certs.NewContext(ctx, caller), nil
+}
+
+func peerCertificate(ctx context.Context) *x509.Certificate {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return nil
+	}
+	info, ok := p.AuthInfo.(credentials.TLSInfo)
+	if !ok || len(info.State.PeerCertificates) == 0 {
+		return nil
+	}
+	return info.State.PeerCertificates[0]
+}
+
+// readOnlyMethod определяет по имени метода gRPC, что он ничего не изменяет.
+func readOnlyMethod(fullMethod string) bool {
+	name := path.Base(fullMethod)
+	for _, prefix := range []string{"Get", "List", "Show", "Watch", "BatchGet", "Check"} {
+		if strings.HasPrefix(name, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// adminMethod определяет методы gRPC, доступные только администраторам: управление
+// webhook позволяет отправлять запросы от имени сервера и читать историю доставок.
+func adminMethod(fullMethod string) bool {
+	return strings.HasPrefix(fullMethod, "/"+news_proto.WebhookService_ServiceDesc.ServiceName+"/")
+}
+
+// identifiedStream подменяет контекст потока контекстом с именем клиента.
+type identifiedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *identifiedStream) Context() context.Context {
+	return s.ctx
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/AnKlvy/news-service/internal/certs"
+	"github.com/AnKlvy/news-service/internal/jsonlog"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func newTestAdminApp(adminClients ...string) *application {
+	app := &application{logger: jsonlog.New(&strings.Builder{}, jsonlog.LevelOff)}
+	app.config.tls.clientCAFile = "ca.pem"
+	app.config.tls.adminClients = adminClients
+	return app
+}
+
+func adminRequest(commonName string) *http.Request {
+	r := httptest.NewRequest(http.MethodGet, "/v1/webhooks", nil)
+	if commonName == "" {
+		return r
+	}
+	cert := &x509.Certificate{Subject: pkix.Name{CommonName: commonName}}
+	r.TLS = &tls.ConnectionState{PeerCertificates: []*x509.Certificate{cert}}
+	return r.WithContext(certs.NewContext(r.Context(), certs.Identity(cert)))
+}
+
+func TestRequireAdmin(t *testing.T) {
+	tests := []struct {
+		name         string
+		adminClients []string
+		commonName   string
+		want         int
+	}{
+		{"no client certificate", []string{"ops"}, "", http.StatusUnauthorized},
+		{"not an admin", []string{"ops"}, "editor", http.StatusForbidden},
+		{"admin list is empty", nil, "ops", http.StatusForbidden},
+		{"admin", []string{"ops"}, "ops", http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := newTestAdminApp(tt.adminClients...)
+			handler := app.requireAdmin(func(w http.ResponseWriter, r *http.Request) {})
+
+			rr := httptest.NewRecorder()
+			handler(rr, adminRequest(tt.commonName))
+			if rr.Code != tt.want {
+				t.Fatalf("status = %d, want %d", rr.Code, tt.want)
+			}
+		})
+	}
+}
+
+func TestIdentifyGRPCClientRequiresCertificateForAdminMethods(t *testing.T) {
+	app := newTestAdminApp("ops")
+
+	_, err := app.identifyGRPCClient(context.Background(), "/data.WebhookService/ListWebhooks")
+	if status.Code(err) != codes.Unauthenticated {
+		t.Fatalf("admin method error = %v, want code %s", err, codes.Unauthenticated)
+	}
+
+	if _, err := app.identifyGRPCClient(context.Background(), "/data.NewsService/ListNewsHandler"); err != nil {
+		t.Fatalf("news method error = %v, want nil", err)
+	}
+}
+
+func TestAdminRoutesRequireClientCertificate(t *testing.T) {
+	app := newTestAdminApp("ops")
+	app.live.Store(&app.config)
+	routes := app.routes()
+
+	tests := []struct {
+		method string
+		path   string
+	}{
+		{http.MethodGet, "/v1/admin/stats"},
+		{http.MethodPost, "/v1/admin/config/reload"},
+		{http.MethodGet, "/v1/admin/log-level"},
+		{http.MethodPut, "/v1/admin/log-level"},
+		{http.MethodGet, "/v1/webhooks"},
+	}
+
+	for _, tt := range tests {
+		r := httptest.NewRequest(tt.method, tt.path, nil)
+		rr := httptest.NewRecorder()
+		routes.ServeHTTP(rr, r)
+		if rr.Code != http.StatusUnauthorized {
+			t.Errorf("%s %s status = %d, want %d", tt.method, tt.path, rr.Code, http.StatusUnauthorized)
+		}
+	}
+}
@@ -0,0 +1,129 @@
+package bulk
+
+import (
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/AnKlvy/news-service/internal/data/database"
+	"github.com/AnKlvy/news-service/internal/jsonlog"
+)
+
+// Config — настройки исполнителя массовых заданий.
+type Config struct {
+	// Количество новостей, изменяемых в одной транзакции.
+	ChunkSize int
+	// Как часто проверяется наличие новых заданий.
+	PollInterval time.Duration
+}
+
+// Задание закрепляется за исполнителем на это время и продлевается после каждой порции.
+// Если экземпляр сервиса остановится, задание подхватит другой экземпляр.
+const lease = time.Minute
+
+// Runner выполняет массовые операции над новостями в фоне.
+type Runner struct {
+	models database.Models
+	logger *jsonlog.Logger
+	config Config
+
+	stop chan struct{}
+	wg   sync.WaitGroup
+}
+
+func NewRunner(models database.Models, logger *jsonlog.Logger, config Config) *Runner {
+	if config.ChunkSize < 1 {
+		config.ChunkSize = 1
+	}
+	return &Runner{
+		models: models,
+		logger: logger,
+		config: config,
+		stop:   make(chan struct{}),
+	}
+}
+
+// Start запускает фоновую горутину исполнителя.
+func (r *Runner) Start() {
+	r.wg.Add(1)
+	go func() {
+		defer r.wg.Done()
+		for {
+			job, err := r.models.BulkJobs.Claim(lease)
+			if err != nil {
+				r.logger.PrintError(err, map[string]string{"component": "bulk"})
+			}
+			if job != nil {
+				r.run(job)
+				continue
+			}
+
+			select {
+			case <-r.stop:
+				return
+			case <-time.After(r.config.PollInterval):
+			}
+		}
+	}()
+}
+
+// Shutdown останавливает исполнитель после текущей порции. Незавершённое задание
+// продолжится после перезапуска.
+func (r *Runner) Shutdown() {
+	close(r.stop)
+	r.wg.Wait()
+}
+
+func (r *Runner) run(job *database.BulkJob) {
+	properties := map[string]string{
+		"component": "bulk",
+		"job_id":    strconv.FormatInt(job.ID, 10),
+		"action":    job.Operation.Action,
+	}
+	r.logger.PrintInfo("bulk job started", properties)
+
+	for {
+		select {
+		case <-r.stop:
+			return
+		default:
+		}
+
+		chunk, err := r.models.News.BulkApply(job.Operation, job.LastID, r.config.ChunkSize)
+		if err != nil {
+			job.Status = database.JobFailed
+			job.Error = err.Error()
+			r.logger.PrintError(err, properties)
+			r.save(job, properties)
+			return
+		}
+
+		job.LastID = chunk.LastID
+		job.Processed += chunk.Processed
+		job.Affected += chunk.Affected
+		// Новости, созданные после запуска задания, тоже могут попасть под фильтр.
+		if job.Processed > job.Matched {
+			job.Matched = job.Processed
+		}
+		if chunk.Done {
+			job.Status = database.JobCompleted
+		}
+		r.save(job, properties)
+
+		if chunk.Done {
+			r.logger.PrintInfo("bulk job completed", map[string]string{
+				"component": "bulk",
+				"job_id":    strconv.FormatInt(job.ID, 10),
+				"processed": strconv.FormatInt(job.Processed, 10),
+				"affected":  strconv.FormatInt(job.Affected, 10),
+			})
+			return
+		}
+	}
+}
+
+func (r *Runner) save(job *database.BulkJob, properties map[string]string) {
+	if err := r.models.BulkJobs.SaveProgress(job, lease); err != nil {
+		r.logger.PrintError(err, properties)
+	}
+}
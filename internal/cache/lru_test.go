@@ -0,0 +1,122 @@
+package cache
+
+import (
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestLRUEvictsLeastRecentlyUsed(t *testing.T) {
+	c := NewLRU[string, int](2, time.Minute)
+	c.Set("a", 1)
+	c.Set("b", 2)
+
+	// Обращение к "a" делает самой старой запись "b".
+	if _, ok := c.Get("a"); !ok {
+		t.Fatal(`Get("a") missed`)
+	}
+	c.Set("c", 3)
+
+	if _, ok := c.Get("b"); ok {
+		t.Error(`Get("b") hit, want it evicted`)
+	}
+	for key, want := range map[string]int{"a": 1, "c": 3} {
+		if got, ok := c.Get(key); !ok || got != want {
+			t.Errorf("Get(%q) = %d, %t, want %d, true", key, got, ok, want)
+		}
+	}
+	if c.Len() != 2 {
+		t.Errorf("Len() = %d, want 2", c.Len())
+	}
+}
+
+func TestLRUSetReplacesValue(t *testing.T) {
+	c := NewLRU[string, int](2, time.Minute)
+	c.Set("a", 1)
+	c.Set("a", 2)
+
+	if got, _ := c.Get("a"); got != 2 {
+		t.Errorf(`Get("a") = %d, want 2`, got)
+	}
+	if c.Len() != 1 {
+		t.Errorf("Len() = %d, want 1", c.Len())
+	}
+}
+
+func TestLRUExpiresEntries(t *testing.T) {
+	c := NewLRU[string, int](2, time.Millisecond)
+	c.Set("a", 1)
+	time.Sleep(5 * time.Millisecond)
+
+	if _, ok := c.Get("a"); ok {
+		t.Fatal(`Get("a") hit after TTL`)
+	}
+	if c.Len() != 0 {
+		t.Errorf("Len() = %d, want the expired entry removed", c.Len())
+	}
+
+	c.SetTTL(time.Minute)
+	c.Set("b", 2)
+	time.Sleep(5 * time.Millisecond)
+	if _, ok := c.Get("b"); !ok {
+		t.Error(`Get("b") missed after SetTTL extended the TTL`)
+	}
+	if c.TTL() != time.Minute {
+		t.Errorf("TTL() = %v, want %v", c.TTL(), time.Minute)
+	}
+}
+
+func TestLRUDeleteAndPurge(t *testing.T) {
+	c := NewLRU[int, string](3, time.Minute)
+	c.Set(1, "a")
+	c.Set(2, "b")
+	c.Set(3, "c")
+
+	c.Delete(2)
+	if _, ok := c.Get(2); ok {
+		t.Error("Get(2) hit after Delete")
+	}
+
+	c.Purge()
+	if c.Len() != 0 {
+		t.Errorf("Len() = %d after Purge, want 0", c.Len())
+	}
+	c.Set(4, "d")
+	if got, ok := c.Get(4); !ok || got != "d" {
+		t.Errorf(`Get(4) = %q, %t after Purge, want "d", true`, got, ok)
+	}
+}
+
+func TestLRUZeroSizeStoresNothing(t *testing.T) {
+	c := NewLRU[string, int](0, time.Minute)
+	c.Set("a", 1)
+	if _, ok := c.Get("a"); ok {
+		t.Error(`Get("a") hit in a zero-size cache`)
+	}
+}
+
+func TestLRUConcurrentAccess(t *testing.T) {
+	c := NewLRU[string, int](16, time.Minute)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 1000; j++ {
+				key := strconv.Itoa(j % 32)
+				c.Set(key, j)
+				c.Get(key)
+				if j%100 == 0 {
+					c.Delete(key)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	if c.Len() > 16 {
+		t.Errorf("Len() = %d, want at most 16", c.Len())
+	}
+}
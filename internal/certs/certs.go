@@ -0,0 +1,251 @@
+// Package certs загружает сертификаты TLS для серверов сервиса, перечитывает их при
+// изменении файлов и определяет клиента по его сертификату при взаимной аутентификации.
+package certs
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/AnKlvy/news-service/internal/jsonlog"
+)
+
+// Config — настройки TLS.
+type Config struct {
+	// Сертификат сервера и его закрытый ключ в формате PEM.
+	CertFile string
+	KeyFile  string
+	// Сертификаты удостоверяющих центров, которыми подписаны сертификаты клиентов.
+	// Если файл задан, клиент обязан предъявить сертификат (mutual TLS).
+	ClientCAFile string
+	// Минимальная версия протокола, например tls.VersionTLS12.
+	MinVersion uint16
+	// Как часто проверяется, не изменились ли файлы на диске.
+	ReloadInterval time.Duration
+}
+
+// ParseVersion возвращает версию TLS по её записи вида "1.2" или "1.3". Более старые
+// версии не поддерживаются.
+func ParseVersion(s string) (uint16, error) {
+	switch s {
+	case "1.2":
+		return tls.VersionTLS12, nil
+	case "1.3":
+		return tls.VersionTLS13, nil
+	default:
+		return 0, fmt.Errorf("unsupported TLS version %q", s)
+	}
+}
+
+// Reloader хранит текущие сертификаты и перечитывает их, когда файлы меняются на диске.
+// Соединения, установленные до перезагрузки, продолжают работать со старым сертификатом.
+type Reloader struct {
+	config Config
+	logger *jsonlog.Logger
+
+	mu        sync.RWMutex
+	cert      *tls.Certificate
+	clientCAs *x509.CertPool
+	modTimes  map[string]time.Time
+
+	stop chan struct{}
+	wg   sync.WaitGroup
+}
+
+// NewReloader загружает сертификаты. Ошибка в файлах при запуске возвращается сразу,
+// а при последующих перезагрузках записывается в журнал, и сервер продолжает работать
+// с прежними сертификатами.
+func NewReloader(config Config, logger *jsonlog.Logger) (*Reloader, error) {
+	r := &Reloader{
+		config: config,
+		logger: logger,
+		stop:   make(chan struct{}),
+	}
+	if err := r.load(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// TLSConfig возвращает настройки для сервера. Сертификат выбирается при каждом
+// подключении, поэтому перезагрузка не требует перезапуска сервера.
+func (r *Reloader) TLSConfig() *tls.Config {
+	cfg := &tls.Config{
+		MinVersion:     r.config.MinVersion,
+		GetCertificate: r.certificate,
+	}
+	if r.config.ClientCAFile != "" {
+		// Цепочка клиента проверяется в verifyClient по текущему набору удостоверяющих
+		// центров: стандартная проверка использовала бы набор, заданный при запуске.
+		cfg.ClientAuth = tls.RequireAnyClientCert
+		cfg.VerifyPeerCertificate = r.verifyClient
+	}
+	return cfg
+}
+
+// Start запускает фоновую проверку файлов.
+func (r *Reloader) Start() {
+	r.wg.Add(1)
+	go func() {
+		defer r.wg.Done()
+		for {
+			select {
+			case <-r.stop:
+				return
+			case <-time.After(r.config.ReloadInterval):
+			}
+
+			if !r.changed() {
+				continue
+			}
+			if err := r.load(); err != nil {
+				r.logger.PrintError(err, map[string]string{"component": "tls"})
+				continue
+			}
+			r.logger.PrintInfo("certificates reloaded", map[string]string{"component": "tls"})
+		}
+	}()
+}
+
+// Shutdown останавливает проверку файлов.
+func (r *Reloader) Shutdown() {
+	close(r.stop)
+	r.wg.Wait()
+}
+
+func (r *Reloader) files() []string {
+	files := []string{r.config.CertFile, r.config.KeyFile}
+	if r.config.ClientCAFile != "" {
+		files = append(files, r.config.ClientCAFile)
+	}
+	return files
+}
+
+// changed сообщает, изменилось ли время модификации хотя бы одного из файлов.
+func (r *Reloader) changed() bool {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	for _, name := range r.files() {
+		info, err := os.Stat(name)
+		if err != nil {
+			// Файл могут заменять в этот момент; проверим при следующем проходе.
+			continue
+		}
+		if !info.ModTime().Equal(r.modTimes[name]) {
+			return true
+		}
+	}
+	return false
+}
+
+func (r *Reloader) load() error {
+	// Время модификации запоминается до чтения: если файл изменится во время загрузки,
+	// следующая проверка загрузит его снова.
+	modTimes := map[string]time.Time{}
+	for _, name := range r.files() {
+		info, err := os.Stat(name)
+		if err != nil {
+			return err
+		}
+		modTimes[name] = info.ModTime()
+	}
+
+	cert, err := tls.LoadX509KeyPair(r.config.CertFile, r.config.KeyFile)
+	if err != nil {
+		return fmt.Errorf("load server certificate: %w", err)
+	}
+
+	var clientCAs *x509.CertPool
+	if r.config.ClientCAFile != "" {
+		data, err := os.ReadFile(r.config.ClientCAFile)
+		if err != nil {
+			return fmt.Errorf("load client CA: %w", err)
+		}
+		clientCAs = x509.NewCertPool()
+		if !clientCAs.AppendCertsFromPEM(data) {
+			return fmt.Errorf("load client CA: no certificates found in %s", r.config.ClientCAFile)
+		}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.cert = &cert
+	r.clientCAs = clientCAs
+	r.modTimes = modTimes
+	return nil
+}
+
+func (r *Reloader) certificate(*tls.ClientHelloInfo) (*tls.Certificate, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.cert, nil
+}
+
+func (r *Reloader) verifyClient(rawCerts [][]byte, _ [][]*x509.Certificate) error {
+	if len(rawCerts) == 0 {
+		return errors.New("client certificate required")
+	}
+
+	certs := make([]*x509.Certificate, 0, len(rawCerts))
+	for _, raw := range rawCerts {
+		cert, err := x509.ParseCertificate(raw)
+		if err != nil {
+			return err
+		}
+		certs = append(certs, cert)
+	}
+
+	intermediates := x509.NewCertPool()
+	for _, cert := range certs[1:] {
+		intermediates.AddCert(cert)
+	}
+
+	r.mu.RLock()
+	roots := r.clientCAs
+	r.mu.RUnlock()
+
+	_, err := certs[0].Verify(x509.VerifyOptions{
+		Roots:         roots,
+		Intermediates: intermediates,
+		KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	})
+	return err
+}
+
+// Identity возвращает удостоверенное имя клиента из его сертификата, например
+// "CN=editor,O=Newsroom". Оно используется для авторизации и в журнале аудита.
+func Identity(cert *x509.Certificate) string {
+	return cert.Subject.String()
+}
+
+// Permitted проверяет сертификат клиента по списку разрешённых имён. Элемент списка
+// совпадает с Common Name сертификата или с его полным именем. Пустой список разрешает
+// любого клиента с действительным сертификатом.
+func Permitted(cert *x509.Certificate, allowed []string) bool {
+	if len(allowed) == 0 {
+		return true
+	}
+	return slices.Contains(allowed, cert.Subject.CommonName) || slices.Contains(allowed, Identity(cert))
+}
+
+type contextKey struct{}
+
+// NewContext возвращает копию ctx с именем клиента.
+func NewContext(ctx context.Context, identity string) context.Context {
+	return context.WithValue(ctx, contextKey{}, identity)
+}
+
+// FromContext возвращает имя клиента, сохранённое NewContext, или пустую строку.
+func FromContext(ctx context.Context) string {
+	identity, _ := ctx.Value(contextKey{}).(string)
+	return identity
+}
@@ -0,0 +1,446 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/AnKlvy/news-service/internal/validator"
+	"github.com/lib/pq"
+)
+
+// Действия массовых операций над новостями.
+const (
+	BulkSetStatus       = "set_status"
+	BulkAddCategory     = "add_category"
+	BulkRemoveCategory  = "remove_category"
+	BulkReplaceCategory = "replace_category"
+)
+
+var BulkActions = []string{BulkSetStatus, BulkAddCategory, BulkRemoveCategory, BulkReplaceCategory}
+
+// BulkOperation описывает массовое изменение: действие и новости, к которым оно
+// применяется, — либо перечисленные в IDs, либо подходящие под Filter. Если заданы
+// оба условия, изменяются новости из списка, подходящие под фильтр.
+type BulkOperation struct {
+	Action string     `json:"action"`
+	IDs    []int64    `json:"ids,omitempty"`
+	Filter NewsFilter `json:"filter"`
+	// Новый статус для set_status.
+	Status string `json:"status,omitempty"`
+	// Категория для add_category и remove_category или заменяемая категория для replace_category.
+	Category string `json:"category,omitempty"`
+	// Новая категория для replace_category.
+	NewCategory string `json:"new_category,omitempty"`
+}
+
+func ValidateBulkOperation(v *validator.Validator, op BulkOperation) {
+	v.Check(validator.PermittedValue(op.Action, BulkActions...), "action", "must be one of set_status, add_category, remove_category or replace_category")
+
+	// Пустой фильтр без списка идентификаторов затронул бы все новости: такое
+	// изменение почти всегда ошибка, поэтому запрещаем его.
+	v.Check(len(op.IDs) > 0 || !op.Filter.IsEmpty(), "filter", "must specify ids or at least one filter condition")
+	for _, id := range op.IDs {
+		if id < 1 {
+			v.AddError("ids", "must contain only positive integers")
+			break
+		}
+	}
+	ValidateNewsFilter(v, op.Filter)
+
+	switch op.Action {
+	case BulkSetStatus:
+		v.Check(validator.PermittedValue(op.Status, NewsStatuses...), "status", "must be a valid status")
+	case BulkAddCategory, BulkRemoveCategory:
+		v.Check(op.Category != "", "category", "must be provided")
+	case BulkReplaceCategory:
+		v.Check(op.Category != "", "category", "must be provided")
+		v.Check(op.NewCategory != "", "new_category", "must be provided")
+		v.Check(op.Category != op.NewCategory, "new_category", "must differ from category")
+	}
+}
+
+// selection возвращает условие отбора новостей с параметрами $1–$7. Условия фильтра
+// совпадают с GetAll.
+func (op BulkOperation) selection() (string, []any) {
+	categories := op.Filter.Categories
+	if categories == nil {
+		categories = []string{}
+	}
+	ids := op.IDs
+	if ids == nil {
+		ids = []int64{}
+	}
+
+	clause := `(to_tsvector('simple', title) @@ plainto_tsquery('simple', $1) OR $1 = '')
+    AND (categories @> $2 OR $2 = '{}')
+    AND (status = $3 OR $3 = '')
+    AND (author = $4 OR $4 = '')
+    AND (reading_time >= $5 OR $5 = 0)
+    AND (reading_time <= $6 OR $6 = 0)
+    AND (id = ANY($7) OR cardinality($7::bigint[]) = 0)`
+	args := []any{op.Filter.Title, pq.Array(categories), op.Filter.Status, op.Filter.Author,
+		op.Filter.MinReadingTime, op.Filter.MaxReadingTime, pq.Array(ids)}
+	return clause, args
+}
+
+// change возвращает условие, при котором новость действительно изменится, и выражение
+// SET для изменения. Параметры нумеруются начиная с $n. Новости, которые уже находятся
+// в нужном состоянии или не могут быть изменены (категория не добавляется сверх
+// максимума и не удаляется, если она единственная), не затрагиваются.
+func (op BulkOperation) change(n int) (cond, set string, args []any) {
+	switch op.Action {
+	case BulkSetStatus:
+		cond = fmt.Sprintf(`status <> $%d::text`, n)
+		set = fmt.Sprintf(`status = $%[1]d::text,
+        published_at = CASE WHEN $%[1]d::text = 'PUBLISHED' THEN COALESCE(published_at, now()) ELSE published_at END`, n)
+		args = []any{op.Status}
+	case BulkAddCategory:
+		cond = fmt.Sprintf(`NOT ($%d::text = ANY(categories)) AND cardinality(categories) < %d`, n, maxCategories)
+		set = fmt.Sprintf(`categories = array_append(categories, $%d::text)`, n)
+		args = []any{op.Category}
+	case BulkRemoveCategory:
+		cond = fmt.Sprintf(`$%d::text = ANY(categories) AND cardinality(categories) > 1`, n)
+		set = fmt.Sprintf(`categories = array_remove(categories, $%d::text)`, n)
+		args = []any{op.Category}
+	case BulkReplaceCategory:
+		// Если новая категория уже есть у новости, старая просто удаляется, чтобы
+		// не появилось дубликатов.
+		cond = fmt.Sprintf(`$%d::text = ANY(categories)`, n)
+		set = fmt.Sprintf(`categories = CASE WHEN $%[2]d::text = ANY(categories)
+                          THEN array_remove(categories, $%[1]d::text)
+                          ELSE array_replace(categories, $%[1]d::text, $%[2]d::text) END`, n, n+1)
+		args = []any{op.Category, op.NewCategory}
+	}
+	return cond, set, args
+}
+
+// BulkCounts — количество новостей, подходящих под условие массовой операции, и
+// количество тех из них, которые она изменит.
+type BulkCounts struct {
+	Matched  int64 `json:"matched"`
+	Affected int64 `json:"affected"`
+}
+
+// BulkChunk — результат обработки одной порции новостей.
+type BulkChunk struct {
+	// Идентификатор последней просмотренной новости; следующая порция начинается после него.
+	LastID    int64
+	Processed int64
+	Affected  int64
+	// Done равен true, когда подходящих новостей больше не осталось.
+	Done bool
+}
+
+// BulkPreview подсчитывает, сколько новостей затронет операция, ничего не изменяя.
+func (m NewsModel) BulkPreview(op BulkOperation) (BulkCounts, error) {
+	selection, args := op.selection()
+	cond, _, changeArgs := op.change(len(args) + 1)
+	args = append(args, changeArgs...)
+
+	query := fmt.Sprintf(`
+    SELECT count(*), count(*) FILTER (WHERE %s)
+    FROM news
+    WHERE %s`, cond, selection)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	var counts BulkCounts
+	err := m.DB.QueryRowContext(ctx, query, args...).Scan(&counts.Matched, &counts.Affected)
+	return counts, err
+}
+
+// BulkApply применяет операцию к следующей порции из не более чем limit новостей
+// с идентификаторами больше afterID. Порция обрабатывается в одной транзакции: у каждой
+// изменённой новости увеличивается версия, а подписчики получают события об изменении.
+func (m NewsModel) BulkApply(op BulkOperation, afterID int64, limit int) (BulkChunk, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	tx, err := m.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return BulkChunk{}, err
+	}
+	defer tx.Rollback()
+
+	// Блокируем порцию, чтобы параллельные изменения не перезаписали друг друга.
+	selection, args := op.selection()
+	query := fmt.Sprintf(`
+    SELECT id
+    FROM news
+    WHERE %s AND id > $8
+    ORDER BY id
+    LIMIT $9
+    FOR UPDATE`, selection)
+	args = append(args, afterID, limit)
+
+	rows, err := tx.QueryContext(ctx, query, args...)
+	if err != nil {
+		return BulkChunk{}, err
+	}
+	ids := []int64{}
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return BulkChunk{}, err
+		}
+		ids = append(ids, id)
+	}
+	rows.Close()
+	if err = rows.Err(); err != nil {
+		return BulkChunk{}, err
+	}
+
+	chunk := BulkChunk{LastID: afterID, Processed: int64(len(ids)), Done: len(ids) < limit}
+	if len(ids) == 0 {
+		return chunk, tx.Commit()
+	}
+	chunk.LastID = ids[len(ids)-1]
+
+	cond, set, changeArgs := op.change(2)
+	query = fmt.Sprintf(`
+    UPDATE news
+    SET %s, updated_at = now(), version = version + 1
+    FROM (SELECT id, title, categories, status, author, reading_time, version FROM news WHERE id = ANY($1)) AS old
+    WHERE news.id = old.id AND %s
+    RETURNING news.id, news.created_at, news.updated_at, news.title, news.content, news.content_format,
+              news.content_html, news.word_count, news.reading_time, news.excerpt, news.categories, news.status,
+              news.image_urls, news.author, news.published_at, news.version,
+              old.title, old.categories, old.status, old.author, old.reading_time, old.version`, set, cond)
+
+	rows, err = tx.QueryContext(ctx, query, append([]any{pq.Array(ids)}, changeArgs...)...)
+	if err != nil {
+		return BulkChunk{}, convertCheckViolation(err)
+	}
+
+	changed := []*News{}
+	// Поля фильтров до изменения нужны подписчикам, из выборки которых новость могла выйти.
+	previous := map[int64]*News{}
+	published := map[int64]bool{}
+	for rows.Next() {
+		var n, old News
+		err := rows.Scan(
+			&n.ID,
+			&n.CreatedAt,
+			&n.UpdatedAt,
+			&n.Title,
+			&n.Content,
+			&n.ContentFormat,
+			&n.ContentHTML,
+			&n.WordCount,
+			&n.ReadingTime,
+			&n.Excerpt,
+			pq.Array(&n.Categories),
+			&n.Status,
+			pq.Array(&n.ImageURLs),
+			&n.Author,
+			&n.PublishedAt,
+			&n.Version,
+			&old.Title,
+			pq.Array(&old.Categories),
+			&old.Status,
+			&old.Author,
+			&old.ReadingTime,
+			&old.Version,
+		)
+		if err != nil {
+			rows.Close()
+			return BulkChunk{}, err
+		}
+		old.ID = n.ID
+		changed = append(changed, &n)
+		previous[n.ID] = &old
+		published[n.ID] = n.Status == "PUBLISHED" && old.Status != "PUBLISHED"
+	}
+	rows.Close()
+	if err = rows.Err(); err != nil {
+		return BulkChunk{}, convertCheckViolation(err)
+	}
+	chunk.Affected = int64(len(changed))
+
+	// Изображения нужны, чтобы события и webhook содержали новость целиком.
+	if err = loadMedia(ctx, tx, changed...); err != nil {
+		return BulkChunk{}, err
+	}
+
+	for _, n := range changed {
+		if err = notify(ctx, tx, EventUpdated, n, previous[n.ID]); err != nil {
+			return BulkChunk{}, err
+		}
+		if published[n.ID] {
+			if err = notify(ctx, tx, EventPublished, n, previous[n.ID]); err != nil {
+				return BulkChunk{}, err
+			}
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return BulkChunk{}, err
+	}
+
+	for _, n := range changed {
+		m.Events.Publish(EventUpdated, n, previous[n.ID])
+		if published[n.ID] {
+			m.Events.Publish(EventPublished, n, previous[n.ID])
+		}
+	}
+	return chunk, nil
+}
+
+// Состояния массового задания.
+const (
+	JobPending   = "pending"
+	JobRunning   = "running"
+	JobCompleted = "completed"
+	JobFailed    = "failed"
+)
+
+// BulkJob — массовая операция, выполняемая в фоне. Прогресс сохраняется после каждой
+// порции, поэтому прерванное задание продолжается с места остановки.
+type BulkJob struct {
+	ID         int64         `json:"id"`
+	CreatedAt  time.Time     `json:"created_at"`
+	UpdatedAt  time.Time     `json:"updated_at"`
+	Operation  BulkOperation `json:"operation"`
+	Status     string        `json:"status"`
+	Matched    int64         `json:"matched"`
+	Processed  int64         `json:"processed"`
+	Affected   int64         `json:"affected"`
+	Error      string        `json:"error,omitempty"`
+	FinishedAt *time.Time    `json:"finished_at,omitempty"`
+	LastID     int64         `json:"-"`
+}
+
+type BulkJobModel struct {
+	DB *sql.DB
+}
+
+func (m BulkJobModel) Insert(job *BulkJob) error {
+	params, err := json.Marshal(job.Operation)
+	if err != nil {
+		return err
+	}
+
+	query := `
+    INSERT INTO bulk_jobs (action, params, matched)
+    VALUES ($1, $2, $3)
+    RETURNING id, created_at, updated_at, status`
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	return m.DB.QueryRowContext(ctx, query, job.Operation.Action, string(params), job.Matched).Scan(
+		&job.ID, &job.CreatedAt, &job.UpdatedAt, &job.Status)
+}
+
+const bulkJobColumns = `id, created_at, updated_at, params, status, matched, processed, affected, last_id, error, finished_at`
+
+func scanBulkJob(row interface{ Scan(dest ...any) error }) (*BulkJob, error) {
+	var job BulkJob
+	var params []byte
+	err := row.Scan(
+		&job.ID,
+		&job.CreatedAt,
+		&job.UpdatedAt,
+		&params,
+		&job.Status,
+		&job.Matched,
+		&job.Processed,
+		&job.Affected,
+		&job.LastID,
+		&job.Error,
+		&job.FinishedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(params, &job.Operation); err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
+
+func (m BulkJobModel) Get(id int64) (*BulkJob, error) {
+	if id < 1 {
+		return nil, ErrRecordNotFound
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	job, err := scanBulkJob(m.DB.QueryRowContext(ctx, `SELECT `+bulkJobColumns+` FROM bulk_jobs WHERE id = $1`, id))
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return nil, ErrRecordNotFound
+		default:
+			return nil, err
+		}
+	}
+	return job, nil
+}
+
+// Claim забирает одно незавершённое задание, которое никто не выполняет: новое или
+// брошенное экземпляром, не продлившим lease. Возвращает nil, если таких заданий нет.
+func (m BulkJobModel) Claim(lease time.Duration) (*BulkJob, error) {
+	query := `
+    UPDATE bulk_jobs
+    SET status = 'running', lease_until = now() + make_interval(secs => $1), updated_at = now()
+    WHERE id = (
+        SELECT id FROM bulk_jobs
+        WHERE status IN ('pending', 'running') AND lease_until <= now()
+        ORDER BY id
+        LIMIT 1
+        FOR UPDATE SKIP LOCKED
+    )
+    RETURNING ` + bulkJobColumns
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	job, err := scanBulkJob(m.DB.QueryRowContext(ctx, query, lease.Seconds()))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, nil
+	}
+	return job, err
+}
+
+// SaveProgress сохраняет прогресс и состояние задания и продлевает lease.
+func (m BulkJobModel) SaveProgress(job *BulkJob, lease time.Duration) error {
+	query := `
+    UPDATE bulk_jobs
+    SET status = $1, matched = $2, processed = $3, affected = $4, last_id = $5, error = $6,
+        lease_until = now() + make_interval(secs => $7), updated_at = now(),
+        finished_at = CASE WHEN $1 IN ('completed', 'failed') THEN now() ELSE NULL END
+    WHERE id = $8
+    RETURNING updated_at, finished_at`
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	args := []any{job.Status, job.Matched, job.Processed, job.Affected, job.LastID, job.Error, lease.Seconds(), job.ID}
+	return m.DB.QueryRowContext(ctx, query, args...).Scan(&job.UpdatedAt, &job.FinishedAt)
+}
+
+type MockBulkJobModel struct{}
+
+func (m MockBulkJobModel) Insert(job *BulkJob) error {
+	return nil
+}
+
+func (m MockBulkJobModel) Get(id int64) (*BulkJob, error) {
+	return nil, nil
+}
+
+func (m MockBulkJobModel) Claim(lease time.Duration) (*BulkJob, error) {
+	return nil, nil
+}
+
+func (m MockBulkJobModel) SaveProgress(job *BulkJob, lease time.Duration) error {
+	return nil
+}
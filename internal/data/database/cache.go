@@ -0,0 +1,264 @@
+package database
+
+import (
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/AnKlvy/news-service/internal/cache"
+	"golang.org/x/sync/singleflight"
+)
+
+// CacheConfig — настройки кэша новостей.
+type CacheConfig struct {
+	// Максимальное количество новостей в кэше и время, в течение которого они считаются актуальными.
+	Size int
+	TTL  time.Duration
+	// Кэширование результатов GetAll: количество страниц и время их хранения. Нулевой
+	// ListTTL отключает кэширование списков.
+	ListSize int
+	ListTTL  time.Duration
+}
+
+// CacheStats — счётчики обращений к кэшу.
+type CacheStats struct {
+	Hits       int64 `json:"hits"`
+	Misses     int64 `json:"misses"`
+	ListHits   int64 `json:"list_hits"`
+	ListMisses int64 `json:"list_misses"`
+	Entries    int   `json:"entries"`
+	ListPages  int   `json:"list_pages"`
+}
+
+type cachedList struct {
+	news     []*News
+	metadata Metadata
+}
+
+// CachedNewsModel — кэширующая обёртка над NewsStore. Новости, полученные через Get,
+// хранятся в памяти, а одновременные промахи по одной новости приводят к одному запросу
+// к базе данных. Изменения, сделанные через эту обёртку, сразу удаляют устаревшие
+// записи; изменения из других экземпляров нужно передавать в Invalidate.
+type CachedNewsModel struct {
+	NewsStore
+
+	news  *cache.LRU[int64, *News]
+	lists *cache.LRU[string, cachedList]
+	group singleflight.Group
+
+	// Поколение увеличивается при каждой инвалидации. Значение, загруженное до неё,
+	// в кэш не попадает, иначе медленный запрос мог бы вернуть в кэш старую версию.
+	mu         sync.Mutex
+	generation uint64
+
+	hits, misses, listHits, listMisses atomic.Int64
+}
+
+func NewCachedNewsModel(store NewsStore, config CacheConfig) *CachedNewsModel {
+	c := &CachedNewsModel{
+		NewsStore: store,
+		news:      cache.NewLRU[int64, *News](config.Size, config.TTL),
+		lists:     cache.NewLRU[string, cachedList](config.ListSize, config.ListTTL),
+	}
+	return c
+}
+
+func (c *CachedNewsModel) Get(id int64) (*News, error) {
+	if news, ok := c.news.Get(id); ok {
+		c.hits.Add(1)
+		return cloneNews(news), nil
+	}
+	c.misses.Add(1)
+
+	generation := c.currentGeneration()
+	v, err, _ := c.group.Do(strconv.FormatInt(id, 10), func() (any, error) {
+		news, err := c.NewsStore.Get(id)
+		if err != nil {
+			return nil, err
+		}
+		c.store(generation, func() { c.news.Set(id, cloneNews(news)) })
+		return news, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return cloneNews(v.(*News)), nil
+}
+
+// GetAll кэширует страницы списка, если это включено в настройках. Ключ строится
+// из нормализованных условий отбора, поэтому порядок категорий и регистр заголовка
+// на него не влияют.
+func (c *CachedNewsModel) GetAll(nf NewsFilter, filters Filters) ([]*News, Metadata, error) {
+	if c.lists.TTL() <= 0 {
+		return c.NewsStore.GetAll(nf, filters)
+	}
+
+	key := listCacheKey(nf, filters)
+	if list, ok := c.lists.Get(key); ok {
+		c.listHits.Add(1)
+		return cloneNewsList(list.news), list.metadata, nil
+	}
+	c.listMisses.Add(1)
+
+	generation := c.currentGeneration()
+	v, err, _ := c.group.Do("list:"+key, func() (any, error) {
+		news, metadata, err := c.NewsStore.GetAll(nf, filters)
+		if err != nil {
+			return nil, err
+		}
+		list := cachedList{news: cloneNewsList(news), metadata: metadata}
+		c.store(generation, func() { c.lists.Set(key, list) })
+		return list, nil
+	})
+	if err != nil {
+		return nil, Metadata{}, err
+	}
+	list := v.(cachedList)
+	return cloneNewsList(list.news), list.metadata, nil
+}
+
+func (c *CachedNewsModel) Insert(news *News) error {
+	defer c.invalidateLists()
+	return c.NewsStore.Insert(news)
+}
+
+func (c *CachedNewsModel) InsertIdempotent(news *News, key IdempotencyKey) (bool, error) {
+	defer c.invalidateLists()
+	return c.NewsStore.InsertIdempotent(news, key)
+}
+
+func (c *CachedNewsModel) InsertBatch(news []*News) error {
+	defer c.invalidateLists()
+	return c.NewsStore.InsertBatch(news)
+}
+
+func (c *CachedNewsModel) Update(news *News) error {
+	defer c.Invalidate(news.ID)
+	return c.NewsStore.Update(news)
+}
+
+func (c *CachedNewsModel) Delete(id int64, version int32) error {
+	defer c.Invalidate(id)
+	return c.NewsStore.Delete(id, version)
+}
+
+// BulkApply может изменить любую новость, поэтому после него кэш очищается целиком.
+func (c *CachedNewsModel) BulkApply(op BulkOperation, afterID int64, limit int) (BulkChunk, error) {
+	defer c.Purge()
+	return c.NewsStore.BulkApply(op, afterID, limit)
+}
+
+// Invalidate удаляет новость из кэша вместе со всеми страницами списков.
+func (c *CachedNewsModel) Invalidate(id int64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.generation++
+	c.news.Delete(id)
+	c.lists.Purge()
+}
+
+// Purge очищает кэш целиком.
+func (c *CachedNewsModel) Purge() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.generation++
+	c.news.Purge()
+	c.lists.Purge()
+}
+
+// SetTTL меняет время жизни новостей и страниц списка без перезапуска. Нулевой listTTL
+// отключает кэширование списков и удаляет уже сохранённые страницы.
+func (c *CachedNewsModel) SetTTL(ttl, listTTL time.Duration) {
+	c.news.SetTTL(ttl)
+	c.lists.SetTTL(listTTL)
+	if listTTL <= 0 {
+		c.lists.Purge()
+	}
+}
+
+func (c *CachedNewsModel) Stats() CacheStats {
+	stats := CacheStats{
+		Hits:       c.hits.Load(),
+		Misses:     c.misses.Load(),
+		ListHits:   c.listHits.Load(),
+		ListMisses: c.listMisses.Load(),
+		Entries:    c.news.Len(),
+		ListPages:  c.lists.Len(),
+	}
+	return stats
+}
+
+func (c *CachedNewsModel) invalidateLists() {
+	if c.lists.TTL() <= 0 {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.generation++
+	c.lists.Purge()
+}
+
+func (c *CachedNewsModel) currentGeneration() uint64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.generation
+}
+
+// store выполняет set, только если с начала загрузки кэш не инвалидировался.
+func (c *CachedNewsModel) store(generation uint64, set func()) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.generation == generation {
+		set()
+	}
+}
+
+func listCacheKey(nf NewsFilter, filters Filters) string {
+	categories := slices.Clone(nf.Categories)
+	slices.Sort(categories)
+	categories = slices.Compact(categories)
+
+	title := strings.ToLower(strings.Join(strings.Fields(nf.Title), " "))
+
+	return fmt.Sprintf("%q|%q|%q|%q|%d|%d|%d|%d|%q",
+		title, strings.Join(categories, ","), nf.Status, nf.Author, nf.MinReadingTime, nf.MaxReadingTime,
+		filters.Page, filters.PageSize, filters.Sort)
+}
+
+// cloneNews возвращает копию новости. Обработчики изменяют полученные новости,
+// поэтому наружу никогда не отдаётся значение, хранящееся в кэше.
+func cloneNews(news *News) *News {
+	c := *news
+	c.Categories = slices.Clone(news.Categories)
+	c.ImageURLs = slices.Clone(news.ImageURLs)
+	if news.Media != nil {
+		c.Media = make([]Media, len(news.Media))
+		for i, m := range news.Media {
+			m.Renditions = slices.Clone(m.Renditions)
+			c.Media[i] = m
+		}
+	}
+	if news.PublishedAt != nil {
+		publishedAt := *news.PublishedAt
+		c.PublishedAt = &publishedAt
+	}
+	return &c
+}
+
+func cloneNewsList(news []*News) []*News {
+	list := make([]*News, len(news))
+	for i, n := range news {
+		list[i] = cloneNews(n)
+	}
+	return list
+}
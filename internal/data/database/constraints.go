@@ -0,0 +1,150 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/AnKlvy/news-service/internal/markup"
+	"github.com/lib/pq"
+)
+
+// Код ошибки Postgres check_violation.
+const checkViolation = "23514"
+
+// Constraint описывает CHECK-ограничение таблицы news и правило ValidateNews,
+// которому оно соответствует.
+type Constraint struct {
+	Name    string
+	Field   string
+	Message string
+	Check   string
+}
+
+// newsConstraints строит ограничения таблицы news из тех же констант, что использует
+// ValidateNews. Миграции должны создавать ограничения ровно с такими определениями;
+// расхождения обнаруживает CheckNewsConstraints.
+func newsConstraints() []Constraint {
+	return []Constraint{
+		{
+			Name:    "categories_length_check",
+			Field:   "categories",
+			Message: fmt.Sprintf("must contain between %d and %d categories", minCategories, maxCategories),
+			Check:   fmt.Sprintf("cardinality(categories) BETWEEN %d AND %d", minCategories, maxCategories),
+		},
+		{
+			Name:    "title_length_check",
+			Field:   "title",
+			Message: fmt.Sprintf("must not be more than %d bytes long", maxTitleBytes),
+			Check:   fmt.Sprintf("octet_length(title) <= %d", maxTitleBytes),
+		},
+		{
+			Name:    "image_urls_length_check",
+			Field:   "image_urls",
+			Message: fmt.Sprintf("must not be more than %d images", maxMediaItems),
+			Check:   fmt.Sprintf("cardinality(image_urls) <= %d", maxMediaItems),
+		},
+		{
+			Name:    "news_status_check",
+			Field:   "status",
+			Message: "must be a valid status",
+			Check:   fmt.Sprintf("status IN (%s)", quoteList(NewsStatuses)),
+		},
+		{
+			Name:    "news_content_format_check",
+			Field:   "content_format",
+			Message: "must be one of plain, markdown or html",
+			Check:   fmt.Sprintf("content_format IN (%s)", quoteList(markup.Formats)),
+		},
+	}
+}
+
+// ViolationError возвращается, если запись нарушила CHECK-ограничение. Обычно такие
+// данные отсекает ValidateNews, поэтому ошибка означает расхождение схемы и правил проверки.
+type ViolationError struct {
+	Field   string
+	Message string
+}
+
+func (e *ViolationError) Error() string {
+	return e.Field + " " + e.Message
+}
+
+// convertCheckViolation заменяет нарушение известного CHECK-ограничения на ViolationError.
+func convertCheckViolation(err error) error {
+	var pqErr *pq.Error
+	if !errors.As(err, &pqErr) || pqErr.Code != checkViolation {
+		return err
+	}
+	for _, c := range newsConstraints() {
+		if c.Name == pqErr.Constraint {
+			return &ViolationError{Field: c.Field, Message: c.Message}
+		}
+	}
+	return err
+}
+
+// CheckNewsConstraints сравнивает CHECK-ограничения таблицы news с правилами проверки
+// и возвращает описания расхождений. Чтобы не зависеть от того, как Postgres
+// переписывает выражения, ожидаемые ограничения создаются на временной копии таблицы
+// и сравниваются в нормализованном виде.
+func CheckNewsConstraints(db *sql.DB) ([]string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	// Временная таблица существует только внутри транзакции, которая всегда откатывается.
+	defer tx.Rollback()
+
+	if _, err = tx.ExecContext(ctx, `CREATE TEMP TABLE news_expected (LIKE news) ON COMMIT DROP`); err != nil {
+		return nil, err
+	}
+	for _, c := range newsConstraints() {
+		query := fmt.Sprintf(`ALTER TABLE news_expected ADD CONSTRAINT %s CHECK (%s)`, pq.QuoteIdentifier(c.Name), c.Check)
+		if _, err = tx.ExecContext(ctx, query); err != nil {
+			return nil, err
+		}
+	}
+
+	query := `
+    SELECT e.conname, pg_get_constraintdef(e.oid), coalesce(pg_get_constraintdef(l.oid), '')
+    FROM pg_constraint e
+    LEFT JOIN pg_constraint l ON l.conrelid = 'news'::regclass AND l.conname = e.conname AND l.contype = 'c'
+    WHERE e.conrelid = 'news_expected'::regclass AND e.contype = 'c'
+    ORDER BY e.conname`
+
+	rows, err := tx.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var mismatches []string
+	for rows.Next() {
+		var name, expected, live string
+		if err := rows.Scan(&name, &expected, &live); err != nil {
+			return nil, err
+		}
+		switch {
+		case live == "":
+			mismatches = append(mismatches, fmt.Sprintf("constraint %s is missing, expected %s", name, expected))
+		case live != expected:
+			mismatches = append(mismatches, fmt.Sprintf("constraint %s is %s, expected %s", name, live, expected))
+		}
+	}
+	return mismatches, rows.Err()
+}
+
+func quoteList(values []string) string {
+	quoted := make([]string, len(values))
+	for i, v := range values {
+		quoted[i] = pq.QuoteLiteral(v)
+	}
+	return strings.Join(quoted, ", ")
+}
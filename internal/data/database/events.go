@@ -0,0 +1,153 @@
+package database
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"strings"
+)
+
+// Типы событий об изменении новостей.
+const (
+	EventCreated   = "created"
+	EventUpdated   = "updated"
+	EventDeleted   = "deleted"
+	EventPublished = "published"
+)
+
+// EventPublisher получает уведомления об изменениях новостей. NewsModel вызывает
+// Publish только после успешной фиксации транзакции. previous содержит поля, по
+// которым подписчики отбирают события, в состоянии до изменения; для созданных
+// и удалённых новостей он равен nil.
+type EventPublisher interface {
+	Publish(eventType string, news, previous *News)
+}
+
+// noopPublisher используется, когда публикация событий не настроена.
+type noopPublisher struct{}
+
+func (noopPublisher) Publish(string, *News, *News) {}
+
+// Matches сообщает, подходит ли новость под фильтр. Используется для потоковой выдачи,
+// где записи проверяются в памяти, а не запросом к базе данных. Заголовок считается
+// подходящим, если содержит все слова фильтра без учёта регистра.
+func (f NewsFilter) Matches(n *News) bool {
+	if f.Status != "" && n.Status != f.Status {
+		return false
+	}
+	if f.Author != "" && n.Author != f.Author {
+		return false
+	}
+	for _, c := range f.Categories {
+		found := false
+		for _, nc := range n.Categories {
+			if nc == c {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	if f.MinReadingTime != 0 && n.ReadingTime < f.MinReadingTime {
+		return false
+	}
+	if f.MaxReadingTime != 0 && n.ReadingTime > f.MaxReadingTime {
+		return false
+	}
+	if f.Title != "" {
+		title := strings.ToLower(n.Title)
+		for _, word := range strings.Fields(strings.ToLower(f.Title)) {
+			if !strings.Contains(title, word) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// NotifyChannel — канал Postgres, через который экземпляры сервиса сообщают друг
+// другу об изменениях новостей.
+const NotifyChannel = "news_events"
+
+// Notification — содержимое уведомления pg_notify. Полная запись не передаётся,
+// так как размер уведомления ограничен 8000 байт; получатели читают её из базы сами.
+type Notification struct {
+	ID       int64  `json:"id"`
+	Action   string `json:"action"`
+	Version  int32  `json:"version"`
+	Instance string `json:"instance"`
+	// Поля удалённой новости, по которым подписчики отбирают события. Удалённую запись
+	// из базы уже не прочитать, поэтому для удалений они передаются в уведомлении.
+	News *News `json:"news,omitempty"`
+	// Те же поля изменённой новости до изменения. По ним подписчики узнают о новостях,
+	// которые перестали подходить под их фильтр.
+	Previous *News `json:"previous,omitempty"`
+}
+
+// NewNotification формирует уведомление об изменении новости.
+func NewNotification(action string, news, previous *News) Notification {
+	n := Notification{
+		ID:       news.ID,
+		Action:   action,
+		Version:  news.Version,
+		Instance: instanceID,
+	}
+	if action == EventDeleted {
+		n.News = filterFields(news)
+	}
+	if previous != nil {
+		n.Previous = filterFields(previous)
+	}
+	return n
+}
+
+// filterFields возвращает копию новости только с полями, которые проверяет
+// NewsFilter.Matches, чтобы уведомление оставалось небольшим.
+func filterFields(news *News) *News {
+	return &News{
+		ID:          news.ID,
+		Title:       news.Title,
+		Categories:  news.Categories,
+		Status:      news.Status,
+		Author:      news.Author,
+		ReadingTime: news.ReadingTime,
+		Version:     news.Version,
+	}
+}
+
+// Идентификатор текущего экземпляра сервиса. По нему экземпляр отличает собственные
+// уведомления, которые уже были доставлены локальным подписчикам напрямую.
+var instanceID = newInstanceID()
+
+func newInstanceID() string {
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		panic(err)
+	}
+	return hex.EncodeToString(b)
+}
+
+// InstanceID возвращает идентификатор текущего экземпляра сервиса.
+func InstanceID() string {
+	return instanceID
+}
+
+// notify отправляет уведомление об изменении новости другим экземплярам сервиса и ставит
+// в очередь уведомления для подписанных webhook. Вызывается внутри транзакции, поэтому
+// Postgres доставит уведомление только после её фиксации и не доставит вовсе при откате.
+func notify(ctx context.Context, tx *sql.Tx, action string, news, previous *News) error {
+	if err := enqueueWebhooks(ctx, tx, action, news); err != nil {
+		return err
+	}
+
+	payload, err := json.Marshal(NewNotification(action, news, previous))
+	if err != nil {
+		return err
+	}
+	_, err = tx.ExecContext(ctx, `SELECT pg_notify($1, $2)`, NotifyChannel, string(payload))
+	return err
+}
@@ -0,0 +1,143 @@
+package database
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/AnKlvy/news-service/internal/validator"
+)
+
+// ErrIdempotencyMismatch возвращается, если ключ идемпотентности уже использован
+// для запроса с другим содержимым.
+var ErrIdempotencyMismatch = errors.New("idempotency key has already been used with a different request payload")
+
+// errIdempotencyKeyTaken означает, что ключ одновременно занял другой запрос.
+var errIdempotencyKeyTaken = errors.New("idempotency key is taken")
+
+// IdempotencyKey связывает ключ, переданный клиентом, с хешем запроса и созданной новостью.
+type IdempotencyKey struct {
+	Key         string
+	RequestHash string
+	NewsID      int64
+	CreatedAt   time.Time
+	ExpiresAt   time.Time
+}
+
+func ValidateIdempotencyKey(v *validator.Validator, key string) {
+	v.Check(len(key) <= 255, "idempotency-key", "must not be more than 255 bytes long")
+}
+
+// HashNewsRequest возвращает хеш новости в том виде, в каком её прислал клиент. Хеш
+// не зависит от транспорта: один и тот же ключ можно повторить и через REST, и через gRPC.
+func HashNewsRequest(news *News) (string, error) {
+	b, err := json.Marshal(news)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// InsertIdempotent сохраняет новость, если ключ ещё не использован. Если по ключу уже
+// создана новость, она загружается в news, а replayed равен true. Ключ занимается в той же
+// транзакции, что и новость, поэтому из двух одновременных запросов новость создаст только один.
+func (m NewsModel) InsertIdempotent(news *News, key IdempotencyKey) (replayed bool, err error) {
+	// Вторая попытка нужна, если между проверкой и вставкой ключ занял параллельный запрос:
+	// к этому моменту его транзакция уже завершилась и результат можно вернуть.
+	for range 2 {
+		existing, err := m.getIdempotencyKey(key.Key)
+		switch {
+		case err == nil:
+			if existing.RequestHash != key.RequestHash {
+				return false, ErrIdempotencyMismatch
+			}
+			// Новость могла быть создана только что, поэтому читаем её с основного сервера.
+			primary := m
+			primary.Replicas = nil
+			stored, err := primary.Get(existing.NewsID)
+			if err != nil {
+				return false, err
+			}
+			*news = *stored
+			return true, nil
+		case !errors.Is(err, ErrRecordNotFound):
+			return false, err
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+		err = convertCheckViolation(m.insert(ctx, &key, news))
+		cancel()
+		if !errors.Is(err, errIdempotencyKeyTaken) {
+			return false, err
+		}
+	}
+	return false, errIdempotencyKeyTaken
+}
+
+func (m NewsModel) getIdempotencyKey(key string) (*IdempotencyKey, error) {
+	query := `
+    SELECT key, request_hash, news_id, created_at, expires_at
+    FROM idempotency_keys
+    WHERE key = $1 AND expires_at > now()`
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	var k IdempotencyKey
+	err := m.DB.QueryRowContext(ctx, query, key).Scan(&k.Key, &k.RequestHash, &k.NewsID, &k.CreatedAt, &k.ExpiresAt)
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return nil, ErrRecordNotFound
+		default:
+			return nil, err
+		}
+	}
+	return &k, nil
+}
+
+// saveIdempotencyKey записывает ключ в транзакции создания новости. Истёкший, но ещё
+// не удалённый ключ перезаписывается; действующий ключ возвращает errIdempotencyKeyTaken.
+func saveIdempotencyKey(ctx context.Context, tx *sql.Tx, key *IdempotencyKey) error {
+	query := `
+    INSERT INTO idempotency_keys (key, request_hash, news_id, expires_at)
+    VALUES ($1, $2, $3, $4)
+    ON CONFLICT (key) DO UPDATE
+    SET request_hash = EXCLUDED.request_hash, news_id = EXCLUDED.news_id,
+        created_at = now(), expires_at = EXCLUDED.expires_at
+    WHERE idempotency_keys.expires_at <= now()
+    RETURNING created_at`
+
+	err := tx.QueryRowContext(ctx, query, key.Key, key.RequestHash, key.NewsID, key.ExpiresAt).Scan(&key.CreatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return errIdempotencyKeyTaken
+	}
+	return err
+}
+
+type IdempotencyKeyModel struct {
+	DB *sql.DB
+}
+
+// DeleteExpired удаляет истёкшие ключи и возвращает их количество.
+func (m IdempotencyKeyModel) DeleteExpired() (int64, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	result, err := m.DB.ExecContext(ctx, `DELETE FROM idempotency_keys WHERE expires_at <= now()`)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+type MockIdempotencyKeyModel struct{}
+
+func (m MockIdempotencyKeyModel) DeleteExpired() (int64, error) {
+	return 0, nil
+}
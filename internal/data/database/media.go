@@ -0,0 +1,207 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/url"
+	"strings"
+	"sync/atomic"
+
+	"github.com/AnKlvy/news-service/internal/validator"
+	"github.com/lib/pq"
+)
+
+// Максимальное количество изображений у одной новости.
+const maxMediaItems = 7
+
+// Media описывает изображение, прикреплённое к новости. Поле ImageURLs новости
+// по-прежнему заполняется адресами этих изображений для старых клиентов.
+type Media struct {
+	URL     string `json:"url"`
+	AltText string `json:"alt_text,omitempty"`
+	Caption string `json:"caption,omitempty"`
+	Credit  string `json:"credit,omitempty"`
+	Width   int32  `json:"width,omitempty"`
+	Height  int32  `json:"height,omitempty"`
+	// Уменьшенные копии изображения; заполняются только для файлов, загруженных
+	// через сервис, и не сохраняются вместе с новостью.
+	Renditions []Rendition `json:"renditions,omitempty"`
+}
+
+// allowedImageHosts хранит список хостов, с которых разрешено подключать изображения.
+// Пустой список означает, что допустим любой хост.
+var allowedImageHosts atomic.Pointer[[]string]
+
+// SetAllowedImageHosts задаёт список разрешённых хостов для изображений. Элемент вида
+// "*.example.com" разрешает все поддомены example.com.
+func SetAllowedImageHosts(hosts []string) {
+	normalized := make([]string, 0, len(hosts))
+	for _, h := range hosts {
+		if h = strings.ToLower(strings.TrimSpace(h)); h != "" {
+			normalized = append(normalized, h)
+		}
+	}
+	allowedImageHosts.Store(&normalized)
+}
+
+// imageHostPermitted проверяет хост изображения по списку разрешённых хостов.
+func imageHostPermitted(rawURL string) bool {
+	hosts := allowedImageHosts.Load()
+	if hosts == nil || len(*hosts) == 0 {
+		return true
+	}
+
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return false
+	}
+	host := strings.ToLower(u.Hostname())
+	for _, allowed := range *hosts {
+		if suffix, ok := strings.CutPrefix(allowed, "*."); ok {
+			if strings.HasSuffix(host, "."+suffix) {
+				return true
+			}
+			continue
+		}
+		if host == allowed {
+			return true
+		}
+	}
+	return false
+}
+
+// MediaFromURLs строит список изображений по списку адресов, который передают старые
+// клиенты. Метаданные уже существующих изображений с теми же адресами сохраняются.
+func MediaFromURLs(urls []string, existing []Media) []Media {
+	known := make(map[string]Media, len(existing))
+	for _, m := range existing {
+		known[m.URL] = m
+	}
+
+	media := make([]Media, 0, len(urls))
+	for _, u := range urls {
+		if m, ok := known[strings.TrimSpace(u)]; ok {
+			media = append(media, m)
+			continue
+		}
+		media = append(media, Media{URL: u})
+	}
+	return media
+}
+
+// NormalizeMedia приводит изображения новости к единому виду: если клиент передал
+// только image_urls, из них строится список Media; адреса очищаются от пробелов,
+// повторяющиеся изображения удаляются, а ImageURLs заполняется из Media.
+func NormalizeMedia(news *News) {
+	if len(news.Media) == 0 && len(news.ImageURLs) > 0 {
+		news.Media = MediaFromURLs(news.ImageURLs, nil)
+	}
+
+	seen := make(map[string]bool, len(news.Media))
+	media := make([]Media, 0, len(news.Media))
+	for _, m := range news.Media {
+		m.URL = strings.TrimSpace(m.URL)
+		if seen[m.URL] {
+			continue
+		}
+		seen[m.URL] = true
+		media = append(media, m)
+	}
+
+	news.Media = media
+	news.ImageURLs = make([]string, 0, len(media))
+	for _, m := range media {
+		news.ImageURLs = append(news.ImageURLs, m.URL)
+	}
+}
+
+// ValidateMedia проверяет изображения новости.
+func ValidateMedia(v *validator.Validator, media []Media) {
+	v.Check(len(media) <= maxMediaItems, "image_urls", fmt.Sprintf("must not be more than %d images", maxMediaItems))
+
+	for i, m := range media {
+		key := fmt.Sprintf("media[%d]", i)
+		v.Check(m.URL != "", key+".url", "must be provided")
+		v.Check(validator.IsURL(m.URL, "http", "https"), key+".url", "must be a valid http or https URL")
+		v.Check(imageHostPermitted(m.URL), key+".url", "host is not in the list of allowed image hosts")
+		v.Check(len(m.AltText) <= 1000, key+".alt_text", "must not be more than 1000 bytes long")
+		v.Check(len(m.Caption) <= 2000, key+".caption", "must not be more than 2000 bytes long")
+		v.Check(len(m.Credit) <= 500, key+".credit", "must not be more than 500 bytes long")
+		v.Check(m.Width >= 0, key+".width", "must not be negative")
+		v.Check(m.Height >= 0, key+".height", "must not be negative")
+	}
+}
+
+// replaceMedia заменяет изображения новости в рамках переданной транзакции.
+func replaceMedia(ctx context.Context, tx *sql.Tx, newsID int64, media []Media) error {
+	_, err := tx.ExecContext(ctx, `DELETE FROM news_media WHERE news_id = $1`, newsID)
+	if err != nil {
+		return err
+	}
+
+	query := `
+    INSERT INTO news_media (news_id, position, url, alt_text, caption, credit, width, height)
+    VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`
+
+	for i, m := range media {
+		_, err := tx.ExecContext(ctx, query, newsID, i+1, m.URL, m.AltText, m.Caption, m.Credit, m.Width, m.Height)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// queryer объединяет *sql.DB и *sql.Tx, чтобы загружать изображения как внутри
+// транзакции, так и вне её.
+type queryer interface {
+	QueryContext(ctx context.Context, query string, args ...any) (*sql.Rows, error)
+}
+
+// loadMedia загружает изображения для набора новостей одним запросом и раскладывает
+// их по новостям в исходном порядке.
+func loadMedia(ctx context.Context, q queryer, news ...*News) error {
+	if len(news) == 0 {
+		return nil
+	}
+
+	byID := make(map[int64]*News, len(news))
+	ids := make([]int64, 0, len(news))
+	for _, n := range news {
+		n.Media = []Media{}
+		byID[n.ID] = n
+		ids = append(ids, n.ID)
+	}
+
+	query := `
+    SELECT news_id, url, alt_text, caption, credit, width, height
+    FROM news_media
+    WHERE news_id = ANY($1)
+    ORDER BY news_id, position`
+
+	rows, err := q.QueryContext(ctx, query, pq.Array(ids))
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var newsID int64
+		var m Media
+		err := rows.Scan(&newsID, &m.URL, &m.AltText, &m.Caption, &m.Credit, &m.Width, &m.Height)
+		if err != nil {
+			return err
+		}
+		if n, ok := byID[newsID]; ok {
+			n.Media = append(n.Media, m)
+		}
+	}
+	if err = rows.Err(); err != nil {
+		return err
+	}
+	// Освобождаем соединение до следующего запроса.
+	rows.Close()
+
+	return loadRenditions(ctx, q, news...)
+}
@@ -3,6 +3,7 @@ package database
 import (
 	"database/sql"
 	"errors"
+	"time"
 )
 
 var (
@@ -10,29 +11,103 @@ var (
 	ErrEditConflict   = errors.New("edit conflict")
 )
 
+// NewsStore — операции с новостями. Его реализуют NewsModel, мок-модель и кэширующая
+// обёртка CachedNewsModel.
+type NewsStore interface {
+	Insert(news *News) error
+	InsertIdempotent(news *News, key IdempotencyKey) (bool, error)
+	InsertBatch(news []*News) error
+	Get(id int64) (*News, error)
+	GetMany(ids []int64) ([]*News, error)
+	Update(news *News) error
+	Delete(id int64, version int32) error
+	GetAll(nf NewsFilter, filters Filters) ([]*News, Metadata, error)
+	GetPublished(since time.Time, filters Filters) ([]*News, Metadata, error)
+	PublishedPages(pageSize int) ([]time.Time, error)
+	BulkPreview(op BulkOperation) (BulkCounts, error)
+	BulkApply(op BulkOperation, afterID int64, limit int) (BulkChunk, error)
+}
+
 type Models struct {
 	// Устанавливаем поле News как интерфейс, содержащий методы, которые должны поддерживать
 	// как 'реальная' модель, так и мок-модель.
-	News interface {
-		Insert(news *News) error
-		Get(id int64) (*News, error)
-		Update(news *News) error
+	News       NewsStore
+	Renditions interface {
+		Insert(sourceURL string, renditions []*Rendition) error
+	}
+	BulkJobs interface {
+		Insert(job *BulkJob) error
+		Get(id int64) (*BulkJob, error)
+		Claim(lease time.Duration) (*BulkJob, error)
+		SaveProgress(job *BulkJob, lease time.Duration) error
+	}
+	IdempotencyKeys interface {
+		DeleteExpired() (int64, error)
+	}
+	Webhooks interface {
+		Insert(webhook *Webhook) error
+		Get(id int64) (*Webhook, error)
+		Update(webhook *Webhook) error
 		Delete(id int64) error
-		GetAll(title string, categories []string, status string, filters Filters) ([]*News, Metadata, error)
+		GetAll() ([]*Webhook, error)
+	}
+	WebhookDeliveries interface {
+		Claim(limit int, lease time.Duration) ([]*WebhookDelivery, error)
+		RecordAttempt(d *WebhookDelivery, attempt *WebhookAttempt) error
+		GetAll(webhookID int64, status string, filters Filters) ([]*WebhookDelivery, Metadata, error)
+		Replay(webhookID, id int64) (*WebhookDelivery, error)
+	}
+
+	// Модели, читающие только с основного сервера в обход реплик и кэша.
+	primary *Models
+}
+
+// Primary возвращает модели, которые читают данные с основного сервера в обход реплик
+// и кэша. Их используют, когда прочитанное сразу же изменяется или должно отражать только
+// что сделанную запись: реплика может отставать. Изменения по-прежнему выполняются через
+// основные модели, чтобы кэш узнавал о них.
+func (m Models) Primary() Models {
+	if m.primary == nil {
+		return m
 	}
+	return *m.primary
 }
 
 // Создаем вспомогательную функцию, которая возвращает экземпляр Models, содержащий только мок-модели.
 func NewMockModels() Models {
 	return Models{
-		News: MockNewsModel{},
+		News:              MockNewsModel{},
+		Renditions:        MockRenditionModel{},
+		BulkJobs:          MockBulkJobModel{},
+		IdempotencyKeys:   MockIdempotencyKeyModel{},
+		Webhooks:          MockWebhookModel{},
+		WebhookDeliveries: MockWebhookDeliveryModel{},
 	}
 }
 
 // Для удобства мы также добавляем метод New(), который возвращает структуру Models
-// с инициализированным NewsModel.
-func NewModels(db *sql.DB) Models {
+// с инициализированным NewsModel. Если events равен nil, события не публикуются.
+// Если replicas не nil, Get и GetAll читают новости с реплик.
+func NewModels(db *sql.DB, replicas *ReplicaSet, excerptLength int, events EventPublisher) Models {
+	if events == nil {
+		events = noopPublisher{}
+	}
+	primary := newModels(db, excerptLength, events)
+	models := primary
+	models.primary = &primary
+	if replicas != nil {
+		models.News = NewsModel{DB: db, ExcerptLength: excerptLength, Events: events, Replicas: replicas}
+	}
+	return models
+}
+
+func newModels(db *sql.DB, excerptLength int, events EventPublisher) Models {
 	return Models{
-		News: NewsModel{DB: db},
+		News:              NewsModel{DB: db, ExcerptLength: excerptLength, Events: events},
+		Renditions:        RenditionModel{DB: db, Events: events},
+		BulkJobs:          BulkJobModel{DB: db},
+		IdempotencyKeys:   IdempotencyKeyModel{DB: db},
+		Webhooks:          WebhookModel{DB: db},
+		WebhookDeliveries: WebhookDeliveryModel{DB: db},
 	}
 }
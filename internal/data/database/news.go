@@ -5,63 +5,294 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"slices"
+	"sync/atomic"
 	"time"
 
+	"github.com/AnKlvy/news-service/internal/markup"
 	"github.com/AnKlvy/news-service/internal/validator"
 	"github.com/lib/pq"
 )
 
 type News struct {
-	ID         int64     `json:"id"`
-	CreatedAt  time.Time `json:"created_at"`
-	UpdatedAt  time.Time `json:"updated_at"`
-	Title      string    `json:"title"`
-	Content    string    `json:"content"`
-	Categories []string  `json:"categories"`
-	Status     string    `json:"status"`
-	ImageURLs  []string  `json:"image_urls,omitempty"`
-	Author     string    `json:"author"`
-	Version    int32     `json:"version"`
+	ID        int64     `json:"id"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+	Title     string    `json:"title"`
+	Content   string    `json:"content"`
+	// Формат исходного содержимого (plain, markdown или html) и его HTML-представление,
+	// которое сервис формирует и очищает при каждой записи.
+	ContentFormat string `json:"content_format"`
+	ContentHTML   string `json:"content_html,omitempty"`
+	// Статистика по тексту статьи, пересчитываемая при каждой записи: количество слов,
+	// примерное время чтения и короткая текстовая выдержка для превью.
+	WordCount   int32    `json:"word_count"`
+	ReadingTime Runtime  `json:"reading_time"`
+	Excerpt     string   `json:"excerpt"`
+	Categories  []string `json:"categories"`
+	Status      string   `json:"status"`
+	ImageURLs   []string `json:"image_urls,omitempty"`
+	Media       []Media  `json:"media,omitempty"`
+	Author      string   `json:"author"`
+	// Дата первой публикации. Заполняется базой данных, когда статья впервые
+	// переходит в статус PUBLISHED, и не меняется при последующих правках.
+	PublishedAt *time.Time `json:"published_at,omitempty"`
+	Version     int32      `json:"version"`
 }
 
 // ValidateNews выполняет валидацию данных новости.
 func ValidateNews(v *validator.Validator, news *News) {
 	v.Check(news.Title != "", "title", "must be provided")
-	v.Check(len(news.Title) <= 500, "title", "must not be more than 500 bytes long")
+	v.Check(len(news.Title) <= maxTitleBytes, "title", fmt.Sprintf("must not be more than %d bytes long", maxTitleBytes))
 
 	v.Check(news.Content != "", "content", "must be provided")
+	v.Check(validator.PermittedValue(news.ContentFormat, markup.Formats...), "content_format", "must be one of plain, markdown or html")
 	v.Check(news.Author != "", "author", "must be provided")
 	v.Check(news.Categories != nil, "categories", "must be provided")
-	v.Check(len(news.Categories) >= 1, "categories", "must contain at least 1 categories")
-	v.Check(len(news.Categories) <= 10, "categories", "must not contain more than 10 categories")
+	v.Check(len(news.Categories) >= minCategories, "categories", fmt.Sprintf("must contain at least %d categories", minCategories))
+	v.Check(len(news.Categories) <= maxCategories, "categories", fmt.Sprintf("must not contain more than %d categories", maxCategories))
 	v.Check(validator.Unique(news.Categories), "categories", "must not contain duplicate values")
 
 	v.Check(news.Status != "", "status", "must be provided")
-	v.Check(validator.PermittedValue(news.Status, "DRAFT", "PUBLISHED", "ARCHIVED"), "status", "must be a valid status")
+	v.Check(validator.PermittedValue(news.Status, NewsStatuses...), "status", "must be a valid status")
 
-	if news.ImageURLs != nil {
-		v.Check(len(news.ImageURLs) <= 7, "image_urls", "must not be more than 7 images")
+	// Старые клиенты передают только image_urls, поэтому проверяем изображения
+	// в том виде, в каком они будут сохранены.
+	media := news.Media
+	if len(media) == 0 {
+		media = MediaFromURLs(news.ImageURLs, nil)
 	}
+	ValidateMedia(v, media)
 }
 
+// NewsFilter содержит условия отбора новостей. Пустые значения полей означают,
+// что соответствующее условие не применяется.
+type NewsFilter struct {
+	Title      string   `json:"title,omitempty"`
+	Categories []string `json:"categories,omitempty"`
+	Status     string   `json:"status,omitempty"`
+	Author     string   `json:"author,omitempty"`
+	// Границы времени чтения в минутах, например MaxReadingTime = 3 для коротких статей.
+	MinReadingTime Runtime `json:"min_reading_time,omitempty"`
+	MaxReadingTime Runtime `json:"max_reading_time,omitempty"`
+}
+
+// IsEmpty сообщает, что фильтр не задаёт ни одного условия.
+func (f NewsFilter) IsEmpty() bool {
+	return f.Title == "" && len(f.Categories) == 0 && f.Status == "" && f.Author == "" &&
+		f.MinReadingTime == 0 && f.MaxReadingTime == 0
+}
+
+// ValidateNewsBatch проверяет каждую новость пакета. Ключи ошибок имеют вид
+// "items[<позиция>].<поле>", чтобы клиент мог сопоставить их с элементами запроса.
+func ValidateNewsBatch(v *validator.Validator, news []*News) {
+	for i, n := range news {
+		item := validator.New()
+		ValidateNews(item, n)
+		for key, message := range item.Errors {
+			v.AddError(fmt.Sprintf("items[%d].%s", i, key), message)
+		}
+	}
+}
+
+// ValidateNewsFilter проверяет условия отбора новостей.
+func ValidateNewsFilter(v *validator.Validator, f NewsFilter) {
+	if f.Status != "" {
+		v.Check(validator.PermittedValue(f.Status, NewsStatuses...), "status", "must be a valid status")
+	}
+	v.Check(f.MinReadingTime >= 0, "min_reading_time", "must not be negative")
+	v.Check(f.MaxReadingTime >= 0, "max_reading_time", "must not be negative")
+	if f.MinReadingTime > 0 && f.MaxReadingTime > 0 {
+		v.Check(f.MinReadingTime <= f.MaxReadingTime, "max_reading_time", "must not be less than min_reading_time")
+	}
+}
+
+// Допустимые значения сортировки для списка новостей.
+var NewsSortSafelist = []string{
+	"id", "title", "status", "reading_time",
+	"-id", "-title", "-status", "-reading_time",
+}
+
+// newsSortFields хранит значения сортировки, разрешённые в настройках. Пустой список
+// означает, что разрешены все значения из NewsSortSafelist.
+var newsSortFields atomic.Pointer[[]string]
+
+// SetNewsSortFields ограничивает допустимые значения сортировки списка новостей
+// подмножеством NewsSortSafelist. Значения вне NewsSortSafelist отбрасываются: в запрос
+// попадают только известные столбцы.
+func SetNewsSortFields(fields []string) {
+	permitted := make([]string, 0, len(fields))
+	for _, f := range fields {
+		if slices.Contains(NewsSortSafelist, f) {
+			permitted = append(permitted, f)
+		}
+	}
+	newsSortFields.Store(&permitted)
+}
+
+// NewsSortFields возвращает действующий список допустимых значений сортировки.
+func NewsSortFields() []string {
+	fields := newsSortFields.Load()
+	if fields == nil || len(*fields) == 0 {
+		return NewsSortSafelist
+	}
+	return *fields
+}
+
+// NewsUpdatableFields — поля новости, которые клиент может изменять. Используется для
+// проверки путей FieldMask в gRPC и ключей JSON Merge Patch в REST.
+var NewsUpdatableFields = []string{"title", "content", "content_format", "categories", "status", "image_urls", "media", "author"}
+
+// Ограничения новости. Эти же значения задают CHECK-ограничения таблицы news
+// (см. newsConstraints), и при запуске сервис сверяет их с базой данных.
+const (
+	minCategories = 1
+	maxCategories = 10
+	maxTitleBytes = 500
+)
+
+// NewsStatuses — допустимые статусы новости.
+var NewsStatuses = []string{"DRAFT", "PUBLISHED", "ARCHIVED"}
+
+// Средняя скорость чтения, по которой оценивается время чтения статьи.
+const wordsPerMinute = 200
+
 // Определяем структуру NewsModel, которая содержит пул соединений с базой данных.
 type NewsModel struct {
 	DB *sql.DB
+	// Максимальная длина выдержки из текста статьи в символах.
+	ExcerptLength int
+	// Получатель событий об изменениях новостей.
+	Events EventPublisher
+	// Реплики, на которые направляются Get и GetAll. Если nil, всё читается с основного сервера.
+	Replicas *ReplicaSet
 }
 
-func (m NewsModel) Insert(news *News) error {
-	query := `
-    INSERT INTO news (title, content, categories, status, image_urls, author)
-    VALUES ($1, $2, $3, $4, $5, $6)
-    RETURNING id, created_at, version`
-	args := []any{news.Title, news.Content, pq.Array(news.Categories), news.Status, pq.Array(news.ImageURLs), news.Author}
+// reader возвращает пул для запросов на чтение.
+func (m NewsModel) reader() *sql.DB {
+	if m.Replicas == nil {
+		return m.DB
+	}
+	return m.Replicas.Reader()
+}
 
+// prepareContent очищает HTML-содержимое от недопустимой разметки, формирует
+// HTML-представление статьи и пересчитывает статистику по тексту. Вызывается перед
+// каждой записью в базу данных.
+func (m NewsModel) prepareContent(news *News) error {
+	if news.ContentFormat == markup.FormatHTML {
+		news.Content = markup.Sanitize(news.Content)
+	}
+	contentHTML, err := markup.Render(news.ContentFormat, news.Content)
+	if err != nil {
+		return err
+	}
+	news.ContentHTML = contentHTML
+
+	text := markup.PlainText(contentHTML)
+	words := markup.WordCount(text)
+	news.WordCount = int32(words)
+	news.ReadingTime = Runtime((words + wordsPerMinute - 1) / wordsPerMinute)
+	news.Excerpt = markup.Excerpt(text, m.ExcerptLength)
+	return nil
+}
+
+func (m NewsModel) Insert(news *News) error {
 	// Создаём контекст с тайм-аутом 3 секунды.
 	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
 	defer cancel()
 
-	// Используем QueryRowContext() и передаём контекст в качестве первого аргумента.
-	return m.DB.QueryRowContext(ctx, query, args...).Scan(&news.ID, &news.CreatedAt, &news.Version)
+	return convertCheckViolation(m.insert(ctx, nil, news))
+}
+
+// InsertBatch сохраняет несколько новостей в одной транзакции: либо сохраняются все,
+// либо ни одной.
+func (m NewsModel) InsertBatch(news []*News) error {
+	// Пакет может содержать сотни записей, поэтому тайм-аут больше, чем у одиночных запросов.
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	return convertCheckViolation(m.insert(ctx, nil, news...))
+}
+
+// insert сохраняет новости вместе с их изображениями в одной транзакции и после её
+// фиксации публикует события о создании. Если передан key, в той же транзакции
+// записывается ключ идемпотентности, указывающий на первую новость.
+func (m NewsModel) insert(ctx context.Context, key *IdempotencyKey, news ...*News) error {
+	for _, n := range news {
+		if err := m.prepareContent(n); err != nil {
+			return err
+		}
+		NormalizeMedia(n)
+	}
+
+	query := `
+    INSERT INTO news (title, content, content_format, content_html, categories, status, image_urls, author,
+                      word_count, reading_time, excerpt, published_at)
+    VALUES ($1, $2, $3, $4, $5, $6::text, $7, $8, $9, $10, $11, CASE WHEN $6::text = 'PUBLISHED' THEN now() END)
+    RETURNING id, created_at, published_at, version`
+
+	// Новость и её изображения записываются в одной транзакции.
+	tx, err := m.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, n := range news {
+		args := []any{
+			n.Title,
+			n.Content,
+			n.ContentFormat,
+			n.ContentHTML,
+			pq.Array(n.Categories),
+			n.Status,
+			pq.Array(n.ImageURLs),
+			n.Author,
+			n.WordCount,
+			n.ReadingTime,
+			n.Excerpt,
+		}
+
+		// Используем QueryRowContext() и передаём контекст в качестве первого аргумента.
+		err = tx.QueryRowContext(ctx, query, args...).Scan(&n.ID, &n.CreatedAt, &n.PublishedAt, &n.Version)
+		if err != nil {
+			return err
+		}
+
+		if err = replaceMedia(ctx, tx, n.ID, n.Media); err != nil {
+			return err
+		}
+
+		n.UpdatedAt = n.CreatedAt
+		if err = notify(ctx, tx, EventCreated, n, nil); err != nil {
+			return err
+		}
+		if n.Status == "PUBLISHED" {
+			if err = notify(ctx, tx, EventPublished, n, nil); err != nil {
+				return err
+			}
+		}
+	}
+
+	if key != nil {
+		key.NewsID = news[0].ID
+		if err = saveIdempotencyKey(ctx, tx, key); err != nil {
+			return err
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return err
+	}
+
+	for _, n := range news {
+		m.Events.Publish(EventCreated, n, nil)
+		if n.Status == "PUBLISHED" {
+			m.Events.Publish(EventPublished, n, nil)
+		}
+	}
+	return nil
 }
 
 func (m NewsModel) Get(id int64) (*News, error) {
@@ -70,7 +301,8 @@ func (m NewsModel) Get(id int64) (*News, error) {
 	}
 
 	query := `
-    SELECT id, created_at, updated_at, title, content, categories, status, image_urls, author, version
+    SELECT id, created_at, updated_at, title, content, content_format, content_html, word_count, reading_time, excerpt,
+           categories, status, image_urls, author, published_at, version
     FROM news
     WHERE id = $1`
 
@@ -78,16 +310,25 @@ func (m NewsModel) Get(id int64) (*News, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
 	defer cancel()
 
-	err := m.DB.QueryRowContext(ctx, query, id).Scan(
+	// Новость и её изображения читаются с одного сервера, иначе при отставании реплики
+	// они могли бы относиться к разным версиям.
+	db := m.reader()
+	err := db.QueryRowContext(ctx, query, id).Scan(
 		&news.ID,
 		&news.CreatedAt,
 		&news.UpdatedAt,
 		&news.Title,
 		&news.Content,
+		&news.ContentFormat,
+		&news.ContentHTML,
+		&news.WordCount,
+		&news.ReadingTime,
+		&news.Excerpt,
 		pq.Array(&news.Categories),
 		&news.Status,
 		pq.Array(&news.ImageURLs),
 		&news.Author,
+		&news.PublishedAt,
 		&news.Version,
 	)
 	if err != nil {
@@ -98,22 +339,41 @@ func (m NewsModel) Get(id int64) (*News, error) {
 			return nil, err
 		}
 	}
+
+	if err = loadMedia(ctx, db, &news); err != nil {
+		return nil, err
+	}
 	return &news, nil
 }
 
 func (m NewsModel) Update(news *News) error {
+	if err := m.prepareContent(news); err != nil {
+		return err
+	}
+	NormalizeMedia(news)
+
 	query := `
     UPDATE news
-    SET title = $1, content = $2, categories = $3, status = $4, image_urls = $5, author = $6, updated_at = now(), version = version + 1
-    WHERE id = $7 AND version = $8
-    RETURNING version`
+    SET title = $1, content = $2, content_format = $3, content_html = $4, categories = $5, status = $6::text,
+        image_urls = $7, author = $8, word_count = $9, reading_time = $10, excerpt = $11, updated_at = now(),
+        published_at = CASE WHEN $6::text = 'PUBLISHED' THEN COALESCE(published_at, now()) ELSE published_at END,
+        version = version + 1
+    FROM (SELECT title, categories, status, author, reading_time, version FROM news WHERE id = $12) AS old
+    WHERE news.id = $12 AND news.version = $13
+    RETURNING news.updated_at, news.published_at, news.version,
+              old.title, old.categories, old.status, old.author, old.reading_time, old.version`
 	args := []any{
 		news.Title,
 		news.Content,
+		news.ContentFormat,
+		news.ContentHTML,
 		pq.Array(news.Categories),
 		news.Status,
 		pq.Array(news.ImageURLs),
 		news.Author,
+		news.WordCount,
+		news.ReadingTime,
+		news.Excerpt,
 		news.ID,
 		news.Version,
 	}
@@ -121,58 +381,216 @@ func (m NewsModel) Update(news *News) error {
 	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
 	defer cancel()
 
-	err := m.DB.QueryRowContext(ctx, query, args...).Scan(&news.Version)
+	tx, err := m.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// Прежний статус нужен, чтобы отличить публикацию от обычного изменения, а прежние
+	// поля фильтров — подписчикам, из выборки которых новость могла выйти.
+	previous := News{ID: news.ID}
+	err = tx.QueryRowContext(ctx, query, args...).Scan(
+		&news.UpdatedAt,
+		&news.PublishedAt,
+		&news.Version,
+		&previous.Title,
+		pq.Array(&previous.Categories),
+		&previous.Status,
+		&previous.Author,
+		&previous.ReadingTime,
+		&previous.Version,
+	)
 	if err != nil {
 		switch {
 		case errors.Is(err, sql.ErrNoRows):
 			return ErrEditConflict
 		default:
+			return convertCheckViolation(err)
+		}
+	}
+
+	if err = replaceMedia(ctx, tx, news.ID, news.Media); err != nil {
+		return err
+	}
+
+	published := news.Status == "PUBLISHED" && previous.Status != "PUBLISHED"
+	if err = notify(ctx, tx, EventUpdated, news, &previous); err != nil {
+		return err
+	}
+	if published {
+		if err = notify(ctx, tx, EventPublished, news, &previous); err != nil {
 			return err
 		}
 	}
+
+	if err = tx.Commit(); err != nil {
+		return err
+	}
+
+	m.Events.Publish(EventUpdated, news, &previous)
+	if published {
+		m.Events.Publish(EventPublished, news, &previous)
+	}
 	return nil
 }
 
-func (m NewsModel) Delete(id int64) error {
+// Delete удаляет новость. Если version больше нуля, новость удаляется только в этой
+// версии, иначе возвращается ErrEditConflict.
+func (m NewsModel) Delete(id int64, version int32) error {
 	if id < 1 {
 		return ErrRecordNotFound
 	}
+	// Удалённая запись возвращается целиком, чтобы передать её подписчикам в событии.
 	query := `
     DELETE FROM news
-    WHERE id = $1`
+    WHERE id = $1 AND ($2::integer = 0 OR version = $2::integer)
+    RETURNING id, created_at, updated_at, title, content, content_format, content_html, word_count, reading_time,
+              excerpt, categories, status, image_urls, author, published_at, version`
 
 	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
 	defer cancel()
 
-	result, err := m.DB.ExecContext(ctx, query, id)
+	tx, err := m.DB.BeginTx(ctx, nil)
 	if err != nil {
 		return err
 	}
-	rowsAffected, err := result.RowsAffected()
+	defer tx.Rollback()
+
+	// Изображения удаляются каскадно вместе с новостью, поэтому читаем их заранее.
+	news := News{ID: id}
+	if err = loadMedia(ctx, tx, &news); err != nil {
+		return err
+	}
+
+	err = tx.QueryRowContext(ctx, query, id, version).Scan(
+		&news.ID,
+		&news.CreatedAt,
+		&news.UpdatedAt,
+		&news.Title,
+		&news.Content,
+		&news.ContentFormat,
+		&news.ContentHTML,
+		&news.WordCount,
+		&news.ReadingTime,
+		&news.Excerpt,
+		pq.Array(&news.Categories),
+		&news.Status,
+		pq.Array(&news.ImageURLs),
+		&news.Author,
+		&news.PublishedAt,
+		&news.Version,
+	)
 	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows) && version > 0:
+			return ErrEditConflict
+		case errors.Is(err, sql.ErrNoRows):
+			return ErrRecordNotFound
+		default:
+			return err
+		}
+	}
+
+	if err = notify(ctx, tx, EventDeleted, &news, nil); err != nil {
 		return err
 	}
-	if rowsAffected == 0 {
-		return ErrRecordNotFound
+
+	if err = tx.Commit(); err != nil {
+		return err
 	}
+
+	m.Events.Publish(EventDeleted, &news, nil)
 	return nil
 }
 
-func (m NewsModel) GetAll(title string, categories []string, status string, filters Filters) ([]*News, Metadata, error) {
+// GetMany загружает новости по списку идентификаторов одним запросом. Результат
+// выровнен по ids: на месте отсутствующей записи стоит nil.
+func (m NewsModel) GetMany(ids []int64) ([]*News, error) {
+	query := `
+    SELECT id, created_at, updated_at, title, content, content_format, content_html, word_count, reading_time, excerpt,
+           categories, status, image_urls, author, published_at, version
+    FROM news
+    WHERE id = ANY($1)`
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	rows, err := m.DB.QueryContext(ctx, query, pq.Array(ids))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	found := []*News{}
+	byID := make(map[int64]*News, len(ids))
+	for rows.Next() {
+		var n News
+		err := rows.Scan(
+			&n.ID,
+			&n.CreatedAt,
+			&n.UpdatedAt,
+			&n.Title,
+			&n.Content,
+			&n.ContentFormat,
+			&n.ContentHTML,
+			&n.WordCount,
+			&n.ReadingTime,
+			&n.Excerpt,
+			pq.Array(&n.Categories),
+			&n.Status,
+			pq.Array(&n.ImageURLs),
+			&n.Author,
+			&n.PublishedAt,
+			&n.Version,
+		)
+		if err != nil {
+			return nil, err
+		}
+		found = append(found, &n)
+		byID[n.ID] = &n
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	rows.Close()
+
+	if err = loadMedia(ctx, m.DB, found...); err != nil {
+		return nil, err
+	}
+
+	news := make([]*News, len(ids))
+	for i, id := range ids {
+		news[i] = byID[id]
+	}
+	return news, nil
+}
+
+func (m NewsModel) GetAll(nf NewsFilter, filters Filters) ([]*News, Metadata, error) {
 	query := fmt.Sprintf(
-		`SELECT count(*) OVER(), id, created_at, updated_at, title, content, categories, status, image_urls, author, version
+		`SELECT count(*) OVER(), id, created_at, updated_at, title, content, content_format, content_html, word_count, reading_time, excerpt,
+		        categories, status, image_urls, author, published_at, version
 		 FROM news
 		 WHERE (to_tsvector('simple', title) @@ plainto_tsquery('simple', $1) OR $1 = '')
 		 AND (categories @> $2 OR $2 = '{}')
 		 AND (status = $3 OR $3 = '')
+		 AND (author = $4 OR $4 = '')
+		 AND (reading_time >= $5 OR $5 = 0)
+		 AND (reading_time <= $6 OR $6 = 0)
 		 ORDER BY %s %s, id ASC
-		 LIMIT $4 OFFSET $5`, filters.sortColumn(), filters.sortDirection())
+		 LIMIT $7 OFFSET $8`, filters.sortColumn(), filters.sortDirection())
 
 	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
 	defer cancel()
 
-	args := []any{title, pq.Array(categories), status, filters.limit(), filters.offset()}
-	rows, err := m.DB.QueryContext(ctx, query, args...)
+	categories := nf.Categories
+	if categories == nil {
+		categories = []string{}
+	}
+
+	args := []any{nf.Title, pq.Array(categories), nf.Status, nf.Author, nf.MinReadingTime, nf.MaxReadingTime, filters.limit(), filters.offset()}
+	db := m.reader()
+	rows, err := db.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, Metadata{}, err
 	}
@@ -190,10 +608,78 @@ func (m NewsModel) GetAll(title string, categories []string, status string, filt
 			&new.UpdatedAt,
 			&new.Title,
 			&new.Content,
+			&new.ContentFormat,
+			&new.ContentHTML,
+			&new.WordCount,
+			&new.ReadingTime,
+			&new.Excerpt,
+			pq.Array(&new.Categories),
+			&new.Status,
+			pq.Array(&new.ImageURLs),
+			&new.Author,
+			&new.PublishedAt,
+			&new.Version,
+		)
+		if err != nil {
+			return nil, Metadata{}, err
+		}
+		news = append(news, &new)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, Metadata{}, err
+	}
+	// Освобождаем соединение до загрузки изображений.
+	rows.Close()
+
+	if err = loadMedia(ctx, db, news...); err != nil {
+		return nil, Metadata{}, err
+	}
+
+	metadata := calculateMetadata(totalRecords, filters.Page, filters.PageSize)
+
+	return news, metadata, nil
+}
+
+// GetPublished возвращает опубликованные новости, впервые опубликованные не раньше since
+// (нулевое значение since снимает ограничение). Содержимое статей не выбирается: метод
+// предназначен для карт сайта, где нужны только адреса, даты и изображения.
+func (m NewsModel) GetPublished(since time.Time, filters Filters) ([]*News, Metadata, error) {
+	query := fmt.Sprintf(
+		`SELECT count(*) OVER(), id, created_at, updated_at, title, categories, status, image_urls, author, published_at, version
+		 FROM news
+		 WHERE status = 'PUBLISHED'
+		 AND (published_at >= $1 OR $1 IS NULL)
+		 ORDER BY %s %s, id ASC
+		 LIMIT $2 OFFSET $3`, filters.sortColumn(), filters.sortDirection())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	sinceArg := sql.NullTime{Time: since, Valid: !since.IsZero()}
+
+	rows, err := m.DB.QueryContext(ctx, query, sinceArg, filters.limit(), filters.offset())
+	if err != nil {
+		return nil, Metadata{}, err
+	}
+	defer rows.Close()
+
+	totalRecords := 0
+	news := []*News{}
+
+	for rows.Next() {
+		var new News
+		err := rows.Scan(
+			&totalRecords,
+			&new.ID,
+			&new.CreatedAt,
+			&new.UpdatedAt,
+			&new.Title,
 			pq.Array(&new.Categories),
 			&new.Status,
 			pq.Array(&new.ImageURLs),
 			&new.Author,
+			&new.PublishedAt,
 			&new.Version,
 		)
 		if err != nil {
@@ -211,12 +697,70 @@ func (m NewsModel) GetAll(title string, categories []string, status string, filt
 	return news, metadata, nil
 }
 
+// PublishedPages делит опубликованные новости, упорядоченные по id, на страницы по
+// pageSize записей и возвращает для каждой страницы время последнего изменения её
+// новостей. Количество элементов результата равно количеству страниц.
+func (m NewsModel) PublishedPages(pageSize int) ([]time.Time, error) {
+	query := `
+		SELECT max(updated_at)
+		FROM (
+			SELECT updated_at, (row_number() OVER (ORDER BY id) - 1) / $1 AS page
+			FROM news
+			WHERE status = 'PUBLISHED'
+		) AS published
+		GROUP BY page
+		ORDER BY page`
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	rows, err := m.reader().QueryContext(ctx, query, pageSize)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	pages := []time.Time{}
+	for rows.Next() {
+		var updatedAt time.Time
+		if err := rows.Scan(&updatedAt); err != nil {
+			return nil, err
+		}
+		pages = append(pages, updatedAt)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return pages, nil
+}
+
 type MockNewsModel struct{}
 
 func (m MockNewsModel) Insert(news *News) error {
 	return nil
 }
 
+func (m MockNewsModel) InsertIdempotent(news *News, key IdempotencyKey) (bool, error) {
+	return false, nil
+}
+
+func (m MockNewsModel) InsertBatch(news []*News) error {
+	return nil
+}
+
+func (m MockNewsModel) GetMany(ids []int64) ([]*News, error) {
+	return make([]*News, len(ids)), nil
+}
+
+func (m MockNewsModel) BulkPreview(op BulkOperation) (BulkCounts, error) {
+	return BulkCounts{}, nil
+}
+
+func (m MockNewsModel) BulkApply(op BulkOperation, afterID int64, limit int) (BulkChunk, error) {
+	return BulkChunk{Done: true}, nil
+}
+
 func (m MockNewsModel) Get(id int64) (*News, error) {
 	return nil, nil
 }
@@ -225,10 +769,18 @@ func (m MockNewsModel) Update(news *News) error {
 	return nil
 }
 
-func (m MockNewsModel) Delete(id int64) error {
+func (m MockNewsModel) Delete(id int64, version int32) error {
 	return nil
 }
 
-func (m MockNewsModel) GetAll(title string, categories []string, status string, filters Filters) ([]*News, Metadata, error) {
+func (m MockNewsModel) GetAll(nf NewsFilter, filters Filters) ([]*News, Metadata, error) {
+	return nil, Metadata{}, nil
+}
+
+func (m MockNewsModel) GetPublished(since time.Time, filters Filters) ([]*News, Metadata, error) {
 	return nil, Metadata{}, nil
 }
+
+func (m MockNewsModel) PublishedPages(pageSize int) ([]time.Time, error) {
+	return nil, nil
+}
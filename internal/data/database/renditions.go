@@ -0,0 +1,163 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// Rendition — уменьшенная копия изображения, загруженного через сервис.
+type Rendition struct {
+	URL         string `json:"url"`
+	Width       int32  `json:"width"`
+	Height      int32  `json:"height"`
+	ContentType string `json:"content_type"`
+}
+
+// RenditionModel хранит сведения о сгенерированных уменьшенных копиях изображений.
+// Копии привязаны к адресу исходного изображения, поэтому автоматически появляются
+// у всех новостей, которые ссылаются на это изображение.
+type RenditionModel struct {
+	DB *sql.DB
+	// Получатель событий об изменениях новостей.
+	Events EventPublisher
+}
+
+// Insert сохраняет уменьшенные копии изображения sourceURL. Копии меняют представление
+// новостей, которые ссылаются на изображение, поэтому у этих новостей увеличивается
+// версия и публикуется событие об изменении: иначе кэши, ETag и подписчики продолжали
+// бы видеть новость без копий.
+func (m RenditionModel) Insert(sourceURL string, renditions []*Rendition) error {
+	insertQuery := `
+    INSERT INTO media_renditions (source_url, width, height, url, content_type)
+    VALUES ($1, $2, $3, $4, $5)
+    ON CONFLICT (source_url, width) DO UPDATE
+    SET height = EXCLUDED.height, url = EXCLUDED.url, content_type = EXCLUDED.content_type`
+
+	updateQuery := `
+    UPDATE news
+    SET updated_at = now(), version = version + 1
+    WHERE id IN (SELECT news_id FROM news_media WHERE url = $1)
+    RETURNING id, created_at, updated_at, title, content, content_format, content_html, word_count, reading_time,
+              excerpt, categories, status, image_urls, author, published_at, version`
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	tx, err := m.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, r := range renditions {
+		_, err = tx.ExecContext(ctx, insertQuery, sourceURL, r.Width, r.Height, r.URL, r.ContentType)
+		if err != nil {
+			return err
+		}
+	}
+
+	rows, err := tx.QueryContext(ctx, updateQuery, sourceURL)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	updated := []*News{}
+	for rows.Next() {
+		var n News
+		err := rows.Scan(
+			&n.ID,
+			&n.CreatedAt,
+			&n.UpdatedAt,
+			&n.Title,
+			&n.Content,
+			&n.ContentFormat,
+			&n.ContentHTML,
+			&n.WordCount,
+			&n.ReadingTime,
+			&n.Excerpt,
+			pq.Array(&n.Categories),
+			&n.Status,
+			pq.Array(&n.ImageURLs),
+			&n.Author,
+			&n.PublishedAt,
+			&n.Version,
+		)
+		if err != nil {
+			return err
+		}
+		updated = append(updated, &n)
+	}
+	if err = rows.Err(); err != nil {
+		return err
+	}
+
+	if err = loadMedia(ctx, tx, updated...); err != nil {
+		return err
+	}
+	for _, n := range updated {
+		if err = notify(ctx, tx, EventUpdated, n, nil); err != nil {
+			return err
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return err
+	}
+
+	for _, n := range updated {
+		m.Events.Publish(EventUpdated, n, nil)
+	}
+	return nil
+}
+
+// loadRenditions дополняет изображения новостей сведениями об их уменьшенных копиях.
+func loadRenditions(ctx context.Context, q queryer, news ...*News) error {
+	byURL := make(map[string][]*Media)
+	urls := []string{}
+	for _, n := range news {
+		for i := range n.Media {
+			m := &n.Media[i]
+			if _, ok := byURL[m.URL]; !ok {
+				urls = append(urls, m.URL)
+			}
+			byURL[m.URL] = append(byURL[m.URL], m)
+		}
+	}
+	if len(urls) == 0 {
+		return nil
+	}
+
+	query := `
+    SELECT source_url, url, width, height, content_type
+    FROM media_renditions
+    WHERE source_url = ANY($1)
+    ORDER BY source_url, width`
+
+	rows, err := q.QueryContext(ctx, query, pq.Array(urls))
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var sourceURL string
+		var r Rendition
+		if err := rows.Scan(&sourceURL, &r.URL, &r.Width, &r.Height, &r.ContentType); err != nil {
+			return err
+		}
+		for _, m := range byURL[sourceURL] {
+			m.Renditions = append(m.Renditions, r)
+		}
+	}
+	return rows.Err()
+}
+
+type MockRenditionModel struct{}
+
+func (m MockRenditionModel) Insert(sourceURL string, renditions []*Rendition) error {
+	return nil
+}
@@ -0,0 +1,123 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/AnKlvy/news-service/internal/jsonlog"
+)
+
+// ReplicaSet распределяет чтение между репликами базы данных. Реплика, не ответившая
+// на проверку, исключается до следующей успешной проверки; если исправных реплик нет,
+// чтение выполняется на основном сервере.
+type ReplicaSet struct {
+	primary  *sql.DB
+	replicas []*replica
+	next     atomic.Uint64
+	logger   *jsonlog.Logger
+
+	stop chan struct{}
+	wg   sync.WaitGroup
+}
+
+type replica struct {
+	name    string
+	db      *sql.DB
+	healthy atomic.Bool
+	checked bool
+}
+
+// NewReplicaSet создаёт набор реплик. names используются только в логах и не должны
+// содержать пароли.
+func NewReplicaSet(primary *sql.DB, replicas []*sql.DB, names []string, logger *jsonlog.Logger) *ReplicaSet {
+	rs := &ReplicaSet{
+		primary: primary,
+		logger:  logger,
+		stop:    make(chan struct{}),
+	}
+	for i, db := range replicas {
+		rs.replicas = append(rs.replicas, &replica{name: names[i], db: db})
+	}
+	return rs
+}
+
+// Reader возвращает пул для запроса на чтение: очередную исправную реплику или
+// основной сервер.
+func (rs *ReplicaSet) Reader() *sql.DB {
+	if rs == nil {
+		return nil
+	}
+	n := len(rs.replicas)
+	start := rs.next.Add(1)
+	for i := 0; i < n; i++ {
+		r := rs.replicas[(start+uint64(i))%uint64(n)]
+		if r.healthy.Load() {
+			return r.db
+		}
+	}
+	return rs.primary
+}
+
+// Start проверяет реплики и запускает их периодическую проверку. До первой успешной
+// проверки реплика не используется.
+func (rs *ReplicaSet) Start(interval time.Duration) {
+	for _, r := range rs.replicas {
+		rs.check(r)
+	}
+
+	rs.wg.Add(1)
+	go func() {
+		defer rs.wg.Done()
+		for {
+			select {
+			case <-rs.stop:
+				return
+			case <-time.After(interval):
+			}
+			for _, r := range rs.replicas {
+				rs.check(r)
+			}
+		}
+	}()
+}
+
+// Shutdown останавливает проверки и закрывает пулы реплик.
+func (rs *ReplicaSet) Shutdown() {
+	close(rs.stop)
+	rs.wg.Wait()
+	for _, r := range rs.replicas {
+		r.db.Close()
+	}
+}
+
+// Healthy возвращает состояние реплик по их именам.
+func (rs *ReplicaSet) Healthy() map[string]bool {
+	status := make(map[string]bool, len(rs.replicas))
+	for _, r := range rs.replicas {
+		status[r.name] = r.healthy.Load()
+	}
+	return status
+}
+
+func (rs *ReplicaSet) check(r *replica) {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	err := r.db.PingContext(ctx)
+	healthy := err == nil
+	// В лог попадает первая проверка и каждое изменение состояния.
+	if r.healthy.Swap(healthy) == healthy && r.checked {
+		return
+	}
+	r.checked = true
+
+	properties := map[string]string{"component": "replicas", "replica": r.name}
+	if healthy {
+		rs.logger.PrintInfo("replica is in rotation", properties)
+	} else {
+		rs.logger.PrintError(err, properties)
+	}
+}
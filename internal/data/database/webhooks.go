@@ -0,0 +1,507 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/AnKlvy/news-service/internal/validator"
+	"github.com/lib/pq"
+)
+
+// Webhook — адрес внешней системы, которая получает уведомления о событиях новостей.
+// Секрет используется для подписи тела запроса и возвращается клиенту только при
+// создании подписки.
+type Webhook struct {
+	ID         int64     `json:"id"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+	URL        string    `json:"url"`
+	Secret     string    `json:"secret,omitempty"`
+	EventTypes []string  `json:"event_types"`
+	Active     bool      `json:"active"`
+	Version    int32     `json:"version"`
+}
+
+// WebhookEventTypes — события, на которые можно подписать webhook.
+var WebhookEventTypes = []string{EventCreated, EventUpdated, EventDeleted, EventPublished}
+
+// Минимальная длина секрета для подписи уведомлений.
+const webhookSecretMinLength = 16
+
+func ValidateWebhook(v *validator.Validator, webhook *Webhook) {
+	v.Check(webhook.URL != "", "url", "must be provided")
+	v.Check(webhook.URL == "" || validator.IsURL(webhook.URL, "http", "https"), "url", "must be an absolute http or https URL")
+	v.Check(len(webhook.URL) <= 2048, "url", "must not be more than 2048 bytes long")
+
+	v.Check(len(webhook.Secret) >= webhookSecretMinLength, "secret", "must be at least 16 bytes long")
+	v.Check(len(webhook.Secret) <= 256, "secret", "must not be more than 256 bytes long")
+
+	v.Check(len(webhook.EventTypes) >= 1, "event_types", "must contain at least 1 event type")
+	v.Check(validator.Unique(webhook.EventTypes), "event_types", "must not contain duplicate values")
+	for _, t := range webhook.EventTypes {
+		v.Check(validator.PermittedValue(t, WebhookEventTypes...), "event_types", "must contain only created, updated, deleted or published")
+	}
+}
+
+type WebhookModel struct {
+	DB *sql.DB
+}
+
+func (m WebhookModel) Insert(webhook *Webhook) error {
+	query := `
+    INSERT INTO webhooks (url, secret, event_types, active)
+    VALUES ($1, $2, $3, $4)
+    RETURNING id, created_at, updated_at, version`
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	args := []any{webhook.URL, webhook.Secret, pq.Array(webhook.EventTypes), webhook.Active}
+	return m.DB.QueryRowContext(ctx, query, args...).Scan(&webhook.ID, &webhook.CreatedAt, &webhook.UpdatedAt, &webhook.Version)
+}
+
+func (m WebhookModel) Get(id int64) (*Webhook, error) {
+	if id < 1 {
+		return nil, ErrRecordNotFound
+	}
+
+	query := `
+    SELECT id, created_at, updated_at, url, secret, event_types, active, version
+    FROM webhooks
+    WHERE id = $1`
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	var webhook Webhook
+	err := m.DB.QueryRowContext(ctx, query, id).Scan(
+		&webhook.ID,
+		&webhook.CreatedAt,
+		&webhook.UpdatedAt,
+		&webhook.URL,
+		&webhook.Secret,
+		pq.Array(&webhook.EventTypes),
+		&webhook.Active,
+		&webhook.Version,
+	)
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return nil, ErrRecordNotFound
+		default:
+			return nil, err
+		}
+	}
+	return &webhook, nil
+}
+
+func (m WebhookModel) Update(webhook *Webhook) error {
+	query := `
+    UPDATE webhooks
+    SET url = $1, secret = $2, event_types = $3, active = $4, updated_at = now(), version = version + 1
+    WHERE id = $5 AND version = $6
+    RETURNING updated_at, version`
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	args := []any{webhook.URL, webhook.Secret, pq.Array(webhook.EventTypes), webhook.Active, webhook.ID, webhook.Version}
+	err := m.DB.QueryRowContext(ctx, query, args...).Scan(&webhook.UpdatedAt, &webhook.Version)
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return ErrEditConflict
+		default:
+			return err
+		}
+	}
+	return nil
+}
+
+func (m WebhookModel) Delete(id int64) error {
+	if id < 1 {
+		return ErrRecordNotFound
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	result, err := m.DB.ExecContext(ctx, `DELETE FROM webhooks WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return ErrRecordNotFound
+	}
+	return nil
+}
+
+// GetAll возвращает все подписки. Их немного, поэтому постраничная выдача не нужна.
+func (m WebhookModel) GetAll() ([]*Webhook, error) {
+	query := `
+    SELECT id, created_at, updated_at, url, secret, event_types, active, version
+    FROM webhooks
+    ORDER BY id`
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	rows, err := m.DB.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	webhooks := []*Webhook{}
+	for rows.Next() {
+		var webhook Webhook
+		err := rows.Scan(
+			&webhook.ID,
+			&webhook.CreatedAt,
+			&webhook.UpdatedAt,
+			&webhook.URL,
+			&webhook.Secret,
+			pq.Array(&webhook.EventTypes),
+			&webhook.Active,
+			&webhook.Version,
+		)
+		if err != nil {
+			return nil, err
+		}
+		webhooks = append(webhooks, &webhook)
+	}
+	return webhooks, rows.Err()
+}
+
+// Состояния доставки уведомления. Доставка, исчерпавшая все попытки, помечается как
+// dead и больше не отправляется, пока её не запустят повторно вручную.
+const (
+	DeliveryPending   = "pending"
+	DeliveryDelivered = "delivered"
+	DeliveryDead      = "dead"
+)
+
+// WebhookDelivery — уведомление о событии для одной подписки вместе с историей попыток
+// его отправить.
+type WebhookDelivery struct {
+	ID            int64            `json:"id"`
+	WebhookID     int64            `json:"webhook_id"`
+	EventType     string           `json:"event_type"`
+	Payload       json.RawMessage  `json:"payload"`
+	Status        string           `json:"status"`
+	Attempts      int32            `json:"attempts"`
+	NextAttemptAt time.Time        `json:"next_attempt_at"`
+	LastError     string           `json:"last_error,omitempty"`
+	CreatedAt     time.Time        `json:"created_at"`
+	DeliveredAt   *time.Time       `json:"delivered_at,omitempty"`
+	History       []WebhookAttempt `json:"history"`
+	// Адрес и секрет подписки заполняются только для диспетчера.
+	URL    string `json:"-"`
+	Secret string `json:"-"`
+}
+
+// WebhookAttempt — результат одной попытки отправить уведомление.
+type WebhookAttempt struct {
+	AttemptedAt    time.Time `json:"attempted_at"`
+	ResponseStatus int32     `json:"response_status,omitempty"`
+	Error          string    `json:"error,omitempty"`
+	DurationMS     int32     `json:"duration_ms"`
+}
+
+// DeliverySortSafelist — допустимые значения сортировки списка доставок.
+var DeliverySortSafelist = []string{"id", "-id"}
+
+type WebhookDeliveryModel struct {
+	DB *sql.DB
+}
+
+// Claim выбирает доставки, время отправки которых наступило, и откладывает их на lease,
+// чтобы другие диспетчеры (в том числе в других экземплярах сервиса) не взяли их
+// одновременно. Если диспетчер не успеет записать результат, доставка снова станет
+// доступной по истечении lease.
+func (m WebhookDeliveryModel) Claim(limit int, lease time.Duration) ([]*WebhookDelivery, error) {
+	query := `
+    WITH due AS (
+        SELECT d.id
+        FROM webhook_deliveries d
+        JOIN webhooks w ON w.id = d.webhook_id
+        WHERE d.status = 'pending' AND d.next_attempt_at <= now() AND w.active
+        ORDER BY d.next_attempt_at, d.id
+        LIMIT $1
+        FOR UPDATE OF d SKIP LOCKED
+    )
+    UPDATE webhook_deliveries d
+    SET next_attempt_at = now() + make_interval(secs => $2)
+    FROM due, webhooks w
+    WHERE d.id = due.id AND w.id = d.webhook_id
+    RETURNING d.id, d.webhook_id, d.event_type, d.payload, d.status, d.attempts, d.next_attempt_at, d.last_error,
+              d.created_at, w.url, w.secret`
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	rows, err := m.DB.QueryContext(ctx, query, limit, lease.Seconds())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	deliveries := []*WebhookDelivery{}
+	for rows.Next() {
+		var d WebhookDelivery
+		err := rows.Scan(
+			&d.ID,
+			&d.WebhookID,
+			&d.EventType,
+			&d.Payload,
+			&d.Status,
+			&d.Attempts,
+			&d.NextAttemptAt,
+			&d.LastError,
+			&d.CreatedAt,
+			&d.URL,
+			&d.Secret,
+		)
+		if err != nil {
+			return nil, err
+		}
+		deliveries = append(deliveries, &d)
+	}
+	return deliveries, rows.Err()
+}
+
+// RecordAttempt сохраняет результат попытки и новое состояние доставки: статус,
+// количество попыток, время следующей попытки и последнюю ошибку.
+func (m WebhookDeliveryModel) RecordAttempt(d *WebhookDelivery, attempt *WebhookAttempt) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	tx, err := m.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx, `
+    INSERT INTO webhook_attempts (delivery_id, attempted_at, response_status, error, duration_ms)
+    VALUES ($1, $2, $3, $4, $5)`,
+		d.ID, attempt.AttemptedAt, attempt.ResponseStatus, attempt.Error, attempt.DurationMS)
+	if err != nil {
+		return err
+	}
+
+	_, err = tx.ExecContext(ctx, `
+    UPDATE webhook_deliveries
+    SET status = $1, attempts = $2, next_attempt_at = $3, last_error = $4,
+        delivered_at = CASE WHEN $1 = 'delivered' THEN now() ELSE delivered_at END
+    WHERE id = $5`,
+		d.Status, d.Attempts, d.NextAttemptAt, d.LastError, d.ID)
+	if err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// GetAll возвращает доставки подписки вместе с историей попыток. Пустой status
+// означает доставки в любом состоянии.
+func (m WebhookDeliveryModel) GetAll(webhookID int64, status string, filters Filters) ([]*WebhookDelivery, Metadata, error) {
+	query := fmt.Sprintf(`
+    SELECT count(*) OVER(), id, webhook_id, event_type, payload, status, attempts, next_attempt_at, last_error,
+           created_at, delivered_at
+    FROM webhook_deliveries
+    WHERE webhook_id = $1 AND (status = $2 OR $2 = '')
+    ORDER BY %s %s
+    LIMIT $3 OFFSET $4`, filters.sortColumn(), filters.sortDirection())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	rows, err := m.DB.QueryContext(ctx, query, webhookID, status, filters.limit(), filters.offset())
+	if err != nil {
+		return nil, Metadata{}, err
+	}
+	defer rows.Close()
+
+	totalRecords := 0
+	deliveries := []*WebhookDelivery{}
+	for rows.Next() {
+		var d WebhookDelivery
+		err := rows.Scan(
+			&totalRecords,
+			&d.ID,
+			&d.WebhookID,
+			&d.EventType,
+			&d.Payload,
+			&d.Status,
+			&d.Attempts,
+			&d.NextAttemptAt,
+			&d.LastError,
+			&d.CreatedAt,
+			&d.DeliveredAt,
+		)
+		if err != nil {
+			return nil, Metadata{}, err
+		}
+		d.History = []WebhookAttempt{}
+		deliveries = append(deliveries, &d)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, Metadata{}, err
+	}
+	rows.Close()
+
+	if err = loadAttempts(ctx, m.DB, deliveries...); err != nil {
+		return nil, Metadata{}, err
+	}
+
+	return deliveries, calculateMetadata(totalRecords, filters.Page, filters.PageSize), nil
+}
+
+// Replay ставит доставку в очередь на немедленную отправку и восстанавливает
+// количество доступных попыток. Используется для доставок в состоянии dead, но
+// позволяет и повторно отправить уже доставленное уведомление.
+func (m WebhookDeliveryModel) Replay(webhookID, id int64) (*WebhookDelivery, error) {
+	query := `
+    UPDATE webhook_deliveries
+    SET status = 'pending', attempts = 0, next_attempt_at = now(), last_error = '', delivered_at = NULL
+    WHERE id = $1 AND webhook_id = $2
+    RETURNING id, webhook_id, event_type, payload, status, attempts, next_attempt_at, last_error, created_at`
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+
+	var d WebhookDelivery
+	err := m.DB.QueryRowContext(ctx, query, id, webhookID).Scan(
+		&d.ID,
+		&d.WebhookID,
+		&d.EventType,
+		&d.Payload,
+		&d.Status,
+		&d.Attempts,
+		&d.NextAttemptAt,
+		&d.LastError,
+		&d.CreatedAt,
+	)
+	if err != nil {
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			return nil, ErrRecordNotFound
+		default:
+			return nil, err
+		}
+	}
+
+	d.History = []WebhookAttempt{}
+	if err = loadAttempts(ctx, m.DB, &d); err != nil {
+		return nil, err
+	}
+	return &d, nil
+}
+
+// loadAttempts загружает историю попыток для набора доставок одним запросом.
+func loadAttempts(ctx context.Context, q queryer, deliveries ...*WebhookDelivery) error {
+	if len(deliveries) == 0 {
+		return nil
+	}
+
+	ids := make([]int64, 0, len(deliveries))
+	byID := make(map[int64]*WebhookDelivery, len(deliveries))
+	for _, d := range deliveries {
+		ids = append(ids, d.ID)
+		byID[d.ID] = d
+	}
+
+	query := `
+    SELECT delivery_id, attempted_at, response_status, error, duration_ms
+    FROM webhook_attempts
+    WHERE delivery_id = ANY($1)
+    ORDER BY delivery_id, id`
+
+	rows, err := q.QueryContext(ctx, query, pq.Array(ids))
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var deliveryID int64
+		var a WebhookAttempt
+		if err := rows.Scan(&deliveryID, &a.AttemptedAt, &a.ResponseStatus, &a.Error, &a.DurationMS); err != nil {
+			return err
+		}
+		byID[deliveryID].History = append(byID[deliveryID].History, a)
+	}
+	return rows.Err()
+}
+
+// enqueueWebhooks добавляет в очередь исходящих уведомлений по одной доставке для
+// каждой активной подписки на событие. Вызывается в транзакции изменения новости,
+// поэтому уведомление не может потеряться или уйти об отменённом изменении.
+func enqueueWebhooks(ctx context.Context, tx *sql.Tx, eventType string, news *News) error {
+	payload, err := json.Marshal(map[string]any{
+		"event":       eventType,
+		"occurred_at": time.Now().UTC(),
+		"news":        news,
+	})
+	if err != nil {
+		return err
+	}
+
+	query := `
+    INSERT INTO webhook_deliveries (webhook_id, event_type, payload)
+    SELECT id, $1, $2
+    FROM webhooks
+    WHERE active AND $1 = ANY(event_types)`
+
+	_, err = tx.ExecContext(ctx, query, eventType, string(payload))
+	return err
+}
+
+type MockWebhookModel struct{}
+
+func (m MockWebhookModel) Insert(webhook *Webhook) error {
+	return nil
+}
+
+func (m MockWebhookModel) Get(id int64) (*Webhook, error) {
+	return nil, nil
+}
+
+func (m MockWebhookModel) Update(webhook *Webhook) error {
+	return nil
+}
+
+func (m MockWebhookModel) Delete(id int64) error {
+	return nil
+}
+
+func (m MockWebhookModel) GetAll() ([]*Webhook, error) {
+	return nil, nil
+}
+
+type MockWebhookDeliveryModel struct{}
+
+func (m MockWebhookDeliveryModel) Claim(limit int, lease time.Duration) ([]*WebhookDelivery, error) {
+	return nil, nil
+}
+
+func (m MockWebhookDeliveryModel) RecordAttempt(d *WebhookDelivery, attempt *WebhookAttempt) error {
+	return nil
+}
+
+func (m MockWebhookDeliveryModel) GetAll(webhookID int64, status string, filters Filters) ([]*WebhookDelivery, Metadata, error) {
+	return nil, Metadata{}, nil
+}
+
+func (m MockWebhookDeliveryModel) Replay(webhookID, id int64) (*WebhookDelivery, error) {
+	return nil, nil
+}
@@ -0,0 +1,73 @@
+package media
+
+import (
+	"bytes"
+	"errors"
+	"io"
+
+	"github.com/AnKlvy/news-service/internal/media"
+	"github.com/AnKlvy/news-service/protobuf/gen_news"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type Service struct {
+	uploader *media.Uploader
+	news_proto.UnimplementedMediaServiceServer
+}
+
+func NewMediaService(grpc *grpc.Server, uploader *media.Uploader) {
+	mediaService := &Service{uploader: uploader}
+	news_proto.RegisterMediaServiceServer(grpc, mediaService)
+}
+
+// UploadMedia принимает файл потоком: первое сообщение содержит сведения о файле,
+// остальные — его части. Файл сохраняется после получения последней части.
+func (s *Service) UploadMedia(stream news_proto.MediaService_UploadMediaServer) error {
+	first, err := stream.Recv()
+	if err != nil {
+		return err
+	}
+	info := first.GetInfo()
+	if info == nil {
+		return status.Error(codes.InvalidArgument, "the first message must contain file info")
+	}
+
+	var buf bytes.Buffer
+	for {
+		req, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return err
+		}
+
+		// Прерываем приём сразу, как только размер превысил лимит, не дожидаясь конца потока.
+		buf.Write(req.GetChunk())
+		if int64(buf.Len()) > s.uploader.MaxSize {
+			return status.Error(codes.InvalidArgument, media.ErrTooLarge.Error())
+		}
+	}
+
+	obj, err := s.uploader.Upload(stream.Context(), &buf, info.GetContentType())
+	if err != nil {
+		switch {
+		case errors.Is(err, media.ErrTooLarge),
+			errors.Is(err, media.ErrEmpty),
+			errors.Is(err, media.ErrUnsupportedType),
+			errors.Is(err, media.ErrContentTypeMismatch):
+			return status.Error(codes.InvalidArgument, err.Error())
+		default:
+			return err
+		}
+	}
+
+	return stream.SendAndClose(&news_proto.UploadMediaResponse{
+		Name:        obj.Name,
+		Url:         obj.URL,
+		ContentType: obj.ContentType,
+		Size:        obj.Size,
+	})
+}
@@ -3,50 +3,101 @@ package news
 import (
 	"context"
 	"errors"
+	"sort"
+	"time"
+
 	"github.com/AnKlvy/news-service/internal/data/database"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/known/emptypb"
 
+	"github.com/AnKlvy/news-service/internal/events"
+	"github.com/AnKlvy/news-service/internal/markup"
 	"github.com/AnKlvy/news-service/internal/validator"
 	"github.com/AnKlvy/news-service/protobuf/gen_news"
 
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
+// Config — ограничения сервиса новостей.
+type Config struct {
+	// Максимальное количество новостей в BatchCreateNews.
+	MaxBatchCreate int
+	// Максимальное количество идентификаторов в BatchGetNews.
+	MaxBatchGet int
+	// Сколько хранится ключ идемпотентности, переданный в CreateNewsHandler.
+	IdempotencyTTL time.Duration
+}
+
+// Ключ идемпотентности передаётся в метаданных запроса. Если новость по ключу уже была
+// создана, в ответ добавляется заголовок idempotent-replayed.
+const (
+	idempotencyKeyMetadata     = "idempotency-key"
+	idempotentReplayedMetadata = "idempotent-replayed"
+)
+
 type Service struct {
-	repo database.Models
+	repo   database.Models
+	bus    *events.Bus
+	config Config
 	news_proto.UnimplementedNewsServiceServer
 }
 
-func NewNewsService(grpc *grpc.Server, repo database.Models) {
-	newsService := &Service{repo: repo}
+func NewNewsService(grpc *grpc.Server, repo database.Models, bus *events.Bus, config Config) {
+	newsService := &Service{repo: repo, bus: bus, config: config}
 	news_proto.RegisterNewsServiceServer(grpc, newsService)
 }
 
 func (s *Service) CreateNewsHandler(ctx context.Context, req *news_proto.CreateNewsRequest) (*news_proto.News, error) {
+	news := convertCreateRequest(req)
 
-	news := &database.News{
-		Title:      req.GetTitle(),
-		Content:    req.GetContent(),
-		Categories: req.GetCategories(),
-		Status:     req.GetStatus(),
-		ImageURLs:  req.GetImageUrls(),
-		Author:     req.GetAuthor(),
+	var key string
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get(idempotencyKeyMetadata); len(values) > 0 {
+			key = values[0]
+		}
 	}
 
 	v := validator.New()
+	database.ValidateIdempotencyKey(v, key)
 	if database.ValidateNews(v, news); !v.Valid() {
-		return nil, errors.New("invalid news input data")
+		return nil, validationError("invalid news input data", v.Errors)
+	}
+
+	if key == "" {
+		if err := s.repo.News.Insert(news); err != nil {
+			return nil, convertWriteError(err)
+		}
+		return convertNewsToPB(news, true), nil
 	}
 
-	err := s.repo.News.Insert(news)
+	hash, err := database.HashNewsRequest(news)
 	if err != nil {
 		return nil, err
 	}
+	replayed, err := s.repo.News.InsertIdempotent(news, database.IdempotencyKey{
+		Key:         key,
+		RequestHash: hash,
+		ExpiresAt:   time.Now().Add(s.config.IdempotencyTTL),
+	})
+	if err != nil {
+		switch {
+		case errors.Is(err, database.ErrIdempotencyMismatch):
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		case errors.Is(err, database.ErrRecordNotFound):
+			return nil, status.Error(codes.NotFound, "the news created with this idempotency key has been deleted")
+		default:
+			return nil, convertWriteError(err)
+		}
+	}
+	if replayed {
+		grpc.SetHeader(ctx, metadata.Pairs(idempotentReplayedMetadata, "true"))
+	}
 
-	return convertNewsToPB(news), nil
+	return convertNewsToPB(news, true), nil
 }
 
 func (s *Service) ShowNewsHandler(ctx context.Context, req *news_proto.NewsId) (*news_proto.News, error) {
@@ -55,54 +106,45 @@ func (s *Service) ShowNewsHandler(ctx context.Context, req *news_proto.NewsId) (
 		return nil, err
 	}
 
-	return convertNewsToPB(news), nil
+	return convertNewsToPB(news, req.GetIncludeContentHtml()), nil
 }
 
 func (s *Service) UpdateNewsHandler(ctx context.Context, req *news_proto.UpdateNewsRequest) (*news_proto.News, error) {
-	news, err := s.repo.News.Get(req.GetId())
+	// Новость читается с основного сервера: реплика может вернуть устаревшую версию.
+	news, err := s.repo.Primary().News.Get(req.GetId())
 	if err != nil {
 		return nil, err
 	}
 
-	// Проверка на версию, аналогичная твоему коду
-	if req.GetVersion() != news.Version {
+	// Версия необязательна: без неё изменение применяется к текущему состоянию новости.
+	if req.Version != nil && req.GetVersion() != news.Version {
 		return nil, status.Errorf(codes.Aborted, "Version conflict: The news resource has been modified by another process.")
 	}
 
-	if req.Title != nil {
-		news.Title = *req.Title
-	}
-	if req.Content != nil {
-		news.Content = *req.Content
-	}
-	if len(req.GetCategories()) > 0 {
-		news.Categories = req.GetCategories()
-	}
-	if req.Status != nil {
-		news.Status = *req.Status
-	}
-	if len(req.GetImageUrls()) > 0 {
-		news.ImageURLs = req.GetImageUrls()
-	}
-	if req.Author != nil {
-		news.Author = *req.Author
+	if paths := req.GetUpdateMask().GetPaths(); len(paths) > 0 {
+		if err := applyUpdateMask(news, req, paths); err != nil {
+			return nil, err
+		}
+	} else {
+		applyUpdate(news, req)
 	}
+	database.NormalizeMedia(news)
 
 	v := validator.New()
 	if database.ValidateNews(v, news); !v.Valid() {
-		return nil, errors.New("invalid input data")
+		return nil, validationError("invalid news input data", v.Errors)
 	}
 
 	err = s.repo.News.Update(news)
 	if err != nil {
-		return nil, err
+		return nil, convertWriteError(err)
 	}
 
-	return convertNewsToPB(news), nil
+	return convertNewsToPB(news, true), nil
 }
 
 func (s *Service) DeleteNewsHandler(ctx context.Context, req *news_proto.NewsId) (*emptypb.Empty, error) {
-	err := s.repo.News.Delete(req.GetId())
+	err := s.repo.News.Delete(req.GetId(), 0)
 	if err != nil {
 		return nil, err
 	}
@@ -128,27 +170,31 @@ func (s *Service) ListNewsHandler(ctx context.Context, req *news_proto.GetAllReq
 		Page:         page,
 		PageSize:     pageSize,
 		Sort:         sort,
-		SortSafelist: []string{"id", "title", "status", "-id", "-title", "-status"},
+		SortSafelist: database.NewsSortFields(),
+	}
+	nf := database.NewsFilter{
+		Title:          req.GetTitle(),
+		Categories:     req.GetCategories(),
+		Status:         req.GetStatus(),
+		Author:         req.GetAuthor(),
+		MinReadingTime: database.Runtime(req.GetMinReadingTime()),
+		MaxReadingTime: database.Runtime(req.GetMaxReadingTime()),
 	}
 	v := validator.New()
 
+	database.ValidateNewsFilter(v, nf)
 	if database.ValidateFilters(v, filters); !v.Valid() {
 		return nil, errors.New("invalid filters input data")
 	}
 
-	categories := req.GetCategories()
-	if categories == nil {
-		categories = []string{}
-	}
-
-	news, metadata, err := s.repo.News.GetAll(req.GetTitle(), categories, req.GetStatus(), filters)
+	news, metadata, err := s.repo.News.GetAll(nf, filters)
 	if err != nil {
 		return nil, err
 	}
 
 	pbNews := make([]*news_proto.News, 0, len(news))
 	for _, n := range news {
-		pbNews = append(pbNews, convertNewsToPB(n))
+		pbNews = append(pbNews, convertNewsToPB(n, req.GetIncludeContentHtml()))
 	}
 
 	metadataProto := &news_proto.Metadata{
@@ -162,20 +208,383 @@ func (s *Service) ListNewsHandler(ctx context.Context, req *news_proto.GetAllReq
 	return &news_proto.NewsList{News: pbNews, Metadata: metadataProto}, nil
 }
 
-func convertNewsToPB(n *database.News) *news_proto.News {
+// BatchCreateNews создаёт несколько новостей в одной транзакции. Если хотя бы одна
+// новость не проходит проверку, не создаётся ни одна, а в деталях ошибки перечисляются
+// поля с ключами вида "items[<позиция>].<поле>".
+func (s *Service) BatchCreateNews(ctx context.Context, req *news_proto.BatchCreateNewsRequest) (*news_proto.BatchCreateNewsResponse, error) {
+	items := req.GetItems()
+	if len(items) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "items must contain at least 1 item")
+	}
+	if len(items) > s.config.MaxBatchCreate {
+		return nil, status.Errorf(codes.InvalidArgument, "items must not contain more than %d items", s.config.MaxBatchCreate)
+	}
+
+	news := make([]*database.News, 0, len(items))
+	for _, item := range items {
+		news = append(news, convertCreateRequest(item))
+	}
+
+	v := validator.New()
+	if database.ValidateNewsBatch(v, news); !v.Valid() {
+		return nil, validationError("invalid news input data", v.Errors)
+	}
+
+	if err := s.repo.News.InsertBatch(news); err != nil {
+		return nil, convertWriteError(err)
+	}
+
+	resp := &news_proto.BatchCreateNewsResponse{}
+	for _, n := range news {
+		resp.News = append(resp.News, convertNewsToPB(n, true))
+	}
+	return resp, nil
+}
+
+// BatchGetNews возвращает новости в порядке запрошенных идентификаторов и перечисляет
+// идентификаторы, для которых новостей не нашлось.
+func (s *Service) BatchGetNews(ctx context.Context, req *news_proto.BatchGetNewsRequest) (*news_proto.BatchGetNewsResponse, error) {
+	ids := req.GetIds()
+	if len(ids) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "ids must contain at least 1 id")
+	}
+	if len(ids) > s.config.MaxBatchGet {
+		return nil, status.Errorf(codes.InvalidArgument, "ids must not contain more than %d ids", s.config.MaxBatchGet)
+	}
+
+	found, err := s.repo.News.GetMany(ids)
+	if err != nil {
+		return nil, err
+	}
+
+	resp := &news_proto.BatchGetNewsResponse{}
+	for i, n := range found {
+		if n == nil {
+			resp.MissingIds = append(resp.MissingIds, ids[i])
+			continue
+		}
+		resp.News = append(resp.News, convertNewsToPB(n, req.GetIncludeContentHtml()))
+	}
+	return resp, nil
+}
+
+// BulkUpdateNews запускает массовое изменение статуса или категорий новостей в фоне.
+// С dry_run задание не создаётся, а возвращается только количество затрагиваемых новостей.
+func (s *Service) BulkUpdateNews(ctx context.Context, req *news_proto.BulkUpdateNewsRequest) (*news_proto.BulkUpdateNewsResponse, error) {
+	op := database.BulkOperation{
+		Action: req.GetAction(),
+		IDs:    req.GetIds(),
+		Filter: database.NewsFilter{
+			Title:          req.GetFilter().GetTitle(),
+			Categories:     req.GetFilter().GetCategories(),
+			Status:         req.GetFilter().GetStatus(),
+			Author:         req.GetFilter().GetAuthor(),
+			MinReadingTime: database.Runtime(req.GetFilter().GetMinReadingTime()),
+			MaxReadingTime: database.Runtime(req.GetFilter().GetMaxReadingTime()),
+		},
+		Status:      req.GetStatus(),
+		Category:    req.GetCategory(),
+		NewCategory: req.GetNewCategory(),
+	}
+
+	v := validator.New()
+	if database.ValidateBulkOperation(v, op); !v.Valid() {
+		return nil, validationError("invalid bulk operation", v.Errors)
+	}
+
+	counts, err := s.repo.News.BulkPreview(op)
+	if err != nil {
+		return nil, err
+	}
+	resp := &news_proto.BulkUpdateNewsResponse{Matched: counts.Matched, Affected: counts.Affected}
+	if req.GetDryRun() {
+		return resp, nil
+	}
+
+	job := &database.BulkJob{Operation: op, Matched: counts.Matched}
+	if err := s.repo.BulkJobs.Insert(job); err != nil {
+		return nil, err
+	}
+	resp.Job = convertBulkJobToPB(job)
+	return resp, nil
+}
+
+// GetBulkJob возвращает состояние и прогресс массового задания.
+func (s *Service) GetBulkJob(ctx context.Context, req *news_proto.BulkJobId) (*news_proto.BulkJob, error) {
+	job, err := s.repo.BulkJobs.Get(req.GetId())
+	if err != nil {
+		if errors.Is(err, database.ErrRecordNotFound) {
+			return nil, status.Error(codes.NotFound, err.Error())
+		}
+		return nil, err
+	}
+	return convertBulkJobToPB(job), nil
+}
+
+// convertWriteError превращает нарушение ограничения базы данных в InvalidArgument
+// с описанием поля, как и ошибки проверки данных.
+func convertWriteError(err error) error {
+	var violation *database.ViolationError
+	if errors.As(err, &violation) {
+		return validationError("invalid news input data", map[string]string{violation.Field: violation.Message})
+	}
+	return err
+}
+
+// validationError возвращает ошибку InvalidArgument, в деталях которой перечислены
+// поля, не прошедшие проверку.
+func validationError(message string, errs map[string]string) error {
+	st := status.New(codes.InvalidArgument, message)
+	badRequest := &errdetails.BadRequest{}
+	for field, description := range errs {
+		badRequest.FieldViolations = append(badRequest.FieldViolations, &errdetails.BadRequest_FieldViolation{
+			Field:       field,
+			Description: description,
+		})
+	}
+	// Сортируем нарушения, чтобы ответ не зависел от порядка обхода карты.
+	sort.Slice(badRequest.FieldViolations, func(i, j int) bool {
+		return badRequest.FieldViolations[i].Field < badRequest.FieldViolations[j].Field
+	})
+	detailed, err := st.WithDetails(badRequest)
+	if err != nil {
+		return st.Err()
+	}
+	return detailed.Err()
+}
+
+// Размер буфера событий одного подписчика. Подписчик, отставший больше чем на это
+// количество событий, отключается, чтобы не задерживать остальных.
+const watchBufferSize = 256
+
+// WatchNews передаёт клиенту события об изменениях новостей, подходящих под фильтр
+// до или после изменения: клиент узнаёт и о новостях, вышедших из его выборки.
+// Если указан last_event_id, сначала отправляются пропущенные события из истории шины.
+func (s *Service) WatchNews(req *news_proto.WatchNewsRequest, stream news_proto.NewsService_WatchNewsServer) error {
+	nf := database.NewsFilter{
+		Title:          req.GetTitle(),
+		Categories:     req.GetCategories(),
+		Status:         req.GetStatus(),
+		Author:         req.GetAuthor(),
+		MinReadingTime: database.Runtime(req.GetMinReadingTime()),
+		MaxReadingTime: database.Runtime(req.GetMaxReadingTime()),
+	}
+	v := validator.New()
+	if database.ValidateNewsFilter(v, nf); !v.Valid() {
+		return status.Error(codes.InvalidArgument, "invalid filters input data")
+	}
+
+	sub, err := s.bus.Subscribe(req.GetLastEventId(), watchBufferSize)
+	if err != nil {
+		switch {
+		case errors.Is(err, events.ErrResumeUnavailable):
+			// Клиенту нужно заново загрузить список новостей и подписаться без last_event_id.
+			return status.Error(codes.OutOfRange, err.Error())
+		default:
+			return status.Error(codes.Unavailable, err.Error())
+		}
+	}
+	defer sub.Close()
+
+	ctx := stream.Context()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case e, ok := <-sub.Events():
+			if !ok {
+				if errors.Is(sub.Err(), events.ErrSlowSubscriber) {
+					return status.Error(codes.ResourceExhausted, sub.Err().Error())
+				}
+				return status.Error(codes.Unavailable, "event stream closed")
+			}
+			if !e.Matches(nf) {
+				continue
+			}
+			err := stream.Send(&news_proto.NewsEvent{
+				Id:         e.ID,
+				Type:       e.Type,
+				News:       convertNewsToPB(e.News, req.GetIncludeContentHtml()),
+				OccurredAt: timestamppb.New(e.OccurredAt),
+			})
+			if err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// applyUpdate переносит в новость поля, переданные в запросе без update_mask. Пустые
+// повторяющиеся поля при этом считаются непереданными.
+func applyUpdate(news *database.News, req *news_proto.UpdateNewsRequest) {
+	if req.Title != nil {
+		news.Title = *req.Title
+	}
+	if req.Content != nil {
+		news.Content = *req.Content
+	}
+	if req.ContentFormat != nil {
+		news.ContentFormat = *req.ContentFormat
+	}
+	if len(req.GetCategories()) > 0 {
+		news.Categories = req.GetCategories()
+	}
+	if req.Status != nil {
+		news.Status = *req.Status
+	}
+	if len(req.GetMedia()) > 0 {
+		news.Media = convertMediaFromPB(req.GetMedia())
+	} else if len(req.GetImageUrls()) > 0 {
+		news.Media = database.MediaFromURLs(req.GetImageUrls(), news.Media)
+	}
+	if req.Author != nil {
+		news.Author = *req.Author
+	}
+}
+
+// applyUpdateMask изменяет только поля, перечисленные в update_mask. Поле из маски,
+// отсутствующее в запросе, очищается, так что пустой image_urls удаляет все изображения.
+func applyUpdateMask(news *database.News, req *news_proto.UpdateNewsRequest, paths []string) error {
+	for _, path := range paths {
+		if !validator.PermittedValue(path, database.NewsUpdatableFields...) {
+			return status.Errorf(codes.InvalidArgument, "unknown update_mask path %q", path)
+		}
+	}
+
+	masked := make(map[string]bool, len(paths))
+	for _, path := range paths {
+		masked[path] = true
+	}
+
+	if masked["title"] {
+		news.Title = req.GetTitle()
+	}
+	if masked["content"] {
+		news.Content = req.GetContent()
+	}
+	if masked["content_format"] {
+		news.ContentFormat = req.GetContentFormat()
+	}
+	if masked["categories"] {
+		news.Categories = req.GetCategories()
+	}
+	if masked["status"] {
+		news.Status = req.GetStatus()
+	}
+	// ImageURLs сбрасывается, чтобы NormalizeMedia не восстановил из него удалённые изображения.
+	switch {
+	case masked["media"]:
+		news.Media = convertMediaFromPB(req.GetMedia())
+		news.ImageURLs = nil
+	case masked["image_urls"]:
+		news.Media = database.MediaFromURLs(req.GetImageUrls(), news.Media)
+		news.ImageURLs = nil
+	}
+	if masked["author"] {
+		news.Author = req.GetAuthor()
+	}
+	return nil
+}
+
+// convertNewsToPB преобразует новость в protobuf-сообщение. HTML-представление
+// содержимого добавляется только по запросу клиента, чтобы не передавать его дважды.
+func convertNewsToPB(n *database.News, includeHTML bool) *news_proto.News {
 	if n == nil {
 		return &news_proto.News{}
 	}
-	return &news_proto.News{
-		Id:         n.ID,
-		Title:      n.Title,
-		Content:    n.Content,
-		Categories: n.Categories,
-		Status:     n.Status,
-		ImageUrls:  n.ImageURLs,
-		Author:     n.Author,
-		CreatedAt:  timestamppb.New(n.CreatedAt),
-		UpdatedAt:  timestamppb.New(n.UpdatedAt),
-		Version:    n.Version,
+	pb := &news_proto.News{
+		Id:            n.ID,
+		Title:         n.Title,
+		Content:       n.Content,
+		ContentFormat: n.ContentFormat,
+		WordCount:     n.WordCount,
+		ReadingTime:   int32(n.ReadingTime),
+		Excerpt:       n.Excerpt,
+		Categories:    n.Categories,
+		Status:        n.Status,
+		ImageUrls:     n.ImageURLs,
+		Author:        n.Author,
+		CreatedAt:     timestamppb.New(n.CreatedAt),
+		UpdatedAt:     timestamppb.New(n.UpdatedAt),
+		Version:       n.Version,
+	}
+	if n.PublishedAt != nil {
+		pb.PublishedAt = timestamppb.New(*n.PublishedAt)
+	}
+	for _, m := range n.Media {
+		pbMedia := &news_proto.Media{
+			Url:     m.URL,
+			AltText: m.AltText,
+			Caption: m.Caption,
+			Credit:  m.Credit,
+			Width:   m.Width,
+			Height:  m.Height,
+		}
+		for _, r := range m.Renditions {
+			pbMedia.Renditions = append(pbMedia.Renditions, &news_proto.Rendition{
+				Url:         r.URL,
+				Width:       r.Width,
+				Height:      r.Height,
+				ContentType: r.ContentType,
+			})
+		}
+		pb.Media = append(pb.Media, pbMedia)
+	}
+	if includeHTML {
+		pb.ContentHtml = n.ContentHTML
+	}
+	return pb
+}
+
+func convertBulkJobToPB(job *database.BulkJob) *news_proto.BulkJob {
+	pb := &news_proto.BulkJob{
+		Id:        job.ID,
+		CreatedAt: timestamppb.New(job.CreatedAt),
+		UpdatedAt: timestamppb.New(job.UpdatedAt),
+		Action:    job.Operation.Action,
+		Status:    job.Status,
+		Matched:   job.Matched,
+		Processed: job.Processed,
+		Affected:  job.Affected,
+		Error:     job.Error,
+	}
+	if job.FinishedAt != nil {
+		pb.FinishedAt = timestamppb.New(*job.FinishedAt)
+	}
+	return pb
+}
+
+// convertCreateRequest преобразует запрос на создание новости в запись, подставляя
+// значения по умолчанию.
+func convertCreateRequest(req *news_proto.CreateNewsRequest) *database.News {
+	news := &database.News{
+		Title:         req.GetTitle(),
+		Content:       req.GetContent(),
+		ContentFormat: req.GetContentFormat(),
+		Categories:    req.GetCategories(),
+		Status:        req.GetStatus(),
+		ImageURLs:     req.GetImageUrls(),
+		Media:         convertMediaFromPB(req.GetMedia()),
+		Author:        req.GetAuthor(),
+	}
+	if news.ContentFormat == "" {
+		news.ContentFormat = markup.FormatPlain
+	}
+	database.NormalizeMedia(news)
+	return news
+}
+
+func convertMediaFromPB(media []*news_proto.Media) []database.Media {
+	result := make([]database.Media, 0, len(media))
+	for _, m := range media {
+		result = append(result, database.Media{
+			URL:     m.GetUrl(),
+			AltText: m.GetAltText(),
+			Caption: m.GetCaption(),
+			Credit:  m.GetCredit(),
+			Width:   m.GetWidth(),
+			Height:  m.GetHeight(),
+		})
 	}
+	return result
 }
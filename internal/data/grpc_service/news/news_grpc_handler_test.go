@@ -0,0 +1,228 @@
+package news
+
+import (
+	"context"
+	"slices"
+	"testing"
+
+	"github.com/AnKlvy/news-service/internal/data/database"
+	"github.com/AnKlvy/news-service/internal/events"
+	news_proto "github.com/AnKlvy/news-service/protobuf/gen_news"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type watchStream struct {
+	grpc.ServerStream
+	ctx  context.Context
+	sent []*news_proto.NewsEvent
+	// Если задан, вызывается после отправки want событий.
+	cancel context.CancelFunc
+	want   int
+}
+
+func (s *watchStream) Context() context.Context {
+	return s.ctx
+}
+
+func (s *watchStream) Send(e *news_proto.NewsEvent) error {
+	s.sent = append(s.sent, e)
+	if s.cancel != nil && len(s.sent) == s.want {
+		s.cancel()
+	}
+	return nil
+}
+
+func TestWatchNewsRejectsUnknownLastEventID(t *testing.T) {
+	other := events.NewBus(10)
+	sub, err := other.Subscribe(0, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	other.Publish(database.EventCreated, &database.News{ID: 1}, nil)
+	foreignID := (<-sub.Events()).ID
+	sub.Close()
+
+	bus := events.NewBus(10)
+	defer bus.Close()
+	bus.Publish(database.EventCreated, &database.News{ID: 1}, nil)
+
+	s := &Service{repo: database.NewMockModels(), bus: bus}
+
+	tests := []struct {
+		name        string
+		lastEventID int64
+	}{
+		{"issued by another process", foreignID},
+		{"issued before a restart", 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			stream := &watchStream{ctx: context.Background()}
+			err := s.WatchNews(&news_proto.WatchNewsRequest{LastEventId: tt.lastEventID}, stream)
+			if status.Code(err) != codes.OutOfRange {
+				t.Fatalf("WatchNews() error = %v, want code %s", err, codes.OutOfRange)
+			}
+			if len(stream.sent) != 0 {
+				t.Fatalf("WatchNews() sent %d events before failing", len(stream.sent))
+			}
+		})
+	}
+}
+
+func TestWatchNewsSendsTransitionsOutOfFilter(t *testing.T) {
+	bus := events.NewBus(10)
+	defer bus.Close()
+
+	probe, err := bus.Subscribe(0, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer probe.Close()
+
+	published := &database.News{ID: 1, Status: "PUBLISHED", Categories: []string{"world"}}
+	bus.Publish(database.EventCreated, &database.News{ID: 9, Status: "DRAFT"}, nil)
+	lastEventID := (<-probe.Events()).ID
+	bus.Publish(database.EventUpdated, &database.News{ID: 1, Status: "ARCHIVED", Categories: []string{"world"}}, published)
+	bus.Publish(database.EventUpdated, &database.News{ID: 2, Status: "DRAFT"}, &database.News{ID: 2, Status: "DRAFT"})
+	bus.Publish(database.EventUpdated, &database.News{ID: 1, Status: "PUBLISHED"}, published)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	stream := &watchStream{ctx: ctx, cancel: cancel, want: 2}
+
+	s := &Service{repo: database.NewMockModels(), bus: bus}
+	req := &news_proto.WatchNewsRequest{Status: "PUBLISHED", Categories: []string{"world"}, LastEventId: lastEventID}
+	if err := s.WatchNews(req, stream); err != nil {
+		t.Fatalf("WatchNews() error = %v", err)
+	}
+
+	if len(stream.sent) != 2 {
+		t.Fatalf("sent %d events, want 2", len(stream.sent))
+	}
+	if got := stream.sent[0].GetNews(); got.GetId() != 1 || got.GetStatus() != "ARCHIVED" {
+		t.Fatalf("first event news = %v, want news 1 moved to ARCHIVED", got)
+	}
+	if got := stream.sent[1].GetNews(); got.GetId() != 1 || got.GetStatus() != "PUBLISHED" {
+		t.Fatalf("second event news = %v, want news 1 with the category removed", got)
+	}
+}
+
+func newTestMaskNews() *database.News {
+	news := &database.News{
+		Title:      "Title",
+		Content:    "Content",
+		Categories: []string{"world"},
+		Status:     "DRAFT",
+		Author:     "Author",
+		Media: []database.Media{
+			{URL: "https://img.example.com/a.jpg", AltText: "A"},
+			{URL: "https://img.example.com/b.jpg", AltText: "B"},
+		},
+	}
+	database.NormalizeMedia(news)
+	return news
+}
+
+func TestApplyUpdateMask(t *testing.T) {
+	title := "New title"
+	content := "Ignored content"
+	news := newTestMaskNews()
+	req := &news_proto.UpdateNewsRequest{Title: &title, Content: &content}
+
+	if err := applyUpdateMask(news, req, []string{"title", "author", "categories"}); err != nil {
+		t.Fatalf("applyUpdateMask() error = %v", err)
+	}
+	database.NormalizeMedia(news)
+
+	if news.Title != title {
+		t.Errorf("Title = %q, want %q", news.Title, title)
+	}
+	if news.Content != "Content" {
+		t.Errorf("Content = %q, want the field outside the mask unchanged", news.Content)
+	}
+	if news.Author != "" || len(news.Categories) != 0 {
+		t.Errorf("Author = %q, Categories = %v, want masked fields missing from the request cleared", news.Author, news.Categories)
+	}
+	if len(news.Media) != 2 {
+		t.Errorf("Media = %+v, want images outside the mask unchanged", news.Media)
+	}
+}
+
+func TestApplyUpdateMaskImages(t *testing.T) {
+	tests := []struct {
+		name string
+		req  *news_proto.UpdateNewsRequest
+		path string
+		want []string
+	}{
+		{"empty image_urls clears images", &news_proto.UpdateNewsRequest{}, "image_urls", []string{}},
+		{"empty media clears images", &news_proto.UpdateNewsRequest{}, "media", []string{}},
+		{
+			"image_urls keeps known metadata",
+			&news_proto.UpdateNewsRequest{ImageUrls: []string{"https://img.example.com/b.jpg"}},
+			"image_urls", []string{"https://img.example.com/b.jpg"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			news := newTestMaskNews()
+			if err := applyUpdateMask(news, tt.req, []string{tt.path}); err != nil {
+				t.Fatalf("applyUpdateMask() error = %v", err)
+			}
+			database.NormalizeMedia(news)
+
+			if !slices.Equal(news.ImageURLs, tt.want) {
+				t.Fatalf("ImageURLs = %v, want %v", news.ImageURLs, tt.want)
+			}
+			if len(news.Media) > 0 && news.Media[0].AltText != "B" {
+				t.Errorf("AltText = %q, want %q", news.Media[0].AltText, "B")
+			}
+		})
+	}
+}
+
+func TestApplyUpdateMaskRejectsUnknownPaths(t *testing.T) {
+	for _, path := range []string{"id", "version", "created_at"} {
+		err := applyUpdateMask(newTestMaskNews(), &news_proto.UpdateNewsRequest{}, []string{"title", path})
+		if status.Code(err) != codes.InvalidArgument {
+			t.Errorf("applyUpdateMask(%q) error = %v, want code %s", path, err, codes.InvalidArgument)
+		}
+	}
+}
+
+func TestApplyUpdateIgnoresEmptyRepeatedFields(t *testing.T) {
+	news := newTestMaskNews()
+	applyUpdate(news, &news_proto.UpdateNewsRequest{})
+	database.NormalizeMedia(news)
+
+	if len(news.Categories) != 1 || len(news.Media) != 2 || news.Title != "Title" {
+		t.Fatalf("news = %+v, want a request without fields to change nothing", news)
+	}
+}
+
+func TestCreateNewsValidationError(t *testing.T) {
+	s := &Service{repo: database.NewMockModels()}
+
+	_, err := s.CreateNewsHandler(context.Background(), &news_proto.CreateNewsRequest{Content: "Content"})
+	st := status.Convert(err)
+	if st.Code() != codes.InvalidArgument {
+		t.Fatalf("CreateNewsHandler() error = %v, want code %s", err, codes.InvalidArgument)
+	}
+
+	var fields []string
+	for _, detail := range st.Details() {
+		if badRequest, ok := detail.(*errdetails.BadRequest); ok {
+			for _, violation := range badRequest.GetFieldViolations() {
+				fields = append(fields, violation.GetField())
+			}
+		}
+	}
+	if !slices.Contains(fields, "title") || !slices.Contains(fields, "author") {
+		t.Fatalf("field violations = %v, want title and author", fields)
+	}
+}
@@ -0,0 +1,234 @@
+package webhooks
+
+import (
+	"context"
+	"errors"
+
+	"github.com/AnKlvy/news-service/internal/data/database"
+	"github.com/AnKlvy/news-service/internal/validator"
+	"github.com/AnKlvy/news-service/internal/webhooks"
+	"github.com/AnKlvy/news-service/protobuf/gen_news"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+type Service struct {
+	repo database.Models
+	news_proto.UnimplementedWebhookServiceServer
+}
+
+func NewWebhookService(grpc *grpc.Server, repo database.Models) {
+	webhookService := &Service{repo: repo}
+	news_proto.RegisterWebhookServiceServer(grpc, webhookService)
+}
+
+func (s *Service) CreateWebhook(ctx context.Context, req *news_proto.CreateWebhookRequest) (*news_proto.Webhook, error) {
+	webhook := &database.Webhook{
+		URL:        req.GetUrl(),
+		Secret:     req.GetSecret(),
+		EventTypes: req.GetEventTypes(),
+		Active:     true,
+	}
+	if req.Active != nil {
+		webhook.Active = *req.Active
+	}
+	if webhook.Secret == "" {
+		secret, err := webhooks.NewSecret()
+		if err != nil {
+			return nil, err
+		}
+		webhook.Secret = secret
+	}
+
+	v := validator.New()
+	if database.ValidateWebhook(v, webhook); !v.Valid() {
+		return nil, status.Error(codes.InvalidArgument, "invalid webhook input data")
+	}
+
+	if err := s.repo.Webhooks.Insert(webhook); err != nil {
+		return nil, err
+	}
+
+	// Секрет возвращается только в ответе на создание подписки.
+	return convertWebhookToPB(webhook, true), nil
+}
+
+func (s *Service) GetWebhook(ctx context.Context, req *news_proto.WebhookId) (*news_proto.Webhook, error) {
+	webhook, err := s.repo.Webhooks.Get(req.GetId())
+	if err != nil {
+		return nil, convertError(err)
+	}
+	return convertWebhookToPB(webhook, false), nil
+}
+
+func (s *Service) UpdateWebhook(ctx context.Context, req *news_proto.UpdateWebhookRequest) (*news_proto.Webhook, error) {
+	webhook, err := s.repo.Webhooks.Get(req.GetId())
+	if err != nil {
+		return nil, convertError(err)
+	}
+
+	if req.Version != nil && *req.Version != webhook.Version {
+		return nil, status.Error(codes.Aborted, "version conflict: the webhook has been modified by another process")
+	}
+
+	if req.Url != nil {
+		webhook.URL = *req.Url
+	}
+	if req.Secret != nil {
+		webhook.Secret = *req.Secret
+	}
+	if len(req.GetEventTypes()) > 0 {
+		webhook.EventTypes = req.GetEventTypes()
+	}
+	if req.Active != nil {
+		webhook.Active = *req.Active
+	}
+
+	v := validator.New()
+	if database.ValidateWebhook(v, webhook); !v.Valid() {
+		return nil, status.Error(codes.InvalidArgument, "invalid webhook input data")
+	}
+
+	if err := s.repo.Webhooks.Update(webhook); err != nil {
+		return nil, convertError(err)
+	}
+	return convertWebhookToPB(webhook, false), nil
+}
+
+func (s *Service) DeleteWebhook(ctx context.Context, req *news_proto.WebhookId) (*emptypb.Empty, error) {
+	if err := s.repo.Webhooks.Delete(req.GetId()); err != nil {
+		return nil, convertError(err)
+	}
+	return &emptypb.Empty{}, nil
+}
+
+func (s *Service) ListWebhooks(ctx context.Context, _ *emptypb.Empty) (*news_proto.WebhookList, error) {
+	list, err := s.repo.Webhooks.GetAll()
+	if err != nil {
+		return nil, err
+	}
+
+	pb := &news_proto.WebhookList{}
+	for _, webhook := range list {
+		pb.Webhooks = append(pb.Webhooks, convertWebhookToPB(webhook, false))
+	}
+	return pb, nil
+}
+
+// ListDeliveries возвращает доставки подписки вместе с историей попыток.
+func (s *Service) ListDeliveries(ctx context.Context, req *news_proto.ListDeliveriesRequest) (*news_proto.DeliveryList, error) {
+	page := int(req.GetPage())
+	if page <= 0 {
+		page = 1
+	}
+	pageSize := int(req.GetPageSize())
+	if pageSize <= 0 {
+		pageSize = 20
+	}
+	sort := req.GetSort()
+	if sort == "" {
+		sort = "-id"
+	}
+
+	filters := database.Filters{
+		Page:         page,
+		PageSize:     pageSize,
+		Sort:         sort,
+		SortSafelist: database.DeliverySortSafelist,
+	}
+
+	v := validator.New()
+	v.Check(req.GetStatus() == "" || validator.PermittedValue(req.GetStatus(), database.DeliveryPending, database.DeliveryDelivered, database.DeliveryDead),
+		"status", "must be pending, delivered or dead")
+	if database.ValidateFilters(v, filters); !v.Valid() {
+		return nil, status.Error(codes.InvalidArgument, "invalid filters input data")
+	}
+
+	if _, err := s.repo.Webhooks.Get(req.GetWebhookId()); err != nil {
+		return nil, convertError(err)
+	}
+
+	deliveries, metadata, err := s.repo.WebhookDeliveries.GetAll(req.GetWebhookId(), req.GetStatus(), filters)
+	if err != nil {
+		return nil, err
+	}
+
+	pb := &news_proto.DeliveryList{
+		Metadata: &news_proto.Metadata{
+			CurrentPage:  int32(metadata.CurrentPage),
+			PageSize:     int32(metadata.PageSize),
+			FirstPage:    int32(metadata.FirstPage),
+			LastPage:     int32(metadata.LastPage),
+			TotalRecords: int32(metadata.TotalRecords),
+		},
+	}
+	for _, d := range deliveries {
+		pb.Deliveries = append(pb.Deliveries, convertDeliveryToPB(d))
+	}
+	return pb, nil
+}
+
+// ReplayDelivery ставит доставку в очередь на повторную отправку.
+func (s *Service) ReplayDelivery(ctx context.Context, req *news_proto.ReplayDeliveryRequest) (*news_proto.WebhookDelivery, error) {
+	delivery, err := s.repo.WebhookDeliveries.Replay(req.GetWebhookId(), req.GetDeliveryId())
+	if err != nil {
+		return nil, convertError(err)
+	}
+	return convertDeliveryToPB(delivery), nil
+}
+
+func convertError(err error) error {
+	switch {
+	case errors.Is(err, database.ErrRecordNotFound):
+		return status.Error(codes.NotFound, err.Error())
+	case errors.Is(err, database.ErrEditConflict):
+		return status.Error(codes.Aborted, err.Error())
+	default:
+		return err
+	}
+}
+
+func convertWebhookToPB(w *database.Webhook, includeSecret bool) *news_proto.Webhook {
+	pb := &news_proto.Webhook{
+		Id:         w.ID,
+		CreatedAt:  timestamppb.New(w.CreatedAt),
+		UpdatedAt:  timestamppb.New(w.UpdatedAt),
+		Url:        w.URL,
+		EventTypes: w.EventTypes,
+		Active:     w.Active,
+		Version:    w.Version,
+	}
+	if includeSecret {
+		pb.Secret = w.Secret
+	}
+	return pb
+}
+
+func convertDeliveryToPB(d *database.WebhookDelivery) *news_proto.WebhookDelivery {
+	pb := &news_proto.WebhookDelivery{
+		Id:            d.ID,
+		WebhookId:     d.WebhookID,
+		EventType:     d.EventType,
+		Payload:       string(d.Payload),
+		Status:        d.Status,
+		Attempts:      d.Attempts,
+		NextAttemptAt: timestamppb.New(d.NextAttemptAt),
+		LastError:     d.LastError,
+		CreatedAt:     timestamppb.New(d.CreatedAt),
+	}
+	if d.DeliveredAt != nil {
+		pb.DeliveredAt = timestamppb.New(*d.DeliveredAt)
+	}
+	for _, a := range d.History {
+		pb.History = append(pb.History, &news_proto.WebhookAttempt{
+			AttemptedAt:    timestamppb.New(a.AttemptedAt),
+			ResponseStatus: a.ResponseStatus,
+			Error:          a.Error,
+			DurationMs:     a.DurationMS,
+		})
+	}
+	return pb
+}
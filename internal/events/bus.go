@@ -0,0 +1,187 @@
+package events
+
+import (
+	"errors"
+	"math/rand/v2"
+	"sync"
+	"time"
+
+	"github.com/AnKlvy/news-service/internal/data/database"
+)
+
+var (
+	// ErrResumeUnavailable возвращается, если продолжить поток без пропусков невозможно:
+	// запрошенное событие уже вытеснено из истории или опубликовано другим экземпляром
+	// сервиса либо до его перезапуска.
+	ErrResumeUnavailable = errors.New("events after the requested id are no longer available")
+	// ErrSlowSubscriber — причина закрытия подписки, не успевающей читать события.
+	ErrSlowSubscriber = errors.New("subscriber is too slow and has been disconnected")
+	// ErrBusClosed — причина закрытия подписок при остановке шины.
+	ErrBusClosed = errors.New("event bus is closed")
+)
+
+// Event — изменение новости. ID монотонно возрастает в пределах одного экземпляра
+// сервиса и используется клиентами для возобновления потока после переподключения.
+// Старшие 32 бита ID содержат случайную эпоху шины, поэтому номер, выданный другим
+// процессом, не совпадёт с номером из текущей истории.
+type Event struct {
+	ID   int64
+	Type string
+	News *database.News
+	// Поля фильтров новости до изменения; nil для созданных и удалённых новостей.
+	Previous   *database.News
+	OccurredAt time.Time
+}
+
+// Matches сообщает, касается ли событие выборки по фильтру nf: новость подходит под
+// фильтр сейчас или подходила до изменения. Так подписчик узнаёт и о новостях,
+// которые вышли из выборки, например при снятии с публикации.
+func (e Event) Matches(nf database.NewsFilter) bool {
+	return nf.Matches(e.News) || (e.Previous != nil && nf.Matches(e.Previous))
+}
+
+// Bus — шина событий внутри процесса. Модель новостей публикует в неё изменения,
+// а потоковые RPC и другие подписчики получают их. Последние события хранятся
+// в кольцевом буфере, чтобы переподключившийся клиент мог получить пропущенное.
+type Bus struct {
+	mu          sync.Mutex
+	epoch       int64
+	nextID      int64
+	history     []Event
+	historySize int
+	subscribers map[*Subscription]struct{}
+	closed      bool
+}
+
+// NewBus создаёт шину, которая помнит historySize последних событий.
+func NewBus(historySize int) *Bus {
+	epoch := int64(rand.Int32N(1<<31-1)+1) << 32
+	return &Bus{
+		epoch:       epoch,
+		nextID:      epoch + 1,
+		historySize: historySize,
+		subscribers: make(map[*Subscription]struct{}),
+	}
+}
+
+// Subscription — подписка на события шины.
+type Subscription struct {
+	bus    *Bus
+	events chan Event
+	err    error
+	once   sync.Once
+}
+
+// Events возвращает канал событий. Канал закрывается при отмене подписки.
+func (s *Subscription) Events() <-chan Event {
+	return s.events
+}
+
+// Err возвращает причину закрытия подписки или nil, если подписку отменил сам подписчик.
+func (s *Subscription) Err() error {
+	s.bus.mu.Lock()
+	defer s.bus.mu.Unlock()
+	return s.err
+}
+
+// Close отменяет подписку.
+func (s *Subscription) Close() {
+	s.bus.mu.Lock()
+	defer s.bus.mu.Unlock()
+	s.bus.remove(s, nil)
+}
+
+// Publish реализует database.EventPublisher: присваивает событию номер, сохраняет его
+// в истории и рассылает подписчикам.
+func (b *Bus) Publish(eventType string, news, previous *database.News) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.closed {
+		return
+	}
+
+	// Подписчики получают копию, чтобы последующие изменения исходной структуры
+	// вызывающим кодом не влияли на уже опубликованное событие.
+	snapshot := *news
+	event := Event{
+		ID:         b.nextID,
+		Type:       eventType,
+		News:       &snapshot,
+		OccurredAt: time.Now(),
+	}
+	if previous != nil {
+		old := *previous
+		event.Previous = &old
+	}
+	b.nextID++
+
+	b.history = append(b.history, event)
+	if len(b.history) > b.historySize {
+		b.history = b.history[len(b.history)-b.historySize:]
+	}
+
+	for sub := range b.subscribers {
+		select {
+		case sub.events <- event:
+		default:
+			// Не даём медленному подписчику задерживать остальных.
+			b.remove(sub, ErrSlowSubscriber)
+		}
+	}
+}
+
+// Subscribe подписывается на события. Если lastEventID больше нуля, подписчик сначала
+// получает из истории все события с большими номерами. Номер, который эта шина не
+// выдавала, приводит к ErrResumeUnavailable: пропущенные события восстановить нельзя.
+func (b *Bus) Subscribe(lastEventID int64, buffer int) (*Subscription, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.closed {
+		return nil, ErrBusClosed
+	}
+
+	var backlog []Event
+	if lastEventID > 0 && lastEventID != b.nextID-1 {
+		if lastEventID < b.epoch || lastEventID >= b.nextID ||
+			len(b.history) == 0 || b.history[0].ID > lastEventID+1 {
+			return nil, ErrResumeUnavailable
+		}
+		for _, e := range b.history {
+			if e.ID > lastEventID {
+				backlog = append(backlog, e)
+			}
+		}
+	}
+
+	sub := &Subscription{
+		bus:    b,
+		events: make(chan Event, buffer+len(backlog)),
+	}
+	for _, e := range backlog {
+		sub.events <- e
+	}
+	b.subscribers[sub] = struct{}{}
+	return sub, nil
+}
+
+// Close закрывает шину и все подписки.
+func (b *Bus) Close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.closed = true
+	for sub := range b.subscribers {
+		b.remove(sub, ErrBusClosed)
+	}
+}
+
+// remove удаляет подписку и закрывает её канал. Вызывается с захваченным мьютексом.
+func (b *Bus) remove(sub *Subscription, err error) {
+	sub.once.Do(func() {
+		delete(b.subscribers, sub)
+		sub.err = err
+		close(sub.events)
+	})
+}
@@ -0,0 +1,153 @@
+package events
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/AnKlvy/news-service/internal/data/database"
+)
+
+func publishN(b *Bus, n int) []int64 {
+	sub, _ := b.Subscribe(0, n)
+	defer sub.Close()
+
+	ids := make([]int64, 0, n)
+	for i := 0; i < n; i++ {
+		b.Publish(database.EventUpdated, &database.News{ID: int64(i + 1)}, nil)
+		ids = append(ids, (<-sub.Events()).ID)
+	}
+	return ids
+}
+
+func drain(sub *Subscription) []int64 {
+	var ids []int64
+	for {
+		select {
+		case e := <-sub.Events():
+			ids = append(ids, e.ID)
+		default:
+			return ids
+		}
+	}
+}
+
+func TestSubscribeResumesFromHistory(t *testing.T) {
+	b := NewBus(10)
+	ids := publishN(b, 5)
+
+	sub, err := b.Subscribe(ids[1], 1)
+	if err != nil {
+		t.Fatalf("Subscribe() error = %v", err)
+	}
+	defer sub.Close()
+
+	got := drain(sub)
+	want := ids[2:]
+	if len(got) != len(want) {
+		t.Fatalf("backlog = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("backlog = %v, want %v", got, want)
+		}
+	}
+}
+
+func TestSubscribeFromLatestHasNoBacklog(t *testing.T) {
+	b := NewBus(10)
+	ids := publishN(b, 3)
+
+	sub, err := b.Subscribe(ids[len(ids)-1], 1)
+	if err != nil {
+		t.Fatalf("Subscribe() error = %v", err)
+	}
+	defer sub.Close()
+
+	if got := drain(sub); len(got) != 0 {
+		t.Fatalf("backlog = %v, want none", got)
+	}
+}
+
+func TestSubscribeResumeUnavailable(t *testing.T) {
+	b := NewBus(2)
+	ids := publishN(b, 5)
+	other := publishN(NewBus(10), 5)
+
+	tests := []struct {
+		name        string
+		lastEventID int64
+	}{
+		{"evicted from history", ids[0]},
+		{"not issued yet", ids[len(ids)-1] + 1},
+		{"issued by another bus", other[1]},
+		{"issued before the epoch", 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := b.Subscribe(tt.lastEventID, 1)
+			if !errors.Is(err, ErrResumeUnavailable) {
+				t.Fatalf("Subscribe(%d) error = %v, want %v", tt.lastEventID, err, ErrResumeUnavailable)
+			}
+		})
+	}
+}
+
+func TestSlowSubscriberIsDisconnected(t *testing.T) {
+	b := NewBus(10)
+	sub, err := b.Subscribe(0, 1)
+	if err != nil {
+		t.Fatalf("Subscribe() error = %v", err)
+	}
+
+	b.Publish(database.EventCreated, &database.News{ID: 1}, nil)
+	b.Publish(database.EventCreated, &database.News{ID: 2}, nil)
+
+	for range sub.Events() {
+	}
+	if !errors.Is(sub.Err(), ErrSlowSubscriber) {
+		t.Fatalf("Err() = %v, want %v", sub.Err(), ErrSlowSubscriber)
+	}
+}
+
+func TestCloseEndsSubscriptions(t *testing.T) {
+	b := NewBus(10)
+	sub, err := b.Subscribe(0, 1)
+	if err != nil {
+		t.Fatalf("Subscribe() error = %v", err)
+	}
+
+	b.Close()
+	if _, ok := <-sub.Events(); ok {
+		t.Fatal("Events() is still open after Close")
+	}
+	if !errors.Is(sub.Err(), ErrBusClosed) {
+		t.Fatalf("Err() = %v, want %v", sub.Err(), ErrBusClosed)
+	}
+	if _, err := b.Subscribe(0, 1); !errors.Is(err, ErrBusClosed) {
+		t.Fatalf("Subscribe() after Close error = %v, want %v", err, ErrBusClosed)
+	}
+}
+
+func TestEventMatchesOldOrNewState(t *testing.T) {
+	nf := database.NewsFilter{Status: "PUBLISHED"}
+	published := &database.News{ID: 1, Status: "PUBLISHED"}
+	archived := &database.News{ID: 1, Status: "ARCHIVED"}
+
+	tests := []struct {
+		name  string
+		event Event
+		want  bool
+	}{
+		{"enters the filter", Event{News: published, Previous: archived}, true},
+		{"leaves the filter", Event{News: archived, Previous: published}, true},
+		{"stays outside", Event{News: archived, Previous: archived}, false},
+		{"created outside", Event{News: archived}, false},
+	}
+
+	for _, tt := range tests {
+		if got := tt.event.Matches(nf); got != tt.want {
+			t.Errorf("%s: Matches() = %t, want %t", tt.name, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,121 @@
+package events
+
+import (
+	"encoding/json"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/AnKlvy/news-service/internal/data/database"
+	"github.com/AnKlvy/news-service/internal/jsonlog"
+	"github.com/lib/pq"
+)
+
+// Listener получает уведомления об изменениях новостей, сделанных другими экземплярами
+// сервиса, через LISTEN/NOTIFY и публикует их в локальную шину. Переподключение к базе
+// данных после обрыва соединения выполняет pq.Listener.
+type Listener struct {
+	listener *pq.Listener
+	models   database.Models
+	bus      *Bus
+	logger   *jsonlog.Logger
+	done     chan struct{}
+	stopped  chan struct{}
+}
+
+// NewListener подключается к базе данных по dsn и подписывается на канал уведомлений.
+func NewListener(dsn string, models database.Models, bus *Bus, logger *jsonlog.Logger) (*Listener, error) {
+	callback := func(event pq.ListenerEventType, err error) {
+		switch event {
+		case pq.ListenerEventConnected:
+			logger.PrintInfo("notification listener connected", map[string]string{"channel": database.NotifyChannel})
+		case pq.ListenerEventDisconnected:
+			logger.PrintError(err, map[string]string{"channel": database.NotifyChannel, "event": "disconnected"})
+		case pq.ListenerEventReconnected:
+			logger.PrintInfo("notification listener reconnected", map[string]string{"channel": database.NotifyChannel})
+		case pq.ListenerEventConnectionAttemptFailed:
+			logger.PrintError(err, map[string]string{"channel": database.NotifyChannel, "event": "connection attempt failed"})
+		}
+	}
+
+	pl := pq.NewListener(dsn, time.Second, time.Minute, callback)
+	if err := pl.Listen(database.NotifyChannel); err != nil {
+		pl.Close()
+		return nil, err
+	}
+
+	return &Listener{
+		listener: pl,
+		models:   models,
+		bus:      bus,
+		logger:   logger,
+		done:     make(chan struct{}),
+		stopped:  make(chan struct{}),
+	}, nil
+}
+
+// Run обрабатывает уведомления до вызова Close. Запускается в отдельной горутине.
+func (l *Listener) Run() {
+	defer close(l.stopped)
+
+	for {
+		select {
+		case <-l.done:
+			return
+		case n := <-l.listener.Notify:
+			// После переподключения pq присылает nil: уведомления, отправленные пока
+			// соединения не было, потеряны.
+			if n == nil {
+				l.logger.PrintWarn("notification listener resumed, notifications sent while disconnected are lost", nil)
+				continue
+			}
+			l.handle(n.Extra)
+		case <-time.After(90 * time.Second):
+			// Проверяем соединение, если уведомлений давно не было.
+			go l.listener.Ping()
+		}
+	}
+}
+
+// Close прекращает обработку уведомлений и закрывает соединение.
+func (l *Listener) Close() error {
+	close(l.done)
+	<-l.stopped
+	return l.listener.Close()
+}
+
+func (l *Listener) handle(payload string) {
+	var n database.Notification
+	if err := json.Unmarshal([]byte(payload), &n); err != nil {
+		l.logger.PrintError(err, map[string]string{"payload": payload})
+		return
+	}
+
+	// Собственные изменения уже опубликованы в шину моделью напрямую.
+	if n.Instance == database.InstanceID() {
+		return
+	}
+
+	// В уведомлении нет самой записи, поэтому читаем её актуальное состояние. Удалённую
+	// новость прочитать уже нельзя, и подписчики получают поля, переданные в уведомлении.
+	news := &database.News{ID: n.ID, Version: n.Version}
+	if n.Action == database.EventDeleted {
+		if n.News != nil {
+			news = n.News
+		}
+	} else {
+		fetched, err := l.models.News.Get(n.ID)
+		switch {
+		case errors.Is(err, database.ErrRecordNotFound):
+			// Запись успели удалить; уведомление об удалении придёт следом.
+			return
+		case err != nil:
+			l.logger.PrintError(err, map[string]string{"news_id": strconv.FormatInt(n.ID, 10), "action": n.Action})
+			return
+		default:
+			news = fetched
+		}
+	}
+
+	l.bus.Publish(n.Action, news, n.Previous)
+}
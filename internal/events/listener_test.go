@@ -0,0 +1,105 @@
+package events
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/AnKlvy/news-service/internal/data/database"
+	"github.com/AnKlvy/news-service/internal/jsonlog"
+)
+
+func TestListenerDeliversRemoteDeletesToFilteredSubscribers(t *testing.T) {
+	bus := NewBus(10)
+	l := &Listener{
+		models: database.NewMockModels(),
+		bus:    bus,
+		logger: jsonlog.New(&strings.Builder{}, jsonlog.LevelOff),
+	}
+
+	deleted := &database.News{
+		ID:         7,
+		Title:      "Election results",
+		Content:    "Long article body",
+		Categories: []string{"politics"},
+		Status:     "PUBLISHED",
+		Author:     "Editor",
+		Version:    3,
+	}
+	n := database.NewNotification(database.EventDeleted, deleted, nil)
+	n.Instance = "another-instance"
+	payload, err := json.Marshal(n)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(payload), deleted.Content) {
+		t.Fatalf("notification payload carries the article body: %s", payload)
+	}
+
+	sub, err := bus.Subscribe(0, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sub.Close()
+
+	l.handle(string(payload))
+
+	e := <-sub.Events()
+	if e.Type != database.EventDeleted || e.News.ID != deleted.ID || e.News.Version != deleted.Version {
+		t.Fatalf("event = %s %+v, want deleted news %d version %d", e.Type, e.News, deleted.ID, deleted.Version)
+	}
+
+	filter := database.NewsFilter{Title: "election", Categories: []string{"politics"}, Status: "PUBLISHED", Author: "Editor"}
+	if !filter.Matches(e.News) {
+		t.Fatalf("filter %+v does not match the remote delete %+v", filter, e.News)
+	}
+}
+
+// currentNews отдаёт одну новость в её текущем состоянии.
+type currentNews struct {
+	database.MockNewsModel
+	news *database.News
+}
+
+func (m currentNews) Get(id int64) (*database.News, error) {
+	return m.news, nil
+}
+
+func TestListenerDeliversRemoteTransitionsOutOfFilter(t *testing.T) {
+	bus := NewBus(10)
+	archived := &database.News{ID: 7, Title: "Election results", Status: "ARCHIVED", Version: 4}
+	models := database.NewMockModels()
+	models.News = currentNews{news: archived}
+	l := &Listener{
+		models: models,
+		bus:    bus,
+		logger: jsonlog.New(&strings.Builder{}, jsonlog.LevelOff),
+	}
+
+	previous := &database.News{ID: 7, Title: "Election results", Content: "Long article body", Status: "PUBLISHED", Version: 3}
+	n := database.NewNotification(database.EventUpdated, archived, previous)
+	n.Instance = "another-instance"
+	payload, err := json.Marshal(n)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(payload), previous.Content) {
+		t.Fatalf("notification payload carries the article body: %s", payload)
+	}
+
+	sub, err := bus.Subscribe(0, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sub.Close()
+
+	l.handle(string(payload))
+
+	e := <-sub.Events()
+	if e.News.Status != "ARCHIVED" || e.Previous == nil || e.Previous.Status != "PUBLISHED" {
+		t.Fatalf("event = %+v previous %+v, want the transition from PUBLISHED to ARCHIVED", e.News, e.Previous)
+	}
+	if !e.Matches(database.NewsFilter{Status: "PUBLISHED"}) {
+		t.Fatal("the remote transition does not match the filter on the previous status")
+	}
+}
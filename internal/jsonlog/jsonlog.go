@@ -2,10 +2,13 @@ package jsonlog
 
 import (
 	"encoding/json"
+	"fmt"
 	"io"
 	"os"
 	"runtime/debug"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
@@ -15,50 +18,120 @@ type Level int8
 // Инициализируем константы, представляющие уровни серьезности. Используем iota
 // как сокращение для присвоения последовательных целочисленных значений.
 const (
-	LevelInfo  Level = iota // Значение 0.
-	LevelError              // Значение 1.
-	LevelFatal              // Значение 2.
-	LevelOff                // Значение 3.
+	LevelDebug Level = iota // Значение 0.
+	LevelInfo               // Значение 1.
+	LevelWarn               // Значение 2.
+	LevelError              // Значение 3.
+	LevelFatal              // Значение 4.
+	LevelOff                // Значение 5.
 )
 
 // Возвращаем удобочитаемое строковое представление уровня серьезности.
 func (l Level) String() string {
 	switch l {
+	case LevelDebug:
+		return "DEBUG"
 	case LevelInfo:
 		return "INFO"
+	case LevelWarn:
+		return "WARN"
 	case LevelError:
 		return "ERROR"
 	case LevelFatal:
 		return "FATAL"
+	case LevelOff:
+		return "OFF"
 	default:
 		return ""
 	}
 }
 
+// ParseLevel возвращает уровень по его названию без учёта регистра.
+func ParseLevel(s string) (Level, error) {
+	for l := LevelDebug; l <= LevelOff; l++ {
+		if strings.EqualFold(s, l.String()) {
+			return l, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown log level %q", s)
+}
+
 // Определяем собственный тип Logger. Он хранит выходное место назначения для записей,
-// минимальный уровень серьезности, а также мьютекс для синхронизации записей.
+// минимальный уровень серьезности, уровни отдельных компонентов, а также мьютекс
+// для синхронизации записей.
 type Logger struct {
 	out      io.Writer
-	minLevel Level
-	mu       sync.Mutex
+	minLevel atomic.Int32
+	// Уровни, заданные для отдельных компонентов. Компонент записи определяется
+	// по свойству "component".
+	components atomic.Pointer[map[string]Level]
+	mu         sync.Mutex
 }
 
 // Возвращаем новый экземпляр Logger, который записывает записи в журнал при уровне
 // серьезности не ниже указанного.
 func New(out io.Writer, minLevel Level) *Logger {
-	return &Logger{
-		out:      out,
-		minLevel: minLevel,
+	l := &Logger{out: out}
+	l.SetLevel(minLevel)
+	return l
+}
+
+// SetLevel меняет минимальный уровень записей. Безопасен для вызова во время работы.
+func (l *Logger) SetLevel(minLevel Level) {
+	l.minLevel.Store(int32(minLevel))
+}
+
+// Level возвращает минимальный уровень записей.
+func (l *Logger) Level() Level {
+	return Level(l.minLevel.Load())
+}
+
+// SetComponentLevels заменяет уровни отдельных компонентов. Для компонентов, которых
+// нет в levels, действует общий минимальный уровень.
+func (l *Logger) SetComponentLevels(levels map[string]Level) {
+	components := make(map[string]Level, len(levels))
+	for component, level := range levels {
+		components[component] = level
 	}
+	l.components.Store(&components)
+}
+
+// ComponentLevels возвращает уровни отдельных компонентов.
+func (l *Logger) ComponentLevels() map[string]Level {
+	levels := map[string]Level{}
+	if components := l.components.Load(); components != nil {
+		for component, level := range *components {
+			levels[component] = level
+		}
+	}
+	return levels
+}
+
+// enabled проверяет, попадает ли запись в журнал с учётом уровня её компонента.
+func (l *Logger) enabled(level Level, properties map[string]string) bool {
+	if components := l.components.Load(); components != nil && properties["component"] != "" {
+		if minLevel, ok := (*components)[properties["component"]]; ok {
+			return level >= minLevel
+		}
+	}
+	return int32(level) >= l.minLevel.Load()
 }
 
 // Вспомогательные методы для записи логов с разными уровнями серьезности.
 // В качестве второго параметра принимают карту с произвольными "свойствами",
 // которые будут добавлены в запись лога.
+func (l *Logger) PrintDebug(message string, properties map[string]string) {
+	l.print(LevelDebug, message, properties)
+}
+
 func (l *Logger) PrintInfo(message string, properties map[string]string) {
 	l.print(LevelInfo, message, properties)
 }
 
+func (l *Logger) PrintWarn(message string, properties map[string]string) {
+	l.print(LevelWarn, message, properties)
+}
+
 func (l *Logger) PrintError(err error, properties map[string]string) {
 	l.print(LevelError, err.Error(), properties)
 }
@@ -71,7 +144,7 @@ func (l *Logger) PrintFatal(err error, properties map[string]string) {
 // Внутренний метод print для записи логов.
 func (l *Logger) print(level Level, message string, properties map[string]string) (int, error) {
 	// Если уровень серьезности ниже минимального уровня логирования, просто выходим.
-	if level < l.minLevel {
+	if !l.enabled(level, properties) {
 		return 0, nil
 	}
 
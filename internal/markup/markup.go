@@ -0,0 +1,113 @@
+package markup
+
+import (
+	"bytes"
+	"html"
+	"strings"
+
+	"github.com/microcosm-cc/bluemonday"
+	"github.com/yuin/goldmark"
+	"github.com/yuin/goldmark/extension"
+)
+
+// Допустимые форматы содержимого новости.
+const (
+	FormatPlain    = "plain"
+	FormatMarkdown = "markdown"
+	FormatHTML     = "html"
+)
+
+// Formats содержит все поддерживаемые форматы; используется при валидации.
+var Formats = []string{FormatPlain, FormatMarkdown, FormatHTML}
+
+var (
+	// Markdown преобразуется без поддержки «сырого» HTML: goldmark по умолчанию
+	// заменяет такие фрагменты комментарием, а остальное дочищает санитайзер.
+	markdown = goldmark.New(goldmark.WithExtensions(extension.GFM))
+
+	// policy — список разрешённых тегов и атрибутов для пользовательского HTML.
+	// UGCPolicy() разрешает типичную разметку статей (абзацы, списки, ссылки,
+	// изображения, таблицы) и удаляет скрипты, обработчики событий и стили.
+	policy = newPolicy()
+)
+
+func newPolicy() *bluemonday.Policy {
+	p := bluemonday.UGCPolicy()
+	p.RequireNoFollowOnLinks(true)
+	p.AddTargetBlankToFullyQualifiedLinks(true)
+	p.AllowURLSchemes("http", "https", "mailto")
+	return p
+}
+
+// Sanitize удаляет из HTML всё, что не входит в список разрешённых тегов и атрибутов.
+func Sanitize(source string) string {
+	return policy.Sanitize(source)
+}
+
+// Render преобразует содержимое в указанном формате в безопасный HTML.
+func Render(format, source string) (string, error) {
+	switch format {
+	case FormatMarkdown:
+		var buf bytes.Buffer
+		if err := markdown.Convert([]byte(source), &buf); err != nil {
+			return "", err
+		}
+		return Sanitize(buf.String()), nil
+	case FormatHTML:
+		return Sanitize(source), nil
+	default:
+		return renderPlain(source), nil
+	}
+}
+
+// renderPlain экранирует обычный текст и разбивает его на абзацы по пустым строкам;
+// одиночные переводы строк превращаются в <br>.
+func renderPlain(source string) string {
+	source = strings.ReplaceAll(source, "\r\n", "\n")
+
+	var b strings.Builder
+	for _, paragraph := range strings.Split(source, "\n\n") {
+		paragraph = strings.TrimSpace(paragraph)
+		if paragraph == "" {
+			continue
+		}
+		b.WriteString("<p>")
+		b.WriteString(strings.ReplaceAll(html.EscapeString(paragraph), "\n", "<br>\n"))
+		b.WriteString("</p>\n")
+	}
+	return b.String()
+}
+
+// strict удаляет все теги, оставляя только текст. Пробел на месте тега не даёт
+// склеиться словам из соседних блоков (<p>a</p><p>b</p>).
+var strict = func() *bluemonday.Policy {
+	p := bluemonday.StrictPolicy()
+	p.AddSpaceWhenStrippingTag(true)
+	return p
+}()
+
+// PlainText извлекает из HTML обычный текст с нормализованными пробелами.
+func PlainText(source string) string {
+	text := html.UnescapeString(strict.Sanitize(source))
+	return strings.Join(strings.Fields(text), " ")
+}
+
+// WordCount возвращает количество слов в тексте.
+func WordCount(text string) int {
+	return len(strings.Fields(text))
+}
+
+// Excerpt обрезает текст до maxRunes символов по границе слова и добавляет многоточие.
+// Если текст короче ограничения, он возвращается без изменений.
+func Excerpt(text string, maxRunes int) string {
+	runes := []rune(text)
+	if maxRunes <= 0 || len(runes) <= maxRunes {
+		return text
+	}
+
+	cut := string(runes[:maxRunes])
+	if i := strings.LastIndexAny(cut, " \t\n"); i > 0 {
+		cut = cut[:i]
+	}
+	return strings.TrimRight(cut, " ,.;:-–—") + "…"
+}
@@ -0,0 +1,108 @@
+package markup
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestRenderSanitizesHTML(t *testing.T) {
+	tests := []struct {
+		name    string
+		format  string
+		source  string
+		present []string
+		absent  []string
+	}{
+		{
+			name:    "script tag",
+			format:  FormatHTML,
+			source:  `<p>ok</p><script>alert(1)</script>`,
+			present: []string{"<p>ok</p>"},
+			absent:  []string{"<script", "alert(1)"},
+		},
+		{
+			name:    "event handler",
+			format:  FormatHTML,
+			source:  `<img src="https://example.com/a.png" onerror="alert(1)">`,
+			present: []string{`src="https://example.com/a.png"`},
+			absent:  []string{"onerror"},
+		},
+		{
+			name:   "javascript link",
+			format: FormatHTML,
+			source: `<a href="javascript:alert(1)">x</a>`,
+			absent: []string{"javascript:"},
+		},
+		{
+			name:    "external link",
+			format:  FormatHTML,
+			source:  `<a href="https://example.com">x</a>`,
+			present: []string{`rel="nofollow`, `target="_blank"`},
+		},
+		{
+			name:    "markdown raw html",
+			format:  FormatMarkdown,
+			source:  "# Title\n\n<script>alert(1)</script>\n\n**bold**",
+			present: []string{"<h1>Title</h1>", "<strong>bold</strong>"},
+			absent:  []string{"<script", "alert(1)"},
+		},
+		{
+			name:   "markdown javascript link",
+			format: FormatMarkdown,
+			source: "[x](javascript:alert(1))",
+			absent: []string{"javascript:"},
+		},
+		{
+			name:    "plain text",
+			format:  FormatPlain,
+			source:  "<b>a</b>\nb\n\nc",
+			present: []string{"<p>&lt;b&gt;a&lt;/b&gt;<br>\nb</p>", "<p>c</p>"},
+			absent:  []string{"<b>"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Render(tt.format, tt.source)
+			if err != nil {
+				t.Fatalf("Render() error = %v", err)
+			}
+			for _, s := range tt.present {
+				if !strings.Contains(got, s) {
+					t.Errorf("Render() = %q, want it to contain %q", got, s)
+				}
+			}
+			for _, s := range tt.absent {
+				if strings.Contains(got, s) {
+					t.Errorf("Render() = %q, want it not to contain %q", got, s)
+				}
+			}
+		})
+	}
+}
+
+func TestPlainText(t *testing.T) {
+	got := PlainText("<p>Hello&nbsp;<b>world</b></p><p>again</p>")
+	if want := "Hello world again"; got != want {
+		t.Errorf("PlainText() = %q, want %q", got, want)
+	}
+}
+
+func TestExcerpt(t *testing.T) {
+	tests := []struct {
+		text string
+		max  int
+		want string
+	}{
+		{"short", 10, "short"},
+		{"one two three", 9, "one two…"},
+		{"привет, мир", 8, "привет…"},
+		{"anything", 0, "anything"},
+	}
+
+	for _, tt := range tests {
+		if got := Excerpt(tt.text, tt.max); got != tt.want {
+			t.Errorf("Excerpt(%q, %d) = %q, want %q", tt.text, tt.max, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,245 @@
+package media
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"image"
+	"image/draw"
+	"image/jpeg"
+	"image/png"
+	"io"
+	"path"
+	"strings"
+	"sync"
+
+	"github.com/AnKlvy/news-service/internal/data/database"
+	"github.com/AnKlvy/news-service/internal/jsonlog"
+	"github.com/AnKlvy/news-service/internal/storage"
+)
+
+// Типы изображений, для которых генерируются уменьшенные копии.
+var thumbnailTypes = map[string]bool{
+	"image/jpeg": true,
+	"image/png":  true,
+}
+
+// maxThumbnailPixels ограничивает размер изображения, которое декодируется для
+// уменьшенных копий. Небольшой файл может объявить огромные размеры, и его
+// декодирование заняло бы гигабайты памяти.
+const maxThumbnailPixels = 40_000_000
+
+// Thumbnailer генерирует уменьшенные копии загруженных изображений в фоновом пуле
+// воркеров, чтобы загрузка файла не ждала обработки изображения.
+type Thumbnailer struct {
+	storage storage.Storage
+	models  database.Models
+	logger  *jsonlog.Logger
+	// Ширины уменьшенных копий в пикселях; высота вычисляется с сохранением пропорций.
+	widths []int
+
+	// mu защищает closed: после Shutdown канал jobs закрыт, и отправка в него
+	// из ещё не завершившихся обработчиков загрузки вызвала бы панику.
+	mu     sync.Mutex
+	closed bool
+	jobs   chan *Object
+	wg     sync.WaitGroup
+}
+
+// NewThumbnailer создаёт генератор и запускает workers воркеров. Очередь ограничена:
+// если воркеры не справляются, новые задания отбрасываются с записью в журнал.
+func NewThumbnailer(store storage.Storage, models database.Models, logger *jsonlog.Logger, widths []int, workers int) *Thumbnailer {
+	if workers < 1 {
+		workers = 1
+	}
+
+	t := &Thumbnailer{
+		storage: store,
+		models:  models,
+		logger:  logger,
+		widths:  widths,
+		jobs:    make(chan *Object, 100),
+	}
+
+	for i := 0; i < workers; i++ {
+		t.wg.Add(1)
+		go func() {
+			defer t.wg.Done()
+			for obj := range t.jobs {
+				t.process(obj)
+			}
+		}()
+	}
+	return t
+}
+
+// Enqueue ставит изображение в очередь на обработку. После Shutdown задания
+// отбрасываются.
+func (t *Thumbnailer) Enqueue(obj *Object) {
+	if !thumbnailTypes[obj.ContentType] || len(t.widths) == 0 {
+		return
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.closed {
+		t.logger.PrintError(fmt.Errorf("thumbnailer is shut down, skipping %s", obj.Name), nil)
+		return
+	}
+
+	select {
+	case t.jobs <- obj:
+	default:
+		t.logger.PrintError(fmt.Errorf("thumbnail queue is full, skipping %s", obj.Name), nil)
+	}
+}
+
+// Shutdown перестаёт принимать задания и дожидается обработки уже поставленных в очередь.
+func (t *Thumbnailer) Shutdown() {
+	t.mu.Lock()
+	if !t.closed {
+		t.closed = true
+		close(t.jobs)
+	}
+	t.mu.Unlock()
+	t.wg.Wait()
+}
+
+func (t *Thumbnailer) process(obj *Object) {
+	ctx := context.Background()
+
+	f, _, err := t.storage.Open(ctx, obj.Name)
+	if err != nil {
+		t.logger.PrintError(err, map[string]string{"media": obj.Name})
+		return
+	}
+	src, err := decodeImage(f)
+	f.Close()
+	if err != nil {
+		t.logger.PrintError(err, map[string]string{"media": obj.Name})
+		return
+	}
+
+	bounds := src.Bounds()
+	ext := path.Ext(obj.Name)
+	var renditions []*database.Rendition
+	for _, width := range t.widths {
+		// Увеличенные копии не нужны: для них клиенты используют оригинал.
+		if width <= 0 || width >= bounds.Dx() {
+			continue
+		}
+
+		thumb := resize(src, width)
+
+		var buf bytes.Buffer
+		switch obj.ContentType {
+		case "image/png":
+			err = png.Encode(&buf, thumb)
+		default:
+			err = jpeg.Encode(&buf, thumb, &jpeg.Options{Quality: 85})
+		}
+		if err != nil {
+			t.logger.PrintError(err, map[string]string{"media": obj.Name})
+			return
+		}
+
+		name := fmt.Sprintf("%s_w%d%s", strings.TrimSuffix(obj.Name, ext), width, ext)
+		if err := t.storage.Save(ctx, name, &buf); err != nil {
+			t.logger.PrintError(err, map[string]string{"media": obj.Name})
+			return
+		}
+
+		rendition := &database.Rendition{
+			URL:         strings.TrimSuffix(obj.URL, obj.Name) + name,
+			Width:       int32(thumb.Bounds().Dx()),
+			Height:      int32(thumb.Bounds().Dy()),
+			ContentType: obj.ContentType,
+		}
+		renditions = append(renditions, rendition)
+	}
+
+	if len(renditions) == 0 {
+		return
+	}
+	// Копии сохраняются одной транзакцией, чтобы новости с этим изображением
+	// обновились один раз, а не после каждой копии.
+	if err := t.models.Renditions.Insert(obj.URL, renditions); err != nil {
+		t.logger.PrintError(err, map[string]string{"media": obj.Name})
+		return
+	}
+
+	t.logger.PrintInfo("thumbnails generated", map[string]string{"media": obj.Name})
+}
+
+// decodeImage декодирует изображение, предварительно проверив по заголовку, что
+// его размеры не превышают maxThumbnailPixels.
+func decodeImage(r io.ReadSeeker) (image.Image, error) {
+	cfg, _, err := image.DecodeConfig(r)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.Width <= 0 || cfg.Height <= 0 || int64(cfg.Width)*int64(cfg.Height) > maxThumbnailPixels {
+		return nil, fmt.Errorf("image dimensions %dx%d exceed the limit of %d pixels", cfg.Width, cfg.Height, maxThumbnailPixels)
+	}
+
+	if _, err := r.Seek(0, io.SeekStart); err != nil {
+		return nil, err
+	}
+	src, _, err := image.Decode(r)
+	return src, err
+}
+
+// resize уменьшает изображение до указанной ширины с сохранением пропорций. Каждый
+// пиксель результата — среднее значение пикселей исходного изображения, попадающих
+// в соответствующую ему область, что даёт гладкий результат без сторонних библиотек.
+func resize(src image.Image, width int) *image.RGBA {
+	b := src.Bounds()
+	height := b.Dy() * width / b.Dx()
+	if height < 1 {
+		height = 1
+	}
+
+	// Переводим исходное изображение в RGBA, чтобы читать пиксели напрямую из Pix.
+	rgba, ok := src.(*image.RGBA)
+	if !ok {
+		rgba = image.NewRGBA(image.Rect(0, 0, b.Dx(), b.Dy()))
+		draw.Draw(rgba, rgba.Bounds(), src, b.Min, draw.Src)
+	}
+	sb := rgba.Bounds()
+
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		sy0 := sb.Min.Y + y*sb.Dy()/height
+		sy1 := sb.Min.Y + (y+1)*sb.Dy()/height
+		if sy1 <= sy0 {
+			sy1 = sy0 + 1
+		}
+		for x := 0; x < width; x++ {
+			sx0 := sb.Min.X + x*sb.Dx()/width
+			sx1 := sb.Min.X + (x+1)*sb.Dx()/width
+			if sx1 <= sx0 {
+				sx1 = sx0 + 1
+			}
+
+			var r, g, bl, a, n uint32
+			for sy := sy0; sy < sy1; sy++ {
+				i := rgba.PixOffset(sx0, sy)
+				for sx := sx0; sx < sx1; sx++ {
+					r += uint32(rgba.Pix[i])
+					g += uint32(rgba.Pix[i+1])
+					bl += uint32(rgba.Pix[i+2])
+					a += uint32(rgba.Pix[i+3])
+					n++
+					i += 4
+				}
+			}
+
+			j := dst.PixOffset(x, y)
+			dst.Pix[j] = uint8(r / n)
+			dst.Pix[j+1] = uint8(g / n)
+			dst.Pix[j+2] = uint8(bl / n)
+			dst.Pix[j+3] = uint8(a / n)
+		}
+	}
+	return dst
+}
@@ -0,0 +1,63 @@
+package media
+
+import (
+	"bytes"
+	"encoding/binary"
+	"hash/crc32"
+	"image"
+	"image/png"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/AnKlvy/news-service/internal/data/database"
+	"github.com/AnKlvy/news-service/internal/jsonlog"
+)
+
+// encodePNG кодирует изображение width×height и, если заданы declaredWidth и
+// declaredHeight, подменяет размеры в заголовке IHDR, не меняя данные.
+func encodePNG(t *testing.T, width, height, declaredWidth, declaredHeight int) []byte {
+	t.Helper()
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, width, height))); err != nil {
+		t.Fatal(err)
+	}
+	data := buf.Bytes()
+	if declaredWidth == 0 {
+		return data
+	}
+
+	// После сигнатуры (8 байт) идут длина и тип IHDR, затем ширина и высота.
+	binary.BigEndian.PutUint32(data[16:20], uint32(declaredWidth))
+	binary.BigEndian.PutUint32(data[20:24], uint32(declaredHeight))
+	binary.BigEndian.PutUint32(data[29:33], crc32.ChecksumIEEE(data[12:29]))
+	return data
+}
+
+func TestDecodeImage(t *testing.T) {
+	src, err := decodeImage(bytes.NewReader(encodePNG(t, 40, 20, 0, 0)))
+	if err != nil {
+		t.Fatalf("decodeImage() error = %v", err)
+	}
+	if b := src.Bounds(); b.Dx() != 40 || b.Dy() != 20 {
+		t.Fatalf("bounds = %v, want 40x20", b)
+	}
+}
+
+func TestDecodeImageRejectsHugeDimensions(t *testing.T) {
+	bomb := encodePNG(t, 1, 1, 100000, 100000)
+
+	if _, err := decodeImage(bytes.NewReader(bomb)); err == nil || !strings.Contains(err.Error(), "exceed") {
+		t.Fatalf("decodeImage() error = %v, want the dimensions rejected", err)
+	}
+}
+
+func TestEnqueueAfterShutdown(t *testing.T) {
+	th := NewThumbnailer(nil, database.NewMockModels(), jsonlog.New(io.Discard, jsonlog.LevelOff), []int{10}, 1)
+	th.Shutdown()
+
+	// Задание после остановки отбрасывается, а не вызывает панику.
+	th.Enqueue(&Object{Name: "a.png", ContentType: "image/png"})
+	th.Shutdown()
+}
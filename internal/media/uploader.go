@@ -0,0 +1,118 @@
+package media
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/AnKlvy/news-service/internal/storage"
+)
+
+var (
+	ErrTooLarge            = errors.New("file is too large")
+	ErrEmpty               = errors.New("file is empty")
+	ErrUnsupportedType     = errors.New("unsupported file type")
+	ErrContentTypeMismatch = errors.New("declared content type does not match file contents")
+)
+
+// Допустимые типы загружаемых файлов и расширения, с которыми они сохраняются.
+var allowedTypes = map[string]string{
+	"image/jpeg": ".jpg",
+	"image/png":  ".png",
+	"image/gif":  ".gif",
+	"image/webp": ".webp",
+}
+
+// Object описывает загруженный файл.
+type Object struct {
+	Name        string `json:"name"`
+	URL         string `json:"url"`
+	ContentType string `json:"content_type"`
+	Size        int64  `json:"size"`
+}
+
+// Uploader проверяет загружаемые файлы и сохраняет их в хранилище под именем,
+// построенным из хеша содержимого. Повторная загрузка того же файла возвращает
+// тот же адрес и не занимает дополнительного места.
+type Uploader struct {
+	Storage storage.Storage
+	// Максимальный размер файла в байтах.
+	MaxSize int64
+	// Адрес, по которому HTTP-сервер отдаёт загруженные файлы, например
+	// https://news.example.com/media.
+	BaseURL string
+	// Генератор уменьшенных копий. Если не задан, копии не создаются.
+	Thumbnails *Thumbnailer
+}
+
+// Upload читает файл из r, проверяет его размер и тип и сохраняет в хранилище.
+// declaredType — тип, заявленный клиентом; если он указан, он должен совпадать
+// с типом, определённым по содержимому.
+func (u *Uploader) Upload(ctx context.Context, r io.Reader, declaredType string) (*Object, error) {
+	data, err := io.ReadAll(io.LimitReader(r, u.MaxSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > u.MaxSize {
+		return nil, ErrTooLarge
+	}
+	if len(data) == 0 {
+		return nil, ErrEmpty
+	}
+
+	// Тип определяем по содержимому, а не по заголовкам клиента.
+	contentType := http.DetectContentType(data)
+	ext, ok := allowedTypes[contentType]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedType, contentType)
+	}
+	// Браузеры и HTTP-клиенты часто присылают application/octet-stream для любых
+	// файлов, поэтому такой тип не считаем заявленным.
+	declaredType = mediaType(declaredType)
+	if declaredType != "" && declaredType != "application/octet-stream" && !strings.EqualFold(declaredType, contentType) {
+		return nil, ErrContentTypeMismatch
+	}
+
+	sum := sha256.Sum256(data)
+	name := hex.EncodeToString(sum[:]) + ext
+
+	exists, err := u.Storage.Exists(ctx, name)
+	if err != nil {
+		return nil, err
+	}
+	obj := &Object{
+		Name:        name,
+		URL:         u.URL(name),
+		ContentType: contentType,
+		Size:        int64(len(data)),
+	}
+
+	if !exists {
+		if err := u.Storage.Save(ctx, name, bytes.NewReader(data)); err != nil {
+			return nil, err
+		}
+		// Уменьшенные копии генерируются в фоне, клиент получает ответ сразу.
+		if u.Thumbnails != nil {
+			u.Thumbnails.Enqueue(obj)
+		}
+	}
+
+	return obj, nil
+}
+
+// URL возвращает публичный адрес объекта.
+func (u *Uploader) URL(name string) string {
+	return strings.TrimSuffix(u.BaseURL, "/") + "/" + name
+}
+
+// mediaType отбрасывает параметры из значения Content-Type ("image/png; q=1" -> "image/png").
+func mediaType(contentType string) string {
+	t, _, _ := strings.Cut(contentType, ";")
+	return strings.TrimSpace(t)
+}
@@ -0,0 +1,317 @@
+package migrate
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io/fs"
+	"regexp"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/AnKlvy/news-service/internal/jsonlog"
+)
+
+// Таблица schema_migrations совместима с утилитой golang-migrate, поэтому базы данных,
+// которые раньше обновлялись ею, продолжают обновляться встроенным исполнителем.
+const schemaTable = "schema_migrations"
+
+// Ключ advisory lock, которым экземпляры сервиса исключают одновременное применение миграций.
+const lockKey = 7_364_226_001
+
+var (
+	// ErrDirty означает, что предыдущее применение миграции прервалось на середине.
+	ErrDirty = errors.New("database schema is dirty, the last migration must be resolved manually")
+	// ErrUnknownVersion означает, что версия схемы не соответствует ни одной миграции
+	// и при этом не новее последней из них.
+	ErrUnknownVersion = errors.New("database schema version does not match any known migration")
+)
+
+var fileName = regexp.MustCompile(`^(\d+)_(.+)\.(up|down)\.sql$`)
+
+// Migration — одна миграция схемы.
+type Migration struct {
+	Version int64
+	Name    string
+	Up      string
+	Down    string
+}
+
+// Status — состояние схемы базы данных.
+type Status struct {
+	// Текущая версия схемы; 0, если не применено ни одной миграции.
+	Version int64
+	Dirty   bool
+	// Версия последней известной миграции.
+	Latest int64
+	// Миграции, которые ещё не применены.
+	Pending []Migration
+}
+
+// Behind сообщает, что схема отстаёт от миграций, встроенных в сервис.
+func (s Status) Behind() bool {
+	return s.Dirty || s.Version < s.Latest
+}
+
+// Migrator применяет и откатывает миграции.
+type Migrator struct {
+	db         *sql.DB
+	logger     *jsonlog.Logger
+	migrations []Migration
+}
+
+// New читает миграции из fsys. Файлы должны называться <версия>_<имя>.up.sql
+// и <версия>_<имя>.down.sql.
+func New(db *sql.DB, fsys fs.FS, logger *jsonlog.Logger) (*Migrator, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := make(map[int64]*Migration)
+	for _, entry := range entries {
+		match := fileName.FindStringSubmatch(entry.Name())
+		if entry.IsDir() || match == nil {
+			continue
+		}
+
+		version, err := strconv.ParseInt(match[1], 10, 64)
+		if err != nil || version < 1 {
+			return nil, fmt.Errorf("invalid migration version in %q", entry.Name())
+		}
+		body, err := fs.ReadFile(fsys, entry.Name())
+		if err != nil {
+			return nil, err
+		}
+
+		m, ok := byVersion[version]
+		if !ok {
+			m = &Migration{Version: version, Name: match[2]}
+			byVersion[version] = m
+		}
+		if m.Name != match[2] {
+			return nil, fmt.Errorf("migration version %d is used by %q and %q", version, m.Name, match[2])
+		}
+		if match[3] == "up" {
+			m.Up = string(body)
+		} else {
+			m.Down = string(body)
+		}
+	}
+
+	migrator := &Migrator{db: db, logger: logger}
+	for _, m := range byVersion {
+		migrator.migrations = append(migrator.migrations, *m)
+	}
+	slices.SortFunc(migrator.migrations, func(a, b Migration) int {
+		return int(a.Version - b.Version)
+	})
+	return migrator, nil
+}
+
+// Latest возвращает версию последней миграции.
+func (m *Migrator) Latest() int64 {
+	if len(m.migrations) == 0 {
+		return 0
+	}
+	return m.migrations[len(m.migrations)-1].Version
+}
+
+// Status возвращает текущее состояние схемы.
+func (m *Migrator) Status() (Status, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return Status{}, err
+	}
+	defer conn.Close()
+
+	return m.status(ctx, conn)
+}
+
+// Up применяет все недостающие миграции. Схема новее встроенных миграций — обычное
+// состояние после отката сервиса на предыдущую сборку, поэтому Up лишь записывает
+// это в журнал и не возвращает ошибку.
+func (m *Migrator) Up() error {
+	return m.withLock(func(ctx context.Context, conn *sql.Conn) error {
+		status, err := m.status(ctx, conn)
+		if err != nil {
+			return err
+		}
+		if !status.Dirty && status.Version > status.Latest {
+			if m.logger != nil {
+				m.logger.PrintInfo("database schema is newer than this build", map[string]string{
+					"component": "migrate",
+					"version":   strconv.FormatInt(status.Version, 10),
+					"latest":    strconv.FormatInt(status.Latest, 10),
+				})
+			}
+			return nil
+		}
+		return m.migrate(ctx, conn, status, status.Latest)
+	})
+}
+
+// Down откатывает указанное количество последних миграций.
+func (m *Migrator) Down(steps int) error {
+	return m.withLock(func(ctx context.Context, conn *sql.Conn) error {
+		status, err := m.status(ctx, conn)
+		if err != nil {
+			return err
+		}
+
+		i := m.index(status.Version)
+		if i < 0 {
+			return nil
+		}
+		target := int64(0)
+		if i-steps >= 0 {
+			target = m.migrations[i-steps].Version
+		}
+		return m.migrate(ctx, conn, status, target)
+	})
+}
+
+// Goto применяет или откатывает миграции так, чтобы схема оказалась в версии version.
+// Версия 0 означает откат всех миграций.
+func (m *Migrator) Goto(version int64) error {
+	if version != 0 && m.index(version) < 0 {
+		return fmt.Errorf("migration version %d does not exist", version)
+	}
+	return m.withLock(func(ctx context.Context, conn *sql.Conn) error {
+		status, err := m.status(ctx, conn)
+		if err != nil {
+			return err
+		}
+		return m.migrate(ctx, conn, status, version)
+	})
+}
+
+// withLock выполняет fn под advisory lock. Экземпляр, запустившийся одновременно
+// с другим, дождётся окончания его миграций и увидит уже обновлённую схему.
+func (m *Migrator) withLock(fn func(ctx context.Context, conn *sql.Conn) error) error {
+	ctx := context.Background()
+
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if _, err := conn.ExecContext(ctx, `SELECT pg_advisory_lock($1)`, lockKey); err != nil {
+		return err
+	}
+	defer conn.ExecContext(context.Background(), `SELECT pg_advisory_unlock($1)`, lockKey)
+
+	query := `CREATE TABLE IF NOT EXISTS ` + schemaTable + ` (version bigint NOT NULL PRIMARY KEY, dirty boolean NOT NULL)`
+	if _, err := conn.ExecContext(ctx, query); err != nil {
+		return err
+	}
+
+	return fn(ctx, conn)
+}
+
+func (m *Migrator) status(ctx context.Context, conn *sql.Conn) (Status, error) {
+	status := Status{Latest: m.Latest()}
+
+	var exists bool
+	err := conn.QueryRowContext(ctx, `SELECT to_regclass($1) IS NOT NULL`, schemaTable).Scan(&exists)
+	if err != nil {
+		return Status{}, err
+	}
+	if exists {
+		err = conn.QueryRowContext(ctx, `SELECT version, dirty FROM `+schemaTable+` LIMIT 1`).Scan(&status.Version, &status.Dirty)
+		if err != nil && !errors.Is(err, sql.ErrNoRows) {
+			return Status{}, err
+		}
+	}
+
+	for _, migration := range m.migrations {
+		if migration.Version > status.Version {
+			status.Pending = append(status.Pending, migration)
+		}
+	}
+	return status, nil
+}
+
+func (m *Migrator) migrate(ctx context.Context, conn *sql.Conn, status Status, target int64) error {
+	if status.Dirty {
+		return ErrDirty
+	}
+	if status.Version != 0 && m.index(status.Version) < 0 {
+		return ErrUnknownVersion
+	}
+
+	// Применение вперёд.
+	for _, migration := range m.migrations {
+		if migration.Version > status.Version && migration.Version <= target {
+			if err := m.apply(ctx, conn, migration.Up, migration.Version); err != nil {
+				return fmt.Errorf("migration %d_%s up: %w", migration.Version, migration.Name, err)
+			}
+			m.log("migration applied", migration)
+		}
+	}
+
+	// Откат в обратном порядке.
+	for i := len(m.migrations) - 1; i >= 0; i-- {
+		migration := m.migrations[i]
+		if migration.Version <= status.Version && migration.Version > target {
+			previous := int64(0)
+			if i > 0 {
+				previous = m.migrations[i-1].Version
+			}
+			if err := m.apply(ctx, conn, migration.Down, previous); err != nil {
+				return fmt.Errorf("migration %d_%s down: %w", migration.Version, migration.Name, err)
+			}
+			m.log("migration rolled back", migration)
+		}
+	}
+	return nil
+}
+
+// apply выполняет SQL миграции и записывает новую версию схемы в одной транзакции,
+// поэтому неудачная миграция не оставляет схему в промежуточном состоянии.
+func (m *Migrator) apply(ctx context.Context, conn *sql.Conn, query string, version int64) error {
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err = tx.ExecContext(ctx, query); err != nil {
+		return err
+	}
+	if _, err = tx.ExecContext(ctx, `DELETE FROM `+schemaTable); err != nil {
+		return err
+	}
+	if version > 0 {
+		if _, err = tx.ExecContext(ctx, `INSERT INTO `+schemaTable+` (version, dirty) VALUES ($1, false)`, version); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+func (m *Migrator) index(version int64) int {
+	for i, migration := range m.migrations {
+		if migration.Version == version {
+			return i
+		}
+	}
+	return -1
+}
+
+func (m *Migrator) log(message string, migration Migration) {
+	if m.logger == nil {
+		return
+	}
+	m.logger.PrintInfo(message, map[string]string{
+		"component": "migrate",
+		"version":   strconv.FormatInt(migration.Version, 10),
+		"name":      migration.Name,
+	})
+}
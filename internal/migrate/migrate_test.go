@@ -0,0 +1,320 @@
+package migrate
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+	"testing/fstest"
+
+	"github.com/AnKlvy/news-service/migrations"
+)
+
+// fakeDB — состояние базы данных, которое видит фиктивный драйвер: таблица
+// schema_migrations и выполненные SQL миграций.
+type fakeDB struct {
+	mu       sync.Mutex
+	exists   bool
+	hasRow   bool
+	version  int64
+	dirty    bool
+	executed []string
+}
+
+type fakeDriver struct{ db *fakeDB }
+
+func (d fakeDriver) Open(string) (driver.Conn, error) { return &fakeConn{db: d.db}, nil }
+
+type fakeConn struct {
+	db *fakeDB
+	// Снимок состояния на начало транзакции, восстанавливаемый при откате.
+	snapshot *fakeDB
+}
+
+func (c *fakeConn) Prepare(string) (driver.Stmt, error) { return nil, errors.New("not supported") }
+func (c *fakeConn) Close() error                        { return nil }
+func (c *fakeConn) Begin() (driver.Tx, error) {
+	return c.BeginTx(context.Background(), driver.TxOptions{})
+}
+
+func (c *fakeConn) BeginTx(context.Context, driver.TxOptions) (driver.Tx, error) {
+	c.db.mu.Lock()
+	defer c.db.mu.Unlock()
+	c.snapshot = &fakeDB{
+		exists:   c.db.exists,
+		hasRow:   c.db.hasRow,
+		version:  c.db.version,
+		dirty:    c.db.dirty,
+		executed: append([]string(nil), c.db.executed...),
+	}
+	return c, nil
+}
+
+func (c *fakeConn) Commit() error {
+	c.snapshot = nil
+	return nil
+}
+
+func (c *fakeConn) Rollback() error {
+	if c.snapshot == nil {
+		return nil
+	}
+	c.db.mu.Lock()
+	defer c.db.mu.Unlock()
+	c.db.exists, c.db.hasRow, c.db.version, c.db.dirty = c.snapshot.exists, c.snapshot.hasRow, c.snapshot.version, c.snapshot.dirty
+	c.db.executed = c.snapshot.executed
+	c.snapshot = nil
+	return nil
+}
+
+func (c *fakeConn) ExecContext(_ context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	c.db.mu.Lock()
+	defer c.db.mu.Unlock()
+
+	switch {
+	case strings.Contains(query, "pg_advisory"):
+	case strings.HasPrefix(query, "CREATE TABLE IF NOT EXISTS "+schemaTable):
+		c.db.exists = true
+	case strings.HasPrefix(query, "DELETE FROM "+schemaTable):
+		c.db.hasRow = false
+	case strings.HasPrefix(query, "INSERT INTO "+schemaTable):
+		c.db.hasRow, c.db.version, c.db.dirty = true, args[0].Value.(int64), false
+	case strings.Contains(query, "FAIL"):
+		c.db.executed = append(c.db.executed, query)
+		return nil, errors.New("syntax error")
+	default:
+		c.db.executed = append(c.db.executed, query)
+	}
+	return driver.RowsAffected(0), nil
+}
+
+func (c *fakeConn) QueryContext(_ context.Context, query string, _ []driver.NamedValue) (driver.Rows, error) {
+	c.db.mu.Lock()
+	defer c.db.mu.Unlock()
+
+	switch {
+	case strings.Contains(query, "to_regclass"):
+		return &fakeRows{columns: []string{"exists"}, rows: [][]driver.Value{{c.db.exists}}}, nil
+	case strings.HasPrefix(query, "SELECT version, dirty FROM "+schemaTable):
+		rows := &fakeRows{columns: []string{"version", "dirty"}}
+		if c.db.hasRow {
+			rows.rows = [][]driver.Value{{c.db.version, c.db.dirty}}
+		}
+		return rows, nil
+	}
+	return nil, errors.New("unexpected query: " + query)
+}
+
+type fakeRows struct {
+	columns []string
+	rows    [][]driver.Value
+}
+
+func (r *fakeRows) Columns() []string { return r.columns }
+func (r *fakeRows) Close() error      { return nil }
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if len(r.rows) == 0 {
+		return io.EOF
+	}
+	copy(dest, r.rows[0])
+	r.rows = r.rows[1:]
+	return nil
+}
+
+var testFS = fstest.MapFS{
+	"000001_create_a.up.sql":   {Data: []byte("CREATE a")},
+	"000001_create_a.down.sql": {Data: []byte("DROP a")},
+	"000002_create_b.up.sql":   {Data: []byte("CREATE b")},
+	"000002_create_b.down.sql": {Data: []byte("DROP b")},
+	"000010_create_c.up.sql":   {Data: []byte("CREATE c")},
+	"000010_create_c.down.sql": {Data: []byte("DROP c")},
+	"README.md":                {Data: []byte("not a migration")},
+	"migrations.go":            {Data: []byte("package migrations")},
+}
+
+func newTestMigrator(t *testing.T, fsys fstest.MapFS) (*Migrator, *fakeDB) {
+	t.Helper()
+
+	state := &fakeDB{}
+	db := sql.OpenDB(connector{fakeDriver{state}})
+	t.Cleanup(func() { db.Close() })
+
+	m, err := New(db, fsys, nil)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	return m, state
+}
+
+type connector struct{ d fakeDriver }
+
+func (c connector) Connect(context.Context) (driver.Conn, error) { return c.d.Open("") }
+func (c connector) Driver() driver.Driver                        { return c.d }
+
+func assertExecuted(t *testing.T, state *fakeDB, want ...string) {
+	t.Helper()
+	if strings.Join(state.executed, ";") != strings.Join(want, ";") {
+		t.Fatalf("executed = %q, want %q", state.executed, want)
+	}
+}
+
+func TestNewReadsMigrationsInOrder(t *testing.T) {
+	m, _ := newTestMigrator(t, testFS)
+
+	if m.Latest() != 10 {
+		t.Fatalf("Latest() = %d, want 10", m.Latest())
+	}
+	var versions []int64
+	for _, migration := range m.migrations {
+		versions = append(versions, migration.Version)
+		if migration.Up == "" || migration.Down == "" {
+			t.Errorf("migration %d is missing its up or down SQL", migration.Version)
+		}
+	}
+	if len(versions) != 3 || versions[0] != 1 || versions[1] != 2 || versions[2] != 10 {
+		t.Fatalf("versions = %v, want [1 2 10]", versions)
+	}
+}
+
+func TestNewRejectsInvalidFiles(t *testing.T) {
+	tests := []struct {
+		name string
+		fsys fstest.MapFS
+	}{
+		{"zero version", fstest.MapFS{"000000_init.up.sql": {Data: []byte("x")}}},
+		{"conflicting names", fstest.MapFS{
+			"000001_a.up.sql":   {Data: []byte("x")},
+			"000001_b.down.sql": {Data: []byte("x")},
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := New(nil, tt.fsys, nil); err == nil {
+				t.Fatal("New() error = nil, want an error")
+			}
+		})
+	}
+}
+
+func TestUpAndDown(t *testing.T) {
+	m, state := newTestMigrator(t, testFS)
+
+	status, err := m.Status()
+	if err != nil {
+		t.Fatalf("Status() error = %v", err)
+	}
+	if status.Version != 0 || len(status.Pending) != 3 || !status.Behind() {
+		t.Fatalf("Status() = %+v, want version 0 with 3 pending", status)
+	}
+
+	if err := m.Up(); err != nil {
+		t.Fatalf("Up() error = %v", err)
+	}
+	assertExecuted(t, state, "CREATE a", "CREATE b", "CREATE c")
+	if status, _ := m.Status(); status.Version != 10 || status.Behind() {
+		t.Fatalf("Status() after Up = %+v, want version 10", status)
+	}
+
+	// Повторный Up ничего не делает.
+	if err := m.Up(); err != nil {
+		t.Fatalf("second Up() error = %v", err)
+	}
+	assertExecuted(t, state, "CREATE a", "CREATE b", "CREATE c")
+
+	if err := m.Down(1); err != nil {
+		t.Fatalf("Down(1) error = %v", err)
+	}
+	if state.version != 2 {
+		t.Fatalf("version after Down(1) = %d, want 2", state.version)
+	}
+
+	if err := m.Goto(0); err != nil {
+		t.Fatalf("Goto(0) error = %v", err)
+	}
+	assertExecuted(t, state, "CREATE a", "CREATE b", "CREATE c", "DROP c", "DROP b", "DROP a")
+	if state.hasRow {
+		t.Fatalf("schema version = %d after Goto(0), want no version", state.version)
+	}
+}
+
+func TestFailedMigrationKeepsPreviousVersion(t *testing.T) {
+	fsys := fstest.MapFS{
+		"000001_a.up.sql":   {Data: []byte("CREATE a")},
+		"000001_a.down.sql": {Data: []byte("DROP a")},
+		"000002_b.up.sql":   {Data: []byte("FAIL b")},
+		"000002_b.down.sql": {Data: []byte("DROP b")},
+	}
+	m, state := newTestMigrator(t, fsys)
+
+	if err := m.Up(); err == nil || !strings.Contains(err.Error(), "migration 2_b up") {
+		t.Fatalf("Up() error = %v, want the failed migration named", err)
+	}
+	if state.version != 1 || state.dirty {
+		t.Fatalf("version = %d, dirty = %t, want 1 and clean", state.version, state.dirty)
+	}
+	assertExecuted(t, state, "CREATE a")
+}
+
+func TestMigrateRefusesUnknownState(t *testing.T) {
+	m, state := newTestMigrator(t, testFS)
+	state.exists, state.hasRow = true, true
+
+	state.version, state.dirty = 2, true
+	if err := m.Up(); !errors.Is(err, ErrDirty) {
+		t.Fatalf("Up() on a dirty schema error = %v, want %v", err, ErrDirty)
+	}
+
+	state.version, state.dirty = 5, false
+	if err := m.Up(); !errors.Is(err, ErrUnknownVersion) {
+		t.Fatalf("Up() on an unknown version error = %v, want %v", err, ErrUnknownVersion)
+	}
+
+	state.version, state.dirty = 20, true
+	if err := m.Up(); !errors.Is(err, ErrDirty) {
+		t.Fatalf("Up() on a dirty newer schema error = %v, want %v", err, ErrDirty)
+	}
+
+	if err := m.Goto(3); err == nil {
+		t.Fatal("Goto(3) error = nil, want an error for a missing migration")
+	}
+}
+
+func TestUpAcceptsNewerSchema(t *testing.T) {
+	m, state := newTestMigrator(t, testFS)
+	state.exists, state.hasRow, state.version = true, true, 20
+
+	if err := m.Up(); err != nil {
+		t.Fatalf("Up() on a newer schema error = %v, want nil", err)
+	}
+	if state.version != 20 {
+		t.Fatalf("version = %d, want the newer version 20 kept", state.version)
+	}
+	assertExecuted(t, state)
+
+	// Переход к конкретной версии со схемой из будущей сборки по-прежнему невозможен.
+	if err := m.Goto(2); !errors.Is(err, ErrUnknownVersion) {
+		t.Fatalf("Goto(2) on a newer schema error = %v, want %v", err, ErrUnknownVersion)
+	}
+}
+
+func TestEmbeddedMigrations(t *testing.T) {
+	m, err := New(nil, migrations.Files, nil)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+
+	for i, migration := range m.migrations {
+		if migration.Version != int64(i+1) {
+			t.Fatalf("migration %d_%s breaks the version sequence at position %d", migration.Version, migration.Name, i+1)
+		}
+		if strings.TrimSpace(migration.Up) == "" || strings.TrimSpace(migration.Down) == "" {
+			t.Errorf("migration %d_%s is missing its up or down SQL", migration.Version, migration.Name)
+		}
+	}
+}
@@ -0,0 +1,31 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"time"
+)
+
+var (
+	ErrNotFound    = errors.New("object not found")
+	ErrInvalidName = errors.New("invalid object name")
+)
+
+// ObjectInfo содержит сведения о сохранённом объекте.
+type ObjectInfo struct {
+	Name    string
+	Size    int64
+	ModTime time.Time
+}
+
+// Storage — хранилище загруженных файлов. Сервис работает только через этот интерфейс,
+// поэтому локальную файловую систему можно заменить, например, на S3-совместимое хранилище.
+type Storage interface {
+	// Save сохраняет объект под указанным именем. Существующий объект перезаписывается.
+	Save(ctx context.Context, name string, r io.Reader) error
+	// Open открывает объект для чтения. Вызывающая сторона обязана закрыть его.
+	Open(ctx context.Context, name string) (io.ReadSeekCloser, ObjectInfo, error)
+	// Exists сообщает, существует ли объект с указанным именем.
+	Exists(ctx context.Context, name string) (bool, error)
+}
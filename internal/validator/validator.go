@@ -1,7 +1,9 @@
 package validator
 
 import (
+	"net/url"
 	"regexp"
+	"strings"
 )
 
 // Объявляем регулярное выражение для проверки формата email-адресов (мы будем
@@ -65,3 +67,12 @@ func Unique[T comparable](values []T) bool {
 	}
 	return len(values) == len(uniqueValues)
 }
+
+// IsURL возвращает true, если строка является абсолютным URL с хостом и одной из указанных схем.
+func IsURL(value string, schemes ...string) bool {
+	u, err := url.Parse(value)
+	if err != nil || u.Host == "" {
+		return false
+	}
+	return PermittedValue(strings.ToLower(u.Scheme), schemes...)
+}
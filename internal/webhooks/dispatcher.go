@@ -0,0 +1,293 @@
+package webhooks
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	mrand "math/rand/v2"
+	"net"
+	"net/http"
+	"net/netip"
+	"strconv"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/AnKlvy/news-service/internal/data/database"
+	"github.com/AnKlvy/news-service/internal/jsonlog"
+)
+
+// Заголовки исходящих запросов. Получатель проверяет подпись, вычисляя HMAC-SHA256
+// от строки "<timestamp>.<тело запроса>" с секретом подписки, и может отбрасывать
+// повторы по идентификатору доставки.
+const (
+	HeaderEvent     = "X-Webhook-Event"
+	HeaderDelivery  = "X-Webhook-Delivery"
+	HeaderTimestamp = "X-Webhook-Timestamp"
+	HeaderSignature = "X-Webhook-Signature"
+)
+
+// Sign возвращает значение заголовка X-Webhook-Signature для тела запроса.
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// NewSecret генерирует секрет для подписи уведомлений, если клиент не передал свой.
+func NewSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// Config — настройки диспетчера.
+type Config struct {
+	// Количество воркеров, одновременно отправляющих уведомления.
+	Workers int
+	// Как часто воркер проверяет очередь, если в прошлый раз она была пуста.
+	PollInterval time.Duration
+	// Тайм-аут одного запроса к получателю.
+	Timeout time.Duration
+	// После MaxAttempts неудачных попыток доставка помечается как dead.
+	MaxAttempts int
+	// Пауза перед повторной попыткой удваивается после каждой неудачи, начиная
+	// с BackoffBase, но не превышает BackoffMax.
+	BackoffBase time.Duration
+	BackoffMax  time.Duration
+	// Разрешить отправку на адреса внутренних сетей (loopback, частные и link-local).
+	// По умолчанию такие адреса запрещены, чтобы подпиской нельзя было обратиться
+	// к внутренним сервисам и метаданным облака от имени сервера.
+	AllowPrivateTargets bool
+}
+
+// ErrForbiddenTarget возвращается при попытке отправить уведомление на адрес внутренней сети.
+var ErrForbiddenTarget = errors.New("webhook target resolves to a non-public address")
+
+// Количество доставок, которые воркер забирает из очереди за один раз.
+const batchSize = 10
+
+// Dispatcher отправляет уведомления из очереди исходящих доставок.
+type Dispatcher struct {
+	models database.Models
+	logger *jsonlog.Logger
+	config Config
+	client *http.Client
+
+	stop chan struct{}
+	wg   sync.WaitGroup
+}
+
+func NewDispatcher(models database.Models, logger *jsonlog.Logger, config Config) *Dispatcher {
+	if config.Workers < 1 {
+		config.Workers = 1
+	}
+	return &Dispatcher{
+		models: models,
+		logger: logger,
+		config: config,
+		client: &http.Client{
+			Timeout:   config.Timeout,
+			Transport: newTransport(config.AllowPrivateTargets),
+			// Перенаправления не выполняем: подписка должна указывать на конечный адрес.
+			CheckRedirect: func(req *http.Request, via []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+		stop: make(chan struct{}),
+	}
+}
+
+// Start запускает воркеры.
+func (d *Dispatcher) Start() {
+	for i := 0; i < d.config.Workers; i++ {
+		d.wg.Add(1)
+		go func() {
+			defer d.wg.Done()
+			d.work()
+		}()
+	}
+}
+
+// Shutdown останавливает воркеры, дожидаясь завершения начатых отправок. Доставки,
+// которые не успели взять в работу, останутся в очереди до следующего запуска.
+func (d *Dispatcher) Shutdown() {
+	close(d.stop)
+	d.wg.Wait()
+}
+
+func (d *Dispatcher) work() {
+	for {
+		select {
+		case <-d.stop:
+			return
+		default:
+		}
+
+		// Доставка закрепляется за воркером с запасом на время запроса и записи результата.
+		deliveries, err := d.models.WebhookDeliveries.Claim(batchSize, 2*d.config.Timeout+10*time.Second)
+		if err != nil {
+			d.logger.PrintError(err, map[string]string{"component": "webhooks"})
+		}
+		for _, delivery := range deliveries {
+			d.deliver(delivery)
+		}
+
+		// Если очередь не пуста, сразу забираем следующую партию.
+		if len(deliveries) == batchSize {
+			continue
+		}
+		select {
+		case <-d.stop:
+			return
+		case <-time.After(d.config.PollInterval):
+		}
+	}
+}
+
+func (d *Dispatcher) deliver(delivery *database.WebhookDelivery) {
+	started := time.Now()
+	attempt := &database.WebhookAttempt{AttemptedAt: started}
+
+	status, err := d.send(delivery, started)
+	attempt.DurationMS = int32(time.Since(started).Milliseconds())
+	attempt.ResponseStatus = int32(status)
+
+	delivery.Attempts++
+	switch {
+	case err == nil:
+		delivery.Status = database.DeliveryDelivered
+		delivery.LastError = ""
+		d.logger.PrintDebug("webhook delivered", map[string]string{
+			"component":   "webhooks",
+			"delivery_id": strconv.FormatInt(delivery.ID, 10),
+			"webhook_id":  strconv.FormatInt(delivery.WebhookID, 10),
+			"status":      strconv.Itoa(status),
+		})
+	case int(delivery.Attempts) >= d.config.MaxAttempts:
+		attempt.Error = err.Error()
+		delivery.Status = database.DeliveryDead
+		delivery.LastError = err.Error()
+		d.logger.PrintError(err, map[string]string{
+			"component":   "webhooks",
+			"delivery_id": strconv.FormatInt(delivery.ID, 10),
+			"webhook_id":  strconv.FormatInt(delivery.WebhookID, 10),
+			"status":      database.DeliveryDead,
+		})
+	default:
+		attempt.Error = err.Error()
+		delivery.LastError = err.Error()
+		delivery.NextAttemptAt = time.Now().Add(d.backoff(int(delivery.Attempts)))
+	}
+
+	if err := d.models.WebhookDeliveries.RecordAttempt(delivery, attempt); err != nil {
+		d.logger.PrintError(err, map[string]string{
+			"component":   "webhooks",
+			"delivery_id": strconv.FormatInt(delivery.ID, 10),
+		})
+	}
+}
+
+// send отправляет уведомление и возвращает код ответа получателя. Успешной считается
+// доставка с любым кодом 2xx.
+func (d *Dispatcher) send(delivery *database.WebhookDelivery, now time.Time) (int, error) {
+	req, err := http.NewRequest(http.MethodPost, delivery.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return 0, err
+	}
+
+	timestamp := now.Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "news-service-webhooks/1.0")
+	req.Header.Set(HeaderEvent, delivery.EventType)
+	req.Header.Set(HeaderDelivery, strconv.FormatInt(delivery.ID, 10))
+	req.Header.Set(HeaderTimestamp, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(HeaderSignature, Sign(delivery.Secret, timestamp, delivery.Payload))
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	// Тело ответа не сохраняется: история доставок доступна через API, и через неё
+	// нельзя читать ответы произвольных серверов.
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return resp.StatusCode, fmt.Errorf("unexpected response status %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
+
+// newTransport возвращает транспорт, который проверяет адрес получателя при каждом
+// подключении, уже после разрешения имени: проверка URL при создании подписки не
+// защищает от имён, которые позже начнут указывать на внутренний адрес. Прокси из
+// окружения не используется, иначе проверялся бы адрес прокси, а не получателя.
+func newTransport(allowPrivate bool) *http.Transport {
+	dialer := &net.Dialer{
+		Timeout:   30 * time.Second,
+		KeepAlive: 30 * time.Second,
+	}
+	if !allowPrivate {
+		dialer.Control = func(network, address string, _ syscall.RawConn) error {
+			addrPort, err := netip.ParseAddrPort(address)
+			if err != nil || !publicAddr(addrPort.Addr()) {
+				return ErrForbiddenTarget
+			}
+			return nil
+		}
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+	return transport
+}
+
+// Диапазоны, не входящие в частные сети по netip.Addr.IsPrivate, но тоже недоступные
+// извне: общее адресное пространство операторов (RFC 6598) и сети для документации
+// и тестирования.
+var nonPublicPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),
+	netip.MustParsePrefix("100.64.0.0/10"),
+	netip.MustParsePrefix("192.0.0.0/24"),
+	netip.MustParsePrefix("198.18.0.0/15"),
+	netip.MustParsePrefix("240.0.0.0/4"),
+	netip.MustParsePrefix("64:ff9b::/96"),
+}
+
+// publicAddr сообщает, что адрес принадлежит публичной сети.
+func publicAddr(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	if !addr.IsGlobalUnicast() || addr.IsPrivate() {
+		return false
+	}
+	for _, prefix := range nonPublicPrefixes {
+		if prefix.Contains(addr) {
+			return false
+		}
+	}
+	return true
+}
+
+// backoff возвращает паузу перед следующей попыткой. Случайная добавка до 20% не даёт
+// повторным запросам к одному получателю выстраиваться в синхронные волны.
+func (d *Dispatcher) backoff(attempts int) time.Duration {
+	delay := d.config.BackoffBase
+	for i := 1; i < attempts && delay < d.config.BackoffMax; i++ {
+		delay *= 2
+	}
+	if delay > d.config.BackoffMax {
+		delay = d.config.BackoffMax
+	}
+	return delay + time.Duration(mrand.Int64N(int64(delay)/5+1))
+}
@@ -0,0 +1,263 @@
+package webhooks
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/AnKlvy/news-service/internal/data/database"
+	"github.com/AnKlvy/news-service/internal/jsonlog"
+)
+
+// recordingDeliveries сохраняет результаты попыток вместо записи в базу данных.
+type recordingDeliveries struct {
+	database.MockWebhookDeliveryModel
+
+	mu       sync.Mutex
+	attempts []database.WebhookAttempt
+}
+
+func (m *recordingDeliveries) RecordAttempt(d *database.WebhookDelivery, attempt *database.WebhookAttempt) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.attempts = append(m.attempts, *attempt)
+	return nil
+}
+
+func newTestDispatcher(config Config) (*Dispatcher, *recordingDeliveries) {
+	deliveries := &recordingDeliveries{}
+	models := database.NewMockModels()
+	models.WebhookDeliveries = deliveries
+
+	if config.Timeout == 0 {
+		config.Timeout = time.Second
+	}
+	if config.MaxAttempts == 0 {
+		config.MaxAttempts = 3
+	}
+	if config.BackoffBase == 0 {
+		config.BackoffBase = time.Second
+		config.BackoffMax = time.Minute
+	}
+	config.AllowPrivateTargets = true
+	return NewDispatcher(models, jsonlog.New(io.Discard, jsonlog.LevelOff), config), deliveries
+}
+
+func newTestDelivery(url string) *database.WebhookDelivery {
+	return &database.WebhookDelivery{
+		ID:        42,
+		WebhookID: 7,
+		EventType: database.EventCreated,
+		Payload:   []byte(`{"event":"created"}`),
+		Status:    database.DeliveryPending,
+		URL:       url,
+		Secret:    "0123456789abcdef",
+	}
+}
+
+func TestSign(t *testing.T) {
+	body := []byte(`{"a":1}`)
+
+	mac := hmac.New(sha256.New, []byte("secret"))
+	mac.Write([]byte("1700000000." + string(body)))
+	want := "sha256=" + hex.EncodeToString(mac.Sum(nil))
+
+	if got := Sign("secret", 1700000000, body); got != want {
+		t.Fatalf("Sign() = %q, want %q", got, want)
+	}
+	if Sign("secret", 1700000001, body) == want {
+		t.Fatal("Sign() does not depend on the timestamp")
+	}
+}
+
+func TestDeliverSignsRequest(t *testing.T) {
+	var header http.Header
+	var body []byte
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header = r.Header.Clone()
+		body, _ = io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	d, deliveries := newTestDispatcher(Config{})
+	delivery := newTestDelivery(srv.URL)
+	d.deliver(delivery)
+
+	if delivery.Status != database.DeliveryDelivered || delivery.Attempts != 1 || delivery.LastError != "" {
+		t.Fatalf("delivery = %+v, want delivered after 1 attempt", delivery)
+	}
+	if len(deliveries.attempts) != 1 || deliveries.attempts[0].ResponseStatus != http.StatusNoContent {
+		t.Fatalf("attempts = %+v, want one with status %d", deliveries.attempts, http.StatusNoContent)
+	}
+
+	timestamp, err := strconv.ParseInt(header.Get(HeaderTimestamp), 10, 64)
+	if err != nil {
+		t.Fatalf("invalid %s header %q", HeaderTimestamp, header.Get(HeaderTimestamp))
+	}
+	if got, want := header.Get(HeaderSignature), Sign(delivery.Secret, timestamp, body); got != want {
+		t.Errorf("%s = %q, want %q", HeaderSignature, got, want)
+	}
+	if got := header.Get(HeaderEvent); got != delivery.EventType {
+		t.Errorf("%s = %q, want %q", HeaderEvent, got, delivery.EventType)
+	}
+	if got := header.Get(HeaderDelivery); got != "42" {
+		t.Errorf("%s = %q, want %q", HeaderDelivery, got, "42")
+	}
+}
+
+func TestDeliverRetriesOnErrorStatus(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "internal secret token=abc", http.StatusBadGateway)
+	}))
+	defer srv.Close()
+
+	d, deliveries := newTestDispatcher(Config{})
+	delivery := newTestDelivery(srv.URL)
+	before := time.Now()
+	d.deliver(delivery)
+
+	if delivery.Status != database.DeliveryPending || delivery.Attempts != 1 {
+		t.Fatalf("delivery = %+v, want pending after 1 attempt", delivery)
+	}
+	if !delivery.NextAttemptAt.After(before) {
+		t.Errorf("NextAttemptAt = %v, want a retry after %v", delivery.NextAttemptAt, before)
+	}
+	if want := "unexpected response status 502"; delivery.LastError != want {
+		t.Errorf("LastError = %q, want %q", delivery.LastError, want)
+	}
+	attempt := deliveries.attempts[0]
+	if attempt.ResponseStatus != http.StatusBadGateway || strings.Contains(attempt.Error, "secret") {
+		t.Errorf("attempt = %+v, want status %d without the response body", attempt, http.StatusBadGateway)
+	}
+}
+
+func TestDeliverMarksDeadAfterMaxAttempts(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+
+	d, _ := newTestDispatcher(Config{MaxAttempts: 3})
+	delivery := newTestDelivery(srv.URL)
+	for i := 0; i < 2; i++ {
+		d.deliver(delivery)
+		if delivery.Status != database.DeliveryPending {
+			t.Fatalf("status after attempt %d = %q, want %q", i+1, delivery.Status, database.DeliveryPending)
+		}
+	}
+
+	d.deliver(delivery)
+	if delivery.Status != database.DeliveryDead || delivery.Attempts != 3 {
+		t.Fatalf("delivery = %+v, want dead after 3 attempts", delivery)
+	}
+}
+
+func TestDeliverReplayedDelivery(t *testing.T) {
+	var fail bool
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if fail {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer srv.Close()
+
+	d, _ := newTestDispatcher(Config{MaxAttempts: 1})
+	delivery := newTestDelivery(srv.URL)
+	fail = true
+	d.deliver(delivery)
+	if delivery.Status != database.DeliveryDead {
+		t.Fatalf("status = %q, want %q", delivery.Status, database.DeliveryDead)
+	}
+
+	// Replay возвращает доставку в очередь с обнулённым счётчиком попыток.
+	delivery.Status = database.DeliveryPending
+	delivery.Attempts = 0
+	delivery.LastError = ""
+	fail = false
+	d.deliver(delivery)
+	if delivery.Status != database.DeliveryDelivered || delivery.Attempts != 1 {
+		t.Fatalf("delivery = %+v, want delivered after replay", delivery)
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	d, _ := newTestDispatcher(Config{BackoffBase: time.Second, BackoffMax: 10 * time.Second})
+
+	tests := []struct {
+		attempts int
+		min      time.Duration
+	}{
+		{1, time.Second},
+		{2, 2 * time.Second},
+		{3, 4 * time.Second},
+		{4, 8 * time.Second},
+		{5, 10 * time.Second},
+		{20, 10 * time.Second},
+	}
+
+	for _, tt := range tests {
+		got := d.backoff(tt.attempts)
+		// Случайная добавка не превышает 20% паузы.
+		if got < tt.min || got > tt.min+tt.min/5 {
+			t.Errorf("backoff(%d) = %v, want between %v and %v", tt.attempts, got, tt.min, tt.min+tt.min/5)
+		}
+	}
+}
+
+func TestDeliverRejectsPrivateTargets(t *testing.T) {
+	var called bool
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		called = true
+	}))
+	defer srv.Close()
+
+	d, _ := newTestDispatcher(Config{})
+	d.client.Transport = newTransport(false)
+
+	delivery := newTestDelivery(srv.URL)
+	d.deliver(delivery)
+
+	if called {
+		t.Fatal("request reached a loopback target")
+	}
+	if delivery.Status != database.DeliveryPending || !strings.Contains(delivery.LastError, ErrForbiddenTarget.Error()) {
+		t.Fatalf("delivery = %+v, want a retry with %q", delivery, ErrForbiddenTarget)
+	}
+}
+
+func TestPublicAddr(t *testing.T) {
+	tests := []struct {
+		addr string
+		want bool
+	}{
+		{"93.184.216.34", true},
+		{"2606:2800:220:1:248:1893:25c8:1946", true},
+		{"127.0.0.1", false},
+		{"10.1.2.3", false},
+		{"172.16.0.1", false},
+		{"192.168.1.1", false},
+		{"169.254.169.254", false},
+		{"100.64.0.1", false},
+		{"0.0.0.0", false},
+		{"::1", false},
+		{"fe80::1", false},
+		{"fd00::1", false},
+		{"::ffff:127.0.0.1", false},
+	}
+
+	for _, tt := range tests {
+		if got := publicAddr(netip.MustParseAddr(tt.addr)); got != tt.want {
+			t.Errorf("publicAddr(%s) = %t, want %t", tt.addr, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,2 @@
+DROP INDEX IF EXISTS news_published_at_idx;
+ALTER TABLE news DROP COLUMN published_at;
@@ -0,0 +1,6 @@
+ALTER TABLE news ADD COLUMN published_at timestamp(0) with time zone;
+
+-- Для уже опубликованных новостей считаем датой публикации дату создания.
+UPDATE news SET published_at = created_at WHERE status = 'PUBLISHED';
+
+CREATE INDEX IF NOT EXISTS news_published_at_idx ON news (published_at) WHERE status = 'PUBLISHED';
@@ -0,0 +1,3 @@
+ALTER TABLE news
+    DROP COLUMN content_html,
+    DROP COLUMN content_format;
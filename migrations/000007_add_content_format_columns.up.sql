@@ -0,0 +1,9 @@
+ALTER TABLE news
+    ADD COLUMN content_format TEXT NOT NULL DEFAULT 'plain' CHECK (content_format IN ('plain', 'markdown', 'html')),
+    ADD COLUMN content_html TEXT NOT NULL DEFAULT '';
+
+-- Все существующие статьи хранятся как обычный текст: заполняем для них HTML-представление,
+-- экранируя спецсимволы. При следующем сохранении статьи оно будет пересчитано сервисом.
+UPDATE news SET content_html = '<p>' || replace(
+    replace(replace(replace(replace(replace(content, '&', '&amp;'), '<', '&lt;'), '>', '&gt;'), '"', '&#34;'), '''', '&#39;'),
+    E'\n', E'<br>\n') || '</p>';
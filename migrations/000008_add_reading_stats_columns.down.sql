@@ -0,0 +1,5 @@
+DROP INDEX IF EXISTS news_reading_time_idx;
+ALTER TABLE news
+    DROP COLUMN excerpt,
+    DROP COLUMN reading_time,
+    DROP COLUMN word_count;
@@ -0,0 +1,12 @@
+ALTER TABLE news
+    ADD COLUMN word_count integer NOT NULL DEFAULT 0,
+    ADD COLUMN reading_time integer NOT NULL DEFAULT 0,
+    ADD COLUMN excerpt TEXT NOT NULL DEFAULT '';
+
+-- Приблизительно заполняем статистику для существующих статей. Точные значения
+-- сервис пересчитает при следующем сохранении статьи.
+UPDATE news SET word_count = coalesce(array_length(regexp_split_to_array(btrim(content), '\s+'), 1), 0)
+WHERE btrim(content) <> '';
+UPDATE news SET reading_time = ceil(word_count / 200.0), excerpt = left(content, 200);
+
+CREATE INDEX IF NOT EXISTS news_reading_time_idx ON news (reading_time);
@@ -0,0 +1,20 @@
+CREATE TABLE IF NOT EXISTS news_media (
+    id bigserial PRIMARY KEY,
+    news_id bigint NOT NULL REFERENCES news ON DELETE CASCADE,
+    position integer NOT NULL,
+    url TEXT NOT NULL,
+    alt_text TEXT NOT NULL DEFAULT '',
+    caption TEXT NOT NULL DEFAULT '',
+    credit TEXT NOT NULL DEFAULT '',
+    width integer NOT NULL DEFAULT 0 CHECK (width >= 0),
+    height integer NOT NULL DEFAULT 0 CHECK (height >= 0),
+    UNIQUE (news_id, position)
+);
+
+-- Миграция 000005 оборачивала пустой image_url в массив из одного NULL: убираем такие значения.
+UPDATE news SET image_urls = array_remove(image_urls, NULL) WHERE image_urls IS NOT NULL;
+
+-- Переносим существующие изображения в новую таблицу, сохраняя их порядок.
+INSERT INTO news_media (news_id, position, url)
+SELECT news.id, u.position, u.url
+FROM news, unnest(news.image_urls) WITH ORDINALITY AS u(url, position);
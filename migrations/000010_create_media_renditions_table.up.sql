@@ -0,0 +1,9 @@
+CREATE TABLE IF NOT EXISTS media_renditions (
+    source_url TEXT NOT NULL,
+    width integer NOT NULL CHECK (width > 0),
+    height integer NOT NULL CHECK (height > 0),
+    url TEXT NOT NULL,
+    content_type TEXT NOT NULL,
+    created_at timestamp(0) with time zone NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (source_url, width)
+);
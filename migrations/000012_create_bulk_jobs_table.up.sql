@@ -0,0 +1,18 @@
+CREATE TABLE IF NOT EXISTS bulk_jobs (
+    id bigserial PRIMARY KEY,
+    created_at timestamp(0) with time zone NOT NULL DEFAULT NOW(),
+    updated_at timestamp(0) with time zone NOT NULL DEFAULT NOW(),
+    action TEXT NOT NULL,
+    params jsonb NOT NULL,
+    status TEXT NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'running', 'completed', 'failed')),
+    matched bigint NOT NULL DEFAULT 0,
+    processed bigint NOT NULL DEFAULT 0,
+    affected bigint NOT NULL DEFAULT 0,
+    -- Идентификатор последней обработанной новости: задание продолжается с него после перезапуска.
+    last_id bigint NOT NULL DEFAULT 0,
+    error TEXT NOT NULL DEFAULT '',
+    lease_until timestamp(0) with time zone NOT NULL DEFAULT NOW(),
+    finished_at timestamp(0) with time zone
+);
+
+CREATE INDEX IF NOT EXISTS bulk_jobs_unfinished_idx ON bulk_jobs (lease_until) WHERE status IN ('pending', 'running');
@@ -0,0 +1,4 @@
+ALTER TABLE news DROP CONSTRAINT IF EXISTS image_urls_length_check;
+ALTER TABLE news DROP CONSTRAINT IF EXISTS title_length_check;
+ALTER TABLE news DROP CONSTRAINT IF EXISTS categories_length_check;
+ALTER TABLE news ADD CONSTRAINT categories_length_check CHECK (array_length(categories, 1) BETWEEN 1 AND 5);
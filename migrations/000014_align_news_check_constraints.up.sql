@@ -0,0 +1,5 @@
+-- Ограничения должны совпадать с правилами ValidateNews (см. internal/data/database/constraints.go).
+ALTER TABLE news DROP CONSTRAINT IF EXISTS categories_length_check;
+ALTER TABLE news ADD CONSTRAINT categories_length_check CHECK (cardinality(categories) BETWEEN 1 AND 10);
+ALTER TABLE news ADD CONSTRAINT title_length_check CHECK (octet_length(title) <= 500);
+ALTER TABLE news ADD CONSTRAINT image_urls_length_check CHECK (cardinality(image_urls) <= 7);
@@ -0,0 +1,8 @@
+// Package migrations содержит SQL-миграции схемы базы данных. Файлы встраиваются
+// в бинарный файл сервиса и применяются встроенным исполнителем (internal/migrate).
+package migrations
+
+import "embed"
+
+//go:embed *.sql
+var Files embed.FS
@@ -10,6 +10,7 @@ import (
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
 	emptypb "google.golang.org/protobuf/types/known/emptypb"
+	fieldmaskpb "google.golang.org/protobuf/types/known/fieldmaskpb"
 	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
 	reflect "reflect"
 	sync "sync"
@@ -23,6 +24,166 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+type Rendition struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Url           string                 `protobuf:"bytes,1,opt,name=url,proto3" json:"url,omitempty"`
+	Width         int32                  `protobuf:"varint,2,opt,name=width,proto3" json:"width,omitempty"`
+	Height        int32                  `protobuf:"varint,3,opt,name=height,proto3" json:"height,omitempty"`
+	ContentType   string                 `protobuf:"bytes,4,opt,name=content_type,json=contentType,proto3" json:"content_type,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Rendition) Reset() {
+	*x = Rendition{}
+	mi := &file_news_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Rendition) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Rendition) ProtoMessage() {}
+
+func (x *Rendition) ProtoReflect() protoreflect.Message {
+	mi := &file_news_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Rendition.ProtoReflect.Descriptor instead.
+func (*Rendition) Descriptor() ([]byte, []int) {
+	return file_news_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Rendition) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+func (x *Rendition) GetWidth() int32 {
+	if x != nil {
+		return x.Width
+	}
+	return 0
+}
+
+func (x *Rendition) GetHeight() int32 {
+	if x != nil {
+		return x.Height
+	}
+	return 0
+}
+
+func (x *Rendition) GetContentType() string {
+	if x != nil {
+		return x.ContentType
+	}
+	return ""
+}
+
+type Media struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Url           string                 `protobuf:"bytes,1,opt,name=url,proto3" json:"url,omitempty"`
+	AltText       string                 `protobuf:"bytes,2,opt,name=alt_text,json=altText,proto3" json:"alt_text,omitempty"`
+	Caption       string                 `protobuf:"bytes,3,opt,name=caption,proto3" json:"caption,omitempty"`
+	Credit        string                 `protobuf:"bytes,4,opt,name=credit,proto3" json:"credit,omitempty"`
+	Width         int32                  `protobuf:"varint,5,opt,name=width,proto3" json:"width,omitempty"`
+	Height        int32                  `protobuf:"varint,6,opt,name=height,proto3" json:"height,omitempty"`
+	Renditions    []*Rendition           `protobuf:"bytes,7,rep,name=renditions,proto3" json:"renditions,omitempty"` // Output only, generated for uploaded images
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Media) Reset() {
+	*x = Media{}
+	mi := &file_news_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Media) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Media) ProtoMessage() {}
+
+func (x *Media) ProtoReflect() protoreflect.Message {
+	mi := &file_news_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Media.ProtoReflect.Descriptor instead.
+func (*Media) Descriptor() ([]byte, []int) {
+	return file_news_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Media) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+func (x *Media) GetAltText() string {
+	if x != nil {
+		return x.AltText
+	}
+	return ""
+}
+
+func (x *Media) GetCaption() string {
+	if x != nil {
+		return x.Caption
+	}
+	return ""
+}
+
+func (x *Media) GetCredit() string {
+	if x != nil {
+		return x.Credit
+	}
+	return ""
+}
+
+func (x *Media) GetWidth() int32 {
+	if x != nil {
+		return x.Width
+	}
+	return 0
+}
+
+func (x *Media) GetHeight() int32 {
+	if x != nil {
+		return x.Height
+	}
+	return 0
+}
+
+func (x *Media) GetRenditions() []*Rendition {
+	if x != nil {
+		return x.Renditions
+	}
+	return nil
+}
+
 type News struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
@@ -35,13 +196,20 @@ type News struct {
 	ImageUrls     []string               `protobuf:"bytes,8,rep,name=image_urls,json=imageUrls,proto3" json:"image_urls,omitempty"`
 	Author        string                 `protobuf:"bytes,9,opt,name=author,proto3" json:"author,omitempty"`
 	Version       int32                  `protobuf:"varint,10,opt,name=version,proto3" json:"version,omitempty"`
+	PublishedAt   *timestamppb.Timestamp `protobuf:"bytes,11,opt,name=published_at,json=publishedAt,proto3" json:"published_at,omitempty"`
+	ContentFormat string                 `protobuf:"bytes,12,opt,name=content_format,json=contentFormat,proto3" json:"content_format,omitempty"`
+	ContentHtml   string                 `protobuf:"bytes,13,opt,name=content_html,json=contentHtml,proto3" json:"content_html,omitempty"` // Only set when include_content_html is requested
+	WordCount     int32                  `protobuf:"varint,14,opt,name=word_count,json=wordCount,proto3" json:"word_count,omitempty"`
+	ReadingTime   int32                  `protobuf:"varint,15,opt,name=reading_time,json=readingTime,proto3" json:"reading_time,omitempty"` // Estimated reading time in minutes
+	Excerpt       string                 `protobuf:"bytes,16,opt,name=excerpt,proto3" json:"excerpt,omitempty"`
+	Media         []*Media               `protobuf:"bytes,17,rep,name=media,proto3" json:"media,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *News) Reset() {
 	*x = News{}
-	mi := &file_news_proto_msgTypes[0]
+	mi := &file_news_proto_msgTypes[2]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -53,7 +221,7 @@ func (x *News) String() string {
 func (*News) ProtoMessage() {}
 
 func (x *News) ProtoReflect() protoreflect.Message {
-	mi := &file_news_proto_msgTypes[0]
+	mi := &file_news_proto_msgTypes[2]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -66,7 +234,7 @@ func (x *News) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use News.ProtoReflect.Descriptor instead.
 func (*News) Descriptor() ([]byte, []int) {
-	return file_news_proto_rawDescGZIP(), []int{0}
+	return file_news_proto_rawDescGZIP(), []int{2}
 }
 
 func (x *News) GetId() int64 {
@@ -139,6 +307,55 @@ func (x *News) GetVersion() int32 {
 	return 0
 }
 
+func (x *News) GetPublishedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.PublishedAt
+	}
+	return nil
+}
+
+func (x *News) GetContentFormat() string {
+	if x != nil {
+		return x.ContentFormat
+	}
+	return ""
+}
+
+func (x *News) GetContentHtml() string {
+	if x != nil {
+		return x.ContentHtml
+	}
+	return ""
+}
+
+func (x *News) GetWordCount() int32 {
+	if x != nil {
+		return x.WordCount
+	}
+	return 0
+}
+
+func (x *News) GetReadingTime() int32 {
+	if x != nil {
+		return x.ReadingTime
+	}
+	return 0
+}
+
+func (x *News) GetExcerpt() string {
+	if x != nil {
+		return x.Excerpt
+	}
+	return ""
+}
+
+func (x *News) GetMedia() []*Media {
+	if x != nil {
+		return x.Media
+	}
+	return nil
+}
+
 type Metadata struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	CurrentPage   int32                  `protobuf:"varint,1,opt,name=current_page,json=currentPage,proto3" json:"current_page,omitempty"`
@@ -152,7 +369,7 @@ type Metadata struct {
 
 func (x *Metadata) Reset() {
 	*x = Metadata{}
-	mi := &file_news_proto_msgTypes[1]
+	mi := &file_news_proto_msgTypes[3]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -164,7 +381,7 @@ func (x *Metadata) String() string {
 func (*Metadata) ProtoMessage() {}
 
 func (x *Metadata) ProtoReflect() protoreflect.Message {
-	mi := &file_news_proto_msgTypes[1]
+	mi := &file_news_proto_msgTypes[3]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -177,7 +394,7 @@ func (x *Metadata) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use Metadata.ProtoReflect.Descriptor instead.
 func (*Metadata) Descriptor() ([]byte, []int) {
-	return file_news_proto_rawDescGZIP(), []int{1}
+	return file_news_proto_rawDescGZIP(), []int{3}
 }
 
 func (x *Metadata) GetCurrentPage() int32 {
@@ -215,22 +432,25 @@ func (x *Metadata) GetTotalRecords() int32 {
 	return 0
 }
 
-// TODO add filtering by author
 type GetAllRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Title         string                 `protobuf:"bytes,1,opt,name=title,proto3" json:"title,omitempty"`
-	Categories    []string               `protobuf:"bytes,2,rep,name=categories,proto3" json:"categories,omitempty"`
-	Status        string                 `protobuf:"bytes,3,opt,name=status,proto3" json:"status,omitempty"`
-	Page          int32                  `protobuf:"varint,4,opt,name=page,proto3" json:"page,omitempty"`
-	PageSize      int32                  `protobuf:"varint,5,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
-	Sort          string                 `protobuf:"bytes,6,opt,name=sort,proto3" json:"sort,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	state              protoimpl.MessageState `protogen:"open.v1"`
+	Title              string                 `protobuf:"bytes,1,opt,name=title,proto3" json:"title,omitempty"`
+	Categories         []string               `protobuf:"bytes,2,rep,name=categories,proto3" json:"categories,omitempty"`
+	Status             string                 `protobuf:"bytes,3,opt,name=status,proto3" json:"status,omitempty"`
+	Page               int32                  `protobuf:"varint,4,opt,name=page,proto3" json:"page,omitempty"`
+	PageSize           int32                  `protobuf:"varint,5,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	Sort               string                 `protobuf:"bytes,6,opt,name=sort,proto3" json:"sort,omitempty"`
+	Author             string                 `protobuf:"bytes,7,opt,name=author,proto3" json:"author,omitempty"`
+	IncludeContentHtml bool                   `protobuf:"varint,8,opt,name=include_content_html,json=includeContentHtml,proto3" json:"include_content_html,omitempty"`
+	MinReadingTime     int32                  `protobuf:"varint,9,opt,name=min_reading_time,json=minReadingTime,proto3" json:"min_reading_time,omitempty"`  // Minutes, 0 means no lower bound
+	MaxReadingTime     int32                  `protobuf:"varint,10,opt,name=max_reading_time,json=maxReadingTime,proto3" json:"max_reading_time,omitempty"` // Minutes, 0 means no upper bound
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
 }
 
 func (x *GetAllRequest) Reset() {
 	*x = GetAllRequest{}
-	mi := &file_news_proto_msgTypes[2]
+	mi := &file_news_proto_msgTypes[4]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -242,7 +462,7 @@ func (x *GetAllRequest) String() string {
 func (*GetAllRequest) ProtoMessage() {}
 
 func (x *GetAllRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_news_proto_msgTypes[2]
+	mi := &file_news_proto_msgTypes[4]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -255,7 +475,7 @@ func (x *GetAllRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetAllRequest.ProtoReflect.Descriptor instead.
 func (*GetAllRequest) Descriptor() ([]byte, []int) {
-	return file_news_proto_rawDescGZIP(), []int{2}
+	return file_news_proto_rawDescGZIP(), []int{4}
 }
 
 func (x *GetAllRequest) GetTitle() string {
@@ -300,6 +520,34 @@ func (x *GetAllRequest) GetSort() string {
 	return ""
 }
 
+func (x *GetAllRequest) GetAuthor() string {
+	if x != nil {
+		return x.Author
+	}
+	return ""
+}
+
+func (x *GetAllRequest) GetIncludeContentHtml() bool {
+	if x != nil {
+		return x.IncludeContentHtml
+	}
+	return false
+}
+
+func (x *GetAllRequest) GetMinReadingTime() int32 {
+	if x != nil {
+		return x.MinReadingTime
+	}
+	return 0
+}
+
+func (x *GetAllRequest) GetMaxReadingTime() int32 {
+	if x != nil {
+		return x.MaxReadingTime
+	}
+	return 0
+}
+
 type NewsList struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	News          []*News                `protobuf:"bytes,1,rep,name=news,proto3" json:"news,omitempty"`
@@ -310,7 +558,7 @@ type NewsList struct {
 
 func (x *NewsList) Reset() {
 	*x = NewsList{}
-	mi := &file_news_proto_msgTypes[3]
+	mi := &file_news_proto_msgTypes[5]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -322,7 +570,7 @@ func (x *NewsList) String() string {
 func (*NewsList) ProtoMessage() {}
 
 func (x *NewsList) ProtoReflect() protoreflect.Message {
-	mi := &file_news_proto_msgTypes[3]
+	mi := &file_news_proto_msgTypes[5]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -335,7 +583,7 @@ func (x *NewsList) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use NewsList.ProtoReflect.Descriptor instead.
 func (*NewsList) Descriptor() ([]byte, []int) {
-	return file_news_proto_rawDescGZIP(), []int{3}
+	return file_news_proto_rawDescGZIP(), []int{5}
 }
 
 func (x *NewsList) GetNews() []*News {
@@ -353,15 +601,16 @@ func (x *NewsList) GetMetadata() *Metadata {
 }
 
 type NewsId struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	state              protoimpl.MessageState `protogen:"open.v1"`
+	Id                 int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	IncludeContentHtml bool                   `protobuf:"varint,2,opt,name=include_content_html,json=includeContentHtml,proto3" json:"include_content_html,omitempty"`
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
 }
 
 func (x *NewsId) Reset() {
 	*x = NewsId{}
-	mi := &file_news_proto_msgTypes[4]
+	mi := &file_news_proto_msgTypes[6]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -373,7 +622,7 @@ func (x *NewsId) String() string {
 func (*NewsId) ProtoMessage() {}
 
 func (x *NewsId) ProtoReflect() protoreflect.Message {
-	mi := &file_news_proto_msgTypes[4]
+	mi := &file_news_proto_msgTypes[6]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -386,7 +635,7 @@ func (x *NewsId) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use NewsId.ProtoReflect.Descriptor instead.
 func (*NewsId) Descriptor() ([]byte, []int) {
-	return file_news_proto_rawDescGZIP(), []int{4}
+	return file_news_proto_rawDescGZIP(), []int{6}
 }
 
 func (x *NewsId) GetId() int64 {
@@ -396,6 +645,13 @@ func (x *NewsId) GetId() int64 {
 	return 0
 }
 
+func (x *NewsId) GetIncludeContentHtml() bool {
+	if x != nil {
+		return x.IncludeContentHtml
+	}
+	return false
+}
+
 type CreateNewsRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Title         string                 `protobuf:"bytes,1,opt,name=title,proto3" json:"title,omitempty"`
@@ -404,13 +660,15 @@ type CreateNewsRequest struct {
 	Status        string                 `protobuf:"bytes,4,opt,name=status,proto3" json:"status,omitempty"`
 	ImageUrls     []string               `protobuf:"bytes,5,rep,name=image_urls,json=imageUrls,proto3" json:"image_urls,omitempty"` // Optional field
 	Author        string                 `protobuf:"bytes,6,opt,name=author,proto3" json:"author,omitempty"`
+	ContentFormat string                 `protobuf:"bytes,7,opt,name=content_format,json=contentFormat,proto3" json:"content_format,omitempty"` // plain (default), markdown or html
+	Media         []*Media               `protobuf:"bytes,8,rep,name=media,proto3" json:"media,omitempty"`                                      // Takes precedence over image_urls
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *CreateNewsRequest) Reset() {
 	*x = CreateNewsRequest{}
-	mi := &file_news_proto_msgTypes[5]
+	mi := &file_news_proto_msgTypes[7]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -422,7 +680,7 @@ func (x *CreateNewsRequest) String() string {
 func (*CreateNewsRequest) ProtoMessage() {}
 
 func (x *CreateNewsRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_news_proto_msgTypes[5]
+	mi := &file_news_proto_msgTypes[7]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -435,7 +693,7 @@ func (x *CreateNewsRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use CreateNewsRequest.ProtoReflect.Descriptor instead.
 func (*CreateNewsRequest) Descriptor() ([]byte, []int) {
-	return file_news_proto_rawDescGZIP(), []int{5}
+	return file_news_proto_rawDescGZIP(), []int{7}
 }
 
 func (x *CreateNewsRequest) GetTitle() string {
@@ -480,6 +738,20 @@ func (x *CreateNewsRequest) GetAuthor() string {
 	return ""
 }
 
+func (x *CreateNewsRequest) GetContentFormat() string {
+	if x != nil {
+		return x.ContentFormat
+	}
+	return ""
+}
+
+func (x *CreateNewsRequest) GetMedia() []*Media {
+	if x != nil {
+		return x.Media
+	}
+	return nil
+}
+
 type UpdateNewsRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
@@ -490,13 +762,18 @@ type UpdateNewsRequest struct {
 	ImageUrls     []string               `protobuf:"bytes,6,rep,name=image_urls,json=imageUrls,proto3" json:"image_urls,omitempty"`
 	Author        *string                `protobuf:"bytes,7,opt,name=author,proto3,oneof" json:"author,omitempty"`
 	Version       *int32                 `protobuf:"varint,8,opt,name=version,proto3,oneof" json:"version,omitempty"`
+	ContentFormat *string                `protobuf:"bytes,9,opt,name=content_format,json=contentFormat,proto3,oneof" json:"content_format,omitempty"`
+	Media         []*Media               `protobuf:"bytes,10,rep,name=media,proto3" json:"media,omitempty"` // Takes precedence over image_urls
+	// When set, only the listed fields are changed. A listed repeated field that
+	// is empty clears the stored value.
+	UpdateMask    *fieldmaskpb.FieldMask `protobuf:"bytes,11,opt,name=update_mask,json=updateMask,proto3" json:"update_mask,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *UpdateNewsRequest) Reset() {
 	*x = UpdateNewsRequest{}
-	mi := &file_news_proto_msgTypes[6]
+	mi := &file_news_proto_msgTypes[8]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -508,7 +785,7 @@ func (x *UpdateNewsRequest) String() string {
 func (*UpdateNewsRequest) ProtoMessage() {}
 
 func (x *UpdateNewsRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_news_proto_msgTypes[6]
+	mi := &file_news_proto_msgTypes[8]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -521,7 +798,7 @@ func (x *UpdateNewsRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use UpdateNewsRequest.ProtoReflect.Descriptor instead.
 func (*UpdateNewsRequest) Descriptor() ([]byte, []int) {
-	return file_news_proto_rawDescGZIP(), []int{6}
+	return file_news_proto_rawDescGZIP(), []int{8}
 }
 
 func (x *UpdateNewsRequest) GetId() int64 {
@@ -580,51 +857,1772 @@ func (x *UpdateNewsRequest) GetVersion() int32 {
 	return 0
 }
 
-var File_news_proto protoreflect.FileDescriptor
+func (x *UpdateNewsRequest) GetContentFormat() string {
+	if x != nil && x.ContentFormat != nil {
+		return *x.ContentFormat
+	}
+	return ""
+}
 
-const file_news_proto_rawDesc = "" +
-	"\n" +
-	"\n" +
-	"news.proto\x12\x04data\x1a\x1fgoogle/protobuf/timestamp.proto\x1a\x1bgoogle/protobuf/empty.proto\"\xc5\x02\n" +
-	"\x04News\x12\x0e\n" +
-	"\x02id\x18\x01 \x01(\x03R\x02id\x129\n" +
-	"\n" +
-	"created_at\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
-	"\n" +
-	"updated_at\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\x12\x14\n" +
-	"\x05title\x18\x04 \x01(\tR\x05title\x12\x18\n" +
-	"\acontent\x18\x05 \x01(\tR\acontent\x12\x1e\n" +
-	"\n" +
-	"categories\x18\x06 \x03(\tR\n" +
-	"categories\x12\x16\n" +
-	"\x06status\x18\a \x01(\tR\x06status\x12\x1d\n" +
-	"\n" +
-	"image_urls\x18\b \x03(\tR\timageUrls\x12\x16\n" +
-	"\x06author\x18\t \x01(\tR\x06author\x12\x18\n" +
-	"\aversion\x18\n" +
-	" \x01(\x05R\aversion\"\xab\x01\n" +
-	"\bMetadata\x12!\n" +
-	"\fcurrent_page\x18\x01 \x01(\x05R\vcurrentPage\x12\x1b\n" +
-	"\tpage_size\x18\x02 \x01(\x05R\bpageSize\x12\x1d\n" +
-	"\n" +
-	"first_page\x18\x03 \x01(\x05R\tfirstPage\x12\x1b\n" +
-	"\tlast_page\x18\x04 \x01(\x05R\blastPage\x12#\n" +
-	"\rtotal_records\x18\x05 \x01(\x05R\ftotalRecords\"\xa2\x01\n" +
-	"\rGetAllRequest\x12\x14\n" +
-	"\x05title\x18\x01 \x01(\tR\x05title\x12\x1e\n" +
-	"\n" +
+func (x *UpdateNewsRequest) GetMedia() []*Media {
+	if x != nil {
+		return x.Media
+	}
+	return nil
+}
+
+func (x *UpdateNewsRequest) GetUpdateMask() *fieldmaskpb.FieldMask {
+	if x != nil {
+		return x.UpdateMask
+	}
+	return nil
+}
+
+type BatchCreateNewsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Items         []*CreateNewsRequest   `protobuf:"bytes,1,rep,name=items,proto3" json:"items,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BatchCreateNewsRequest) Reset() {
+	*x = BatchCreateNewsRequest{}
+	mi := &file_news_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchCreateNewsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchCreateNewsRequest) ProtoMessage() {}
+
+func (x *BatchCreateNewsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_news_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchCreateNewsRequest.ProtoReflect.Descriptor instead.
+func (*BatchCreateNewsRequest) Descriptor() ([]byte, []int) {
+	return file_news_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *BatchCreateNewsRequest) GetItems() []*CreateNewsRequest {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+type BatchCreateNewsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	News          []*News                `protobuf:"bytes,1,rep,name=news,proto3" json:"news,omitempty"` // In the order of the request items
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BatchCreateNewsResponse) Reset() {
+	*x = BatchCreateNewsResponse{}
+	mi := &file_news_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchCreateNewsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchCreateNewsResponse) ProtoMessage() {}
+
+func (x *BatchCreateNewsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_news_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchCreateNewsResponse.ProtoReflect.Descriptor instead.
+func (*BatchCreateNewsResponse) Descriptor() ([]byte, []int) {
+	return file_news_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *BatchCreateNewsResponse) GetNews() []*News {
+	if x != nil {
+		return x.News
+	}
+	return nil
+}
+
+type BatchGetNewsRequest struct {
+	state              protoimpl.MessageState `protogen:"open.v1"`
+	Ids                []int64                `protobuf:"varint,1,rep,packed,name=ids,proto3" json:"ids,omitempty"`
+	IncludeContentHtml bool                   `protobuf:"varint,2,opt,name=include_content_html,json=includeContentHtml,proto3" json:"include_content_html,omitempty"`
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
+}
+
+func (x *BatchGetNewsRequest) Reset() {
+	*x = BatchGetNewsRequest{}
+	mi := &file_news_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchGetNewsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchGetNewsRequest) ProtoMessage() {}
+
+func (x *BatchGetNewsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_news_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchGetNewsRequest.ProtoReflect.Descriptor instead.
+func (*BatchGetNewsRequest) Descriptor() ([]byte, []int) {
+	return file_news_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *BatchGetNewsRequest) GetIds() []int64 {
+	if x != nil {
+		return x.Ids
+	}
+	return nil
+}
+
+func (x *BatchGetNewsRequest) GetIncludeContentHtml() bool {
+	if x != nil {
+		return x.IncludeContentHtml
+	}
+	return false
+}
+
+type BatchGetNewsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	News          []*News                `protobuf:"bytes,1,rep,name=news,proto3" json:"news,omitempty"` // In the requested order, missing ids are skipped
+	MissingIds    []int64                `protobuf:"varint,2,rep,packed,name=missing_ids,json=missingIds,proto3" json:"missing_ids,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BatchGetNewsResponse) Reset() {
+	*x = BatchGetNewsResponse{}
+	mi := &file_news_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BatchGetNewsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BatchGetNewsResponse) ProtoMessage() {}
+
+func (x *BatchGetNewsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_news_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BatchGetNewsResponse.ProtoReflect.Descriptor instead.
+func (*BatchGetNewsResponse) Descriptor() ([]byte, []int) {
+	return file_news_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *BatchGetNewsResponse) GetNews() []*News {
+	if x != nil {
+		return x.News
+	}
+	return nil
+}
+
+func (x *BatchGetNewsResponse) GetMissingIds() []int64 {
+	if x != nil {
+		return x.MissingIds
+	}
+	return nil
+}
+
+type NewsFilter struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Title          string                 `protobuf:"bytes,1,opt,name=title,proto3" json:"title,omitempty"`
+	Categories     []string               `protobuf:"bytes,2,rep,name=categories,proto3" json:"categories,omitempty"`
+	Status         string                 `protobuf:"bytes,3,opt,name=status,proto3" json:"status,omitempty"`
+	Author         string                 `protobuf:"bytes,4,opt,name=author,proto3" json:"author,omitempty"`
+	MinReadingTime int32                  `protobuf:"varint,5,opt,name=min_reading_time,json=minReadingTime,proto3" json:"min_reading_time,omitempty"`
+	MaxReadingTime int32                  `protobuf:"varint,6,opt,name=max_reading_time,json=maxReadingTime,proto3" json:"max_reading_time,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *NewsFilter) Reset() {
+	*x = NewsFilter{}
+	mi := &file_news_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NewsFilter) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NewsFilter) ProtoMessage() {}
+
+func (x *NewsFilter) ProtoReflect() protoreflect.Message {
+	mi := &file_news_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NewsFilter.ProtoReflect.Descriptor instead.
+func (*NewsFilter) Descriptor() ([]byte, []int) {
+	return file_news_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *NewsFilter) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *NewsFilter) GetCategories() []string {
+	if x != nil {
+		return x.Categories
+	}
+	return nil
+}
+
+func (x *NewsFilter) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *NewsFilter) GetAuthor() string {
+	if x != nil {
+		return x.Author
+	}
+	return ""
+}
+
+func (x *NewsFilter) GetMinReadingTime() int32 {
+	if x != nil {
+		return x.MinReadingTime
+	}
+	return 0
+}
+
+func (x *NewsFilter) GetMaxReadingTime() int32 {
+	if x != nil {
+		return x.MaxReadingTime
+	}
+	return 0
+}
+
+type BulkUpdateNewsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Action        string                 `protobuf:"bytes,1,opt,name=action,proto3" json:"action,omitempty"` // set_status, add_category, remove_category or replace_category
+	Ids           []int64                `protobuf:"varint,2,rep,packed,name=ids,proto3" json:"ids,omitempty"`
+	Filter        *NewsFilter            `protobuf:"bytes,3,opt,name=filter,proto3" json:"filter,omitempty"`
+	Status        string                 `protobuf:"bytes,4,opt,name=status,proto3" json:"status,omitempty"`                              // New status for set_status
+	Category      string                 `protobuf:"bytes,5,opt,name=category,proto3" json:"category,omitempty"`                          // Category to add, remove or replace
+	NewCategory   string                 `protobuf:"bytes,6,opt,name=new_category,json=newCategory,proto3" json:"new_category,omitempty"` // Replacement for replace_category
+	DryRun        bool                   `protobuf:"varint,7,opt,name=dry_run,json=dryRun,proto3" json:"dry_run,omitempty"`               // Only count affected articles
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BulkUpdateNewsRequest) Reset() {
+	*x = BulkUpdateNewsRequest{}
+	mi := &file_news_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BulkUpdateNewsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BulkUpdateNewsRequest) ProtoMessage() {}
+
+func (x *BulkUpdateNewsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_news_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BulkUpdateNewsRequest.ProtoReflect.Descriptor instead.
+func (*BulkUpdateNewsRequest) Descriptor() ([]byte, []int) {
+	return file_news_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *BulkUpdateNewsRequest) GetAction() string {
+	if x != nil {
+		return x.Action
+	}
+	return ""
+}
+
+func (x *BulkUpdateNewsRequest) GetIds() []int64 {
+	if x != nil {
+		return x.Ids
+	}
+	return nil
+}
+
+func (x *BulkUpdateNewsRequest) GetFilter() *NewsFilter {
+	if x != nil {
+		return x.Filter
+	}
+	return nil
+}
+
+func (x *BulkUpdateNewsRequest) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *BulkUpdateNewsRequest) GetCategory() string {
+	if x != nil {
+		return x.Category
+	}
+	return ""
+}
+
+func (x *BulkUpdateNewsRequest) GetNewCategory() string {
+	if x != nil {
+		return x.NewCategory
+	}
+	return ""
+}
+
+func (x *BulkUpdateNewsRequest) GetDryRun() bool {
+	if x != nil {
+		return x.DryRun
+	}
+	return false
+}
+
+type BulkJob struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt     *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	Action        string                 `protobuf:"bytes,4,opt,name=action,proto3" json:"action,omitempty"`
+	Status        string                 `protobuf:"bytes,5,opt,name=status,proto3" json:"status,omitempty"` // pending, running, completed or failed
+	Matched       int64                  `protobuf:"varint,6,opt,name=matched,proto3" json:"matched,omitempty"`
+	Processed     int64                  `protobuf:"varint,7,opt,name=processed,proto3" json:"processed,omitempty"`
+	Affected      int64                  `protobuf:"varint,8,opt,name=affected,proto3" json:"affected,omitempty"`
+	Error         string                 `protobuf:"bytes,9,opt,name=error,proto3" json:"error,omitempty"`
+	FinishedAt    *timestamppb.Timestamp `protobuf:"bytes,10,opt,name=finished_at,json=finishedAt,proto3" json:"finished_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BulkJob) Reset() {
+	*x = BulkJob{}
+	mi := &file_news_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BulkJob) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BulkJob) ProtoMessage() {}
+
+func (x *BulkJob) ProtoReflect() protoreflect.Message {
+	mi := &file_news_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BulkJob.ProtoReflect.Descriptor instead.
+func (*BulkJob) Descriptor() ([]byte, []int) {
+	return file_news_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *BulkJob) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *BulkJob) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *BulkJob) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+func (x *BulkJob) GetAction() string {
+	if x != nil {
+		return x.Action
+	}
+	return ""
+}
+
+func (x *BulkJob) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *BulkJob) GetMatched() int64 {
+	if x != nil {
+		return x.Matched
+	}
+	return 0
+}
+
+func (x *BulkJob) GetProcessed() int64 {
+	if x != nil {
+		return x.Processed
+	}
+	return 0
+}
+
+func (x *BulkJob) GetAffected() int64 {
+	if x != nil {
+		return x.Affected
+	}
+	return 0
+}
+
+func (x *BulkJob) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *BulkJob) GetFinishedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.FinishedAt
+	}
+	return nil
+}
+
+type BulkUpdateNewsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Matched       int64                  `protobuf:"varint,1,opt,name=matched,proto3" json:"matched,omitempty"`
+	Affected      int64                  `protobuf:"varint,2,opt,name=affected,proto3" json:"affected,omitempty"`
+	Job           *BulkJob               `protobuf:"bytes,3,opt,name=job,proto3" json:"job,omitempty"` // Not set for dry runs
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BulkUpdateNewsResponse) Reset() {
+	*x = BulkUpdateNewsResponse{}
+	mi := &file_news_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BulkUpdateNewsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BulkUpdateNewsResponse) ProtoMessage() {}
+
+func (x *BulkUpdateNewsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_news_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BulkUpdateNewsResponse.ProtoReflect.Descriptor instead.
+func (*BulkUpdateNewsResponse) Descriptor() ([]byte, []int) {
+	return file_news_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *BulkUpdateNewsResponse) GetMatched() int64 {
+	if x != nil {
+		return x.Matched
+	}
+	return 0
+}
+
+func (x *BulkUpdateNewsResponse) GetAffected() int64 {
+	if x != nil {
+		return x.Affected
+	}
+	return 0
+}
+
+func (x *BulkUpdateNewsResponse) GetJob() *BulkJob {
+	if x != nil {
+		return x.Job
+	}
+	return nil
+}
+
+type BulkJobId struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BulkJobId) Reset() {
+	*x = BulkJobId{}
+	mi := &file_news_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BulkJobId) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BulkJobId) ProtoMessage() {}
+
+func (x *BulkJobId) ProtoReflect() protoreflect.Message {
+	mi := &file_news_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BulkJobId.ProtoReflect.Descriptor instead.
+func (*BulkJobId) Descriptor() ([]byte, []int) {
+	return file_news_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *BulkJobId) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+type WatchNewsRequest struct {
+	state              protoimpl.MessageState `protogen:"open.v1"`
+	Title              string                 `protobuf:"bytes,1,opt,name=title,proto3" json:"title,omitempty"`
+	Categories         []string               `protobuf:"bytes,2,rep,name=categories,proto3" json:"categories,omitempty"`
+	Status             string                 `protobuf:"bytes,3,opt,name=status,proto3" json:"status,omitempty"`
+	Author             string                 `protobuf:"bytes,4,opt,name=author,proto3" json:"author,omitempty"`
+	IncludeContentHtml bool                   `protobuf:"varint,5,opt,name=include_content_html,json=includeContentHtml,proto3" json:"include_content_html,omitempty"`
+	MinReadingTime     int32                  `protobuf:"varint,6,opt,name=min_reading_time,json=minReadingTime,proto3" json:"min_reading_time,omitempty"`
+	MaxReadingTime     int32                  `protobuf:"varint,7,opt,name=max_reading_time,json=maxReadingTime,proto3" json:"max_reading_time,omitempty"`
+	LastEventId        int64                  `protobuf:"varint,8,opt,name=last_event_id,json=lastEventId,proto3" json:"last_event_id,omitempty"` // Resume after this event, 0 streams only new events
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
+}
+
+func (x *WatchNewsRequest) Reset() {
+	*x = WatchNewsRequest{}
+	mi := &file_news_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchNewsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchNewsRequest) ProtoMessage() {}
+
+func (x *WatchNewsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_news_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchNewsRequest.ProtoReflect.Descriptor instead.
+func (*WatchNewsRequest) Descriptor() ([]byte, []int) {
+	return file_news_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *WatchNewsRequest) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *WatchNewsRequest) GetCategories() []string {
+	if x != nil {
+		return x.Categories
+	}
+	return nil
+}
+
+func (x *WatchNewsRequest) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *WatchNewsRequest) GetAuthor() string {
+	if x != nil {
+		return x.Author
+	}
+	return ""
+}
+
+func (x *WatchNewsRequest) GetIncludeContentHtml() bool {
+	if x != nil {
+		return x.IncludeContentHtml
+	}
+	return false
+}
+
+func (x *WatchNewsRequest) GetMinReadingTime() int32 {
+	if x != nil {
+		return x.MinReadingTime
+	}
+	return 0
+}
+
+func (x *WatchNewsRequest) GetMaxReadingTime() int32 {
+	if x != nil {
+		return x.MaxReadingTime
+	}
+	return 0
+}
+
+func (x *WatchNewsRequest) GetLastEventId() int64 {
+	if x != nil {
+		return x.LastEventId
+	}
+	return 0
+}
+
+type NewsEvent struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Type          string                 `protobuf:"bytes,2,opt,name=type,proto3" json:"type,omitempty"` // created, updated, deleted or published
+	News          *News                  `protobuf:"bytes,3,opt,name=news,proto3" json:"news,omitempty"`
+	OccurredAt    *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=occurred_at,json=occurredAt,proto3" json:"occurred_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *NewsEvent) Reset() {
+	*x = NewsEvent{}
+	mi := &file_news_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *NewsEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*NewsEvent) ProtoMessage() {}
+
+func (x *NewsEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_news_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use NewsEvent.ProtoReflect.Descriptor instead.
+func (*NewsEvent) Descriptor() ([]byte, []int) {
+	return file_news_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *NewsEvent) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *NewsEvent) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *NewsEvent) GetNews() *News {
+	if x != nil {
+		return x.News
+	}
+	return nil
+}
+
+func (x *NewsEvent) GetOccurredAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.OccurredAt
+	}
+	return nil
+}
+
+type UploadMediaInfo struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Filename      string                 `protobuf:"bytes,1,opt,name=filename,proto3" json:"filename,omitempty"`
+	ContentType   string                 `protobuf:"bytes,2,opt,name=content_type,json=contentType,proto3" json:"content_type,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UploadMediaInfo) Reset() {
+	*x = UploadMediaInfo{}
+	mi := &file_news_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UploadMediaInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UploadMediaInfo) ProtoMessage() {}
+
+func (x *UploadMediaInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_news_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UploadMediaInfo.ProtoReflect.Descriptor instead.
+func (*UploadMediaInfo) Descriptor() ([]byte, []int) {
+	return file_news_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *UploadMediaInfo) GetFilename() string {
+	if x != nil {
+		return x.Filename
+	}
+	return ""
+}
+
+func (x *UploadMediaInfo) GetContentType() string {
+	if x != nil {
+		return x.ContentType
+	}
+	return ""
+}
+
+// The first message of the stream must carry info, all following messages carry file chunks.
+type UploadMediaRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Data:
+	//
+	//	*UploadMediaRequest_Info
+	//	*UploadMediaRequest_Chunk
+	Data          isUploadMediaRequest_Data `protobuf_oneof:"data"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UploadMediaRequest) Reset() {
+	*x = UploadMediaRequest{}
+	mi := &file_news_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UploadMediaRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UploadMediaRequest) ProtoMessage() {}
+
+func (x *UploadMediaRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_news_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UploadMediaRequest.ProtoReflect.Descriptor instead.
+func (*UploadMediaRequest) Descriptor() ([]byte, []int) {
+	return file_news_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *UploadMediaRequest) GetData() isUploadMediaRequest_Data {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+func (x *UploadMediaRequest) GetInfo() *UploadMediaInfo {
+	if x != nil {
+		if x, ok := x.Data.(*UploadMediaRequest_Info); ok {
+			return x.Info
+		}
+	}
+	return nil
+}
+
+func (x *UploadMediaRequest) GetChunk() []byte {
+	if x != nil {
+		if x, ok := x.Data.(*UploadMediaRequest_Chunk); ok {
+			return x.Chunk
+		}
+	}
+	return nil
+}
+
+type isUploadMediaRequest_Data interface {
+	isUploadMediaRequest_Data()
+}
+
+type UploadMediaRequest_Info struct {
+	Info *UploadMediaInfo `protobuf:"bytes,1,opt,name=info,proto3,oneof"`
+}
+
+type UploadMediaRequest_Chunk struct {
+	Chunk []byte `protobuf:"bytes,2,opt,name=chunk,proto3,oneof"`
+}
+
+func (*UploadMediaRequest_Info) isUploadMediaRequest_Data() {}
+
+func (*UploadMediaRequest_Chunk) isUploadMediaRequest_Data() {}
+
+type UploadMediaResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Url           string                 `protobuf:"bytes,2,opt,name=url,proto3" json:"url,omitempty"` // Can be used in CreateNewsRequest.image_urls and Media.url
+	ContentType   string                 `protobuf:"bytes,3,opt,name=content_type,json=contentType,proto3" json:"content_type,omitempty"`
+	Size          int64                  `protobuf:"varint,4,opt,name=size,proto3" json:"size,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UploadMediaResponse) Reset() {
+	*x = UploadMediaResponse{}
+	mi := &file_news_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UploadMediaResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UploadMediaResponse) ProtoMessage() {}
+
+func (x *UploadMediaResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_news_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UploadMediaResponse.ProtoReflect.Descriptor instead.
+func (*UploadMediaResponse) Descriptor() ([]byte, []int) {
+	return file_news_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *UploadMediaResponse) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *UploadMediaResponse) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+func (x *UploadMediaResponse) GetContentType() string {
+	if x != nil {
+		return x.ContentType
+	}
+	return ""
+}
+
+func (x *UploadMediaResponse) GetSize() int64 {
+	if x != nil {
+		return x.Size
+	}
+	return 0
+}
+
+type Webhook struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt     *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	Url           string                 `protobuf:"bytes,4,opt,name=url,proto3" json:"url,omitempty"`
+	Secret        string                 `protobuf:"bytes,5,opt,name=secret,proto3" json:"secret,omitempty"`                           // Returned only by CreateWebhook
+	EventTypes    []string               `protobuf:"bytes,6,rep,name=event_types,json=eventTypes,proto3" json:"event_types,omitempty"` // created, updated, deleted, published
+	Active        bool                   `protobuf:"varint,7,opt,name=active,proto3" json:"active,omitempty"`
+	Version       int32                  `protobuf:"varint,8,opt,name=version,proto3" json:"version,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Webhook) Reset() {
+	*x = Webhook{}
+	mi := &file_news_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Webhook) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Webhook) ProtoMessage() {}
+
+func (x *Webhook) ProtoReflect() protoreflect.Message {
+	mi := &file_news_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Webhook.ProtoReflect.Descriptor instead.
+func (*Webhook) Descriptor() ([]byte, []int) {
+	return file_news_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *Webhook) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *Webhook) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *Webhook) GetUpdatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return nil
+}
+
+func (x *Webhook) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+func (x *Webhook) GetSecret() string {
+	if x != nil {
+		return x.Secret
+	}
+	return ""
+}
+
+func (x *Webhook) GetEventTypes() []string {
+	if x != nil {
+		return x.EventTypes
+	}
+	return nil
+}
+
+func (x *Webhook) GetActive() bool {
+	if x != nil {
+		return x.Active
+	}
+	return false
+}
+
+func (x *Webhook) GetVersion() int32 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+type CreateWebhookRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Url           string                 `protobuf:"bytes,1,opt,name=url,proto3" json:"url,omitempty"`
+	Secret        string                 `protobuf:"bytes,2,opt,name=secret,proto3" json:"secret,omitempty"` // Generated when empty
+	EventTypes    []string               `protobuf:"bytes,3,rep,name=event_types,json=eventTypes,proto3" json:"event_types,omitempty"`
+	Active        *bool                  `protobuf:"varint,4,opt,name=active,proto3,oneof" json:"active,omitempty"` // Defaults to true
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateWebhookRequest) Reset() {
+	*x = CreateWebhookRequest{}
+	mi := &file_news_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateWebhookRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateWebhookRequest) ProtoMessage() {}
+
+func (x *CreateWebhookRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_news_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateWebhookRequest.ProtoReflect.Descriptor instead.
+func (*CreateWebhookRequest) Descriptor() ([]byte, []int) {
+	return file_news_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *CreateWebhookRequest) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+func (x *CreateWebhookRequest) GetSecret() string {
+	if x != nil {
+		return x.Secret
+	}
+	return ""
+}
+
+func (x *CreateWebhookRequest) GetEventTypes() []string {
+	if x != nil {
+		return x.EventTypes
+	}
+	return nil
+}
+
+func (x *CreateWebhookRequest) GetActive() bool {
+	if x != nil && x.Active != nil {
+		return *x.Active
+	}
+	return false
+}
+
+type UpdateWebhookRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Url           *string                `protobuf:"bytes,2,opt,name=url,proto3,oneof" json:"url,omitempty"`
+	Secret        *string                `protobuf:"bytes,3,opt,name=secret,proto3,oneof" json:"secret,omitempty"`
+	EventTypes    []string               `protobuf:"bytes,4,rep,name=event_types,json=eventTypes,proto3" json:"event_types,omitempty"`
+	Active        *bool                  `protobuf:"varint,5,opt,name=active,proto3,oneof" json:"active,omitempty"`
+	Version       *int32                 `protobuf:"varint,6,opt,name=version,proto3,oneof" json:"version,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateWebhookRequest) Reset() {
+	*x = UpdateWebhookRequest{}
+	mi := &file_news_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateWebhookRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateWebhookRequest) ProtoMessage() {}
+
+func (x *UpdateWebhookRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_news_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateWebhookRequest.ProtoReflect.Descriptor instead.
+func (*UpdateWebhookRequest) Descriptor() ([]byte, []int) {
+	return file_news_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *UpdateWebhookRequest) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *UpdateWebhookRequest) GetUrl() string {
+	if x != nil && x.Url != nil {
+		return *x.Url
+	}
+	return ""
+}
+
+func (x *UpdateWebhookRequest) GetSecret() string {
+	if x != nil && x.Secret != nil {
+		return *x.Secret
+	}
+	return ""
+}
+
+func (x *UpdateWebhookRequest) GetEventTypes() []string {
+	if x != nil {
+		return x.EventTypes
+	}
+	return nil
+}
+
+func (x *UpdateWebhookRequest) GetActive() bool {
+	if x != nil && x.Active != nil {
+		return *x.Active
+	}
+	return false
+}
+
+func (x *UpdateWebhookRequest) GetVersion() int32 {
+	if x != nil && x.Version != nil {
+		return *x.Version
+	}
+	return 0
+}
+
+type WebhookId struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WebhookId) Reset() {
+	*x = WebhookId{}
+	mi := &file_news_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WebhookId) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WebhookId) ProtoMessage() {}
+
+func (x *WebhookId) ProtoReflect() protoreflect.Message {
+	mi := &file_news_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WebhookId.ProtoReflect.Descriptor instead.
+func (*WebhookId) Descriptor() ([]byte, []int) {
+	return file_news_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *WebhookId) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+type WebhookList struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Webhooks      []*Webhook             `protobuf:"bytes,1,rep,name=webhooks,proto3" json:"webhooks,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WebhookList) Reset() {
+	*x = WebhookList{}
+	mi := &file_news_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WebhookList) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WebhookList) ProtoMessage() {}
+
+func (x *WebhookList) ProtoReflect() protoreflect.Message {
+	mi := &file_news_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WebhookList.ProtoReflect.Descriptor instead.
+func (*WebhookList) Descriptor() ([]byte, []int) {
+	return file_news_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *WebhookList) GetWebhooks() []*Webhook {
+	if x != nil {
+		return x.Webhooks
+	}
+	return nil
+}
+
+type WebhookAttempt struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	AttemptedAt    *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=attempted_at,json=attemptedAt,proto3" json:"attempted_at,omitempty"`
+	ResponseStatus int32                  `protobuf:"varint,2,opt,name=response_status,json=responseStatus,proto3" json:"response_status,omitempty"`
+	Error          string                 `protobuf:"bytes,3,opt,name=error,proto3" json:"error,omitempty"`
+	DurationMs     int32                  `protobuf:"varint,4,opt,name=duration_ms,json=durationMs,proto3" json:"duration_ms,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *WebhookAttempt) Reset() {
+	*x = WebhookAttempt{}
+	mi := &file_news_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WebhookAttempt) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WebhookAttempt) ProtoMessage() {}
+
+func (x *WebhookAttempt) ProtoReflect() protoreflect.Message {
+	mi := &file_news_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WebhookAttempt.ProtoReflect.Descriptor instead.
+func (*WebhookAttempt) Descriptor() ([]byte, []int) {
+	return file_news_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *WebhookAttempt) GetAttemptedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.AttemptedAt
+	}
+	return nil
+}
+
+func (x *WebhookAttempt) GetResponseStatus() int32 {
+	if x != nil {
+		return x.ResponseStatus
+	}
+	return 0
+}
+
+func (x *WebhookAttempt) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *WebhookAttempt) GetDurationMs() int32 {
+	if x != nil {
+		return x.DurationMs
+	}
+	return 0
+}
+
+type WebhookDelivery struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            int64                  `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	WebhookId     int64                  `protobuf:"varint,2,opt,name=webhook_id,json=webhookId,proto3" json:"webhook_id,omitempty"`
+	EventType     string                 `protobuf:"bytes,3,opt,name=event_type,json=eventType,proto3" json:"event_type,omitempty"`
+	Payload       string                 `protobuf:"bytes,4,opt,name=payload,proto3" json:"payload,omitempty"` // JSON body sent to the receiver
+	Status        string                 `protobuf:"bytes,5,opt,name=status,proto3" json:"status,omitempty"`   // pending, delivered or dead
+	Attempts      int32                  `protobuf:"varint,6,opt,name=attempts,proto3" json:"attempts,omitempty"`
+	NextAttemptAt *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=next_attempt_at,json=nextAttemptAt,proto3" json:"next_attempt_at,omitempty"`
+	LastError     string                 `protobuf:"bytes,8,opt,name=last_error,json=lastError,proto3" json:"last_error,omitempty"`
+	CreatedAt     *timestamppb.Timestamp `protobuf:"bytes,9,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	DeliveredAt   *timestamppb.Timestamp `protobuf:"bytes,10,opt,name=delivered_at,json=deliveredAt,proto3" json:"delivered_at,omitempty"`
+	History       []*WebhookAttempt      `protobuf:"bytes,11,rep,name=history,proto3" json:"history,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WebhookDelivery) Reset() {
+	*x = WebhookDelivery{}
+	mi := &file_news_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WebhookDelivery) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WebhookDelivery) ProtoMessage() {}
+
+func (x *WebhookDelivery) ProtoReflect() protoreflect.Message {
+	mi := &file_news_proto_msgTypes[29]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WebhookDelivery.ProtoReflect.Descriptor instead.
+func (*WebhookDelivery) Descriptor() ([]byte, []int) {
+	return file_news_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *WebhookDelivery) GetId() int64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *WebhookDelivery) GetWebhookId() int64 {
+	if x != nil {
+		return x.WebhookId
+	}
+	return 0
+}
+
+func (x *WebhookDelivery) GetEventType() string {
+	if x != nil {
+		return x.EventType
+	}
+	return ""
+}
+
+func (x *WebhookDelivery) GetPayload() string {
+	if x != nil {
+		return x.Payload
+	}
+	return ""
+}
+
+func (x *WebhookDelivery) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *WebhookDelivery) GetAttempts() int32 {
+	if x != nil {
+		return x.Attempts
+	}
+	return 0
+}
+
+func (x *WebhookDelivery) GetNextAttemptAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.NextAttemptAt
+	}
+	return nil
+}
+
+func (x *WebhookDelivery) GetLastError() string {
+	if x != nil {
+		return x.LastError
+	}
+	return ""
+}
+
+func (x *WebhookDelivery) GetCreatedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return nil
+}
+
+func (x *WebhookDelivery) GetDeliveredAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.DeliveredAt
+	}
+	return nil
+}
+
+func (x *WebhookDelivery) GetHistory() []*WebhookAttempt {
+	if x != nil {
+		return x.History
+	}
+	return nil
+}
+
+type ListDeliveriesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	WebhookId     int64                  `protobuf:"varint,1,opt,name=webhook_id,json=webhookId,proto3" json:"webhook_id,omitempty"`
+	Status        string                 `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"`
+	Page          int32                  `protobuf:"varint,3,opt,name=page,proto3" json:"page,omitempty"`
+	PageSize      int32                  `protobuf:"varint,4,opt,name=page_size,json=pageSize,proto3" json:"page_size,omitempty"`
+	Sort          string                 `protobuf:"bytes,5,opt,name=sort,proto3" json:"sort,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListDeliveriesRequest) Reset() {
+	*x = ListDeliveriesRequest{}
+	mi := &file_news_proto_msgTypes[30]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListDeliveriesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListDeliveriesRequest) ProtoMessage() {}
+
+func (x *ListDeliveriesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_news_proto_msgTypes[30]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListDeliveriesRequest.ProtoReflect.Descriptor instead.
+func (*ListDeliveriesRequest) Descriptor() ([]byte, []int) {
+	return file_news_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *ListDeliveriesRequest) GetWebhookId() int64 {
+	if x != nil {
+		return x.WebhookId
+	}
+	return 0
+}
+
+func (x *ListDeliveriesRequest) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *ListDeliveriesRequest) GetPage() int32 {
+	if x != nil {
+		return x.Page
+	}
+	return 0
+}
+
+func (x *ListDeliveriesRequest) GetPageSize() int32 {
+	if x != nil {
+		return x.PageSize
+	}
+	return 0
+}
+
+func (x *ListDeliveriesRequest) GetSort() string {
+	if x != nil {
+		return x.Sort
+	}
+	return ""
+}
+
+type DeliveryList struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Deliveries    []*WebhookDelivery     `protobuf:"bytes,1,rep,name=deliveries,proto3" json:"deliveries,omitempty"`
+	Metadata      *Metadata              `protobuf:"bytes,2,opt,name=metadata,proto3" json:"metadata,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeliveryList) Reset() {
+	*x = DeliveryList{}
+	mi := &file_news_proto_msgTypes[31]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeliveryList) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeliveryList) ProtoMessage() {}
+
+func (x *DeliveryList) ProtoReflect() protoreflect.Message {
+	mi := &file_news_proto_msgTypes[31]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeliveryList.ProtoReflect.Descriptor instead.
+func (*DeliveryList) Descriptor() ([]byte, []int) {
+	return file_news_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *DeliveryList) GetDeliveries() []*WebhookDelivery {
+	if x != nil {
+		return x.Deliveries
+	}
+	return nil
+}
+
+func (x *DeliveryList) GetMetadata() *Metadata {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
+}
+
+type ReplayDeliveryRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	WebhookId     int64                  `protobuf:"varint,1,opt,name=webhook_id,json=webhookId,proto3" json:"webhook_id,omitempty"`
+	DeliveryId    int64                  `protobuf:"varint,2,opt,name=delivery_id,json=deliveryId,proto3" json:"delivery_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReplayDeliveryRequest) Reset() {
+	*x = ReplayDeliveryRequest{}
+	mi := &file_news_proto_msgTypes[32]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReplayDeliveryRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReplayDeliveryRequest) ProtoMessage() {}
+
+func (x *ReplayDeliveryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_news_proto_msgTypes[32]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReplayDeliveryRequest.ProtoReflect.Descriptor instead.
+func (*ReplayDeliveryRequest) Descriptor() ([]byte, []int) {
+	return file_news_proto_rawDescGZIP(), []int{32}
+}
+
+func (x *ReplayDeliveryRequest) GetWebhookId() int64 {
+	if x != nil {
+		return x.WebhookId
+	}
+	return 0
+}
+
+func (x *ReplayDeliveryRequest) GetDeliveryId() int64 {
+	if x != nil {
+		return x.DeliveryId
+	}
+	return 0
+}
+
+var File_news_proto protoreflect.FileDescriptor
+
+const file_news_proto_rawDesc = "" +
+	"\n" +
+	"\n" +
+	"news.proto\x12\x04data\x1a\x1fgoogle/protobuf/timestamp.proto\x1a\x1bgoogle/protobuf/empty.proto\x1a google/protobuf/field_mask.proto\"n\n" +
+	"\tRendition\x12\x10\n" +
+	"\x03url\x18\x01 \x01(\tR\x03url\x12\x14\n" +
+	"\x05width\x18\x02 \x01(\x05R\x05width\x12\x16\n" +
+	"\x06height\x18\x03 \x01(\x05R\x06height\x12!\n" +
+	"\fcontent_type\x18\x04 \x01(\tR\vcontentType\"\xc5\x01\n" +
+	"\x05Media\x12\x10\n" +
+	"\x03url\x18\x01 \x01(\tR\x03url\x12\x19\n" +
+	"\balt_text\x18\x02 \x01(\tR\aaltText\x12\x18\n" +
+	"\acaption\x18\x03 \x01(\tR\acaption\x12\x16\n" +
+	"\x06credit\x18\x04 \x01(\tR\x06credit\x12\x14\n" +
+	"\x05width\x18\x05 \x01(\x05R\x05width\x12\x16\n" +
+	"\x06height\x18\x06 \x01(\x05R\x06height\x12/\n" +
+	"\n" +
+	"renditions\x18\a \x03(\v2\x0f.data.RenditionR\n" +
+	"renditions\"\xcd\x04\n" +
+	"\x04News\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x129\n" +
+	"\n" +
+	"created_at\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
+	"\n" +
+	"updated_at\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\x12\x14\n" +
+	"\x05title\x18\x04 \x01(\tR\x05title\x12\x18\n" +
+	"\acontent\x18\x05 \x01(\tR\acontent\x12\x1e\n" +
+	"\n" +
+	"categories\x18\x06 \x03(\tR\n" +
+	"categories\x12\x16\n" +
+	"\x06status\x18\a \x01(\tR\x06status\x12\x1d\n" +
+	"\n" +
+	"image_urls\x18\b \x03(\tR\timageUrls\x12\x16\n" +
+	"\x06author\x18\t \x01(\tR\x06author\x12\x18\n" +
+	"\aversion\x18\n" +
+	" \x01(\x05R\aversion\x12=\n" +
+	"\fpublished_at\x18\v \x01(\v2\x1a.google.protobuf.TimestampR\vpublishedAt\x12%\n" +
+	"\x0econtent_format\x18\f \x01(\tR\rcontentFormat\x12!\n" +
+	"\fcontent_html\x18\r \x01(\tR\vcontentHtml\x12\x1d\n" +
+	"\n" +
+	"word_count\x18\x0e \x01(\x05R\twordCount\x12!\n" +
+	"\freading_time\x18\x0f \x01(\x05R\vreadingTime\x12\x18\n" +
+	"\aexcerpt\x18\x10 \x01(\tR\aexcerpt\x12!\n" +
+	"\x05media\x18\x11 \x03(\v2\v.data.MediaR\x05media\"\xab\x01\n" +
+	"\bMetadata\x12!\n" +
+	"\fcurrent_page\x18\x01 \x01(\x05R\vcurrentPage\x12\x1b\n" +
+	"\tpage_size\x18\x02 \x01(\x05R\bpageSize\x12\x1d\n" +
+	"\n" +
+	"first_page\x18\x03 \x01(\x05R\tfirstPage\x12\x1b\n" +
+	"\tlast_page\x18\x04 \x01(\x05R\blastPage\x12#\n" +
+	"\rtotal_records\x18\x05 \x01(\x05R\ftotalRecords\"\xc0\x02\n" +
+	"\rGetAllRequest\x12\x14\n" +
+	"\x05title\x18\x01 \x01(\tR\x05title\x12\x1e\n" +
+	"\n" +
 	"categories\x18\x02 \x03(\tR\n" +
 	"categories\x12\x16\n" +
 	"\x06status\x18\x03 \x01(\tR\x06status\x12\x12\n" +
 	"\x04page\x18\x04 \x01(\x05R\x04page\x12\x1b\n" +
 	"\tpage_size\x18\x05 \x01(\x05R\bpageSize\x12\x12\n" +
-	"\x04sort\x18\x06 \x01(\tR\x04sort\"V\n" +
+	"\x04sort\x18\x06 \x01(\tR\x04sort\x12\x16\n" +
+	"\x06author\x18\a \x01(\tR\x06author\x120\n" +
+	"\x14include_content_html\x18\b \x01(\bR\x12includeContentHtml\x12(\n" +
+	"\x10min_reading_time\x18\t \x01(\x05R\x0eminReadingTime\x12(\n" +
+	"\x10max_reading_time\x18\n" +
+	" \x01(\x05R\x0emaxReadingTime\"V\n" +
 	"\bNewsList\x12\x1e\n" +
 	"\x04news\x18\x01 \x03(\v2\n" +
 	".data.NewsR\x04news\x12*\n" +
-	"\bmetadata\x18\x02 \x01(\v2\x0e.data.MetadataR\bmetadata\"\x18\n" +
+	"\bmetadata\x18\x02 \x01(\v2\x0e.data.MetadataR\bmetadata\"J\n" +
 	"\x06NewsId\x12\x0e\n" +
-	"\x02id\x18\x01 \x01(\x03R\x02id\"\xb2\x01\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x120\n" +
+	"\x14include_content_html\x18\x02 \x01(\bR\x12includeContentHtml\"\xfc\x01\n" +
 	"\x11CreateNewsRequest\x12\x14\n" +
 	"\x05title\x18\x01 \x01(\tR\x05title\x12\x18\n" +
 	"\acontent\x18\x02 \x01(\tR\acontent\x12\x1e\n" +
@@ -634,7 +2632,9 @@ const file_news_proto_rawDesc = "" +
 	"\x06status\x18\x04 \x01(\tR\x06status\x12\x1d\n" +
 	"\n" +
 	"image_urls\x18\x05 \x03(\tR\timageUrls\x12\x16\n" +
-	"\x06author\x18\x06 \x01(\tR\x06author\"\xad\x02\n" +
+	"\x06author\x18\x06 \x01(\tR\x06author\x12%\n" +
+	"\x0econtent_format\x18\a \x01(\tR\rcontentFormat\x12!\n" +
+	"\x05media\x18\b \x03(\v2\v.data.MediaR\x05media\"\xcc\x03\n" +
 	"\x11UpdateNewsRequest\x12\x0e\n" +
 	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x19\n" +
 	"\x05title\x18\x02 \x01(\tH\x00R\x05title\x88\x01\x01\x12\x1d\n" +
@@ -646,14 +2646,178 @@ const file_news_proto_rawDesc = "" +
 	"\n" +
 	"image_urls\x18\x06 \x03(\tR\timageUrls\x12\x1b\n" +
 	"\x06author\x18\a \x01(\tH\x03R\x06author\x88\x01\x01\x12\x1d\n" +
-	"\aversion\x18\b \x01(\x05H\x04R\aversion\x88\x01\x01B\b\n" +
+	"\aversion\x18\b \x01(\x05H\x04R\aversion\x88\x01\x01\x12*\n" +
+	"\x0econtent_format\x18\t \x01(\tH\x05R\rcontentFormat\x88\x01\x01\x12!\n" +
+	"\x05media\x18\n" +
+	" \x03(\v2\v.data.MediaR\x05media\x12;\n" +
+	"\vupdate_mask\x18\v \x01(\v2\x1a.google.protobuf.FieldMaskR\n" +
+	"updateMaskB\b\n" +
 	"\x06_titleB\n" +
 	"\n" +
 	"\b_contentB\t\n" +
 	"\a_statusB\t\n" +
 	"\a_authorB\n" +
 	"\n" +
-	"\b_version2\xa1\x02\n" +
+	"\b_versionB\x11\n" +
+	"\x0f_content_format\"G\n" +
+	"\x16BatchCreateNewsRequest\x12-\n" +
+	"\x05items\x18\x01 \x03(\v2\x17.data.CreateNewsRequestR\x05items\"9\n" +
+	"\x17BatchCreateNewsResponse\x12\x1e\n" +
+	"\x04news\x18\x01 \x03(\v2\n" +
+	".data.NewsR\x04news\"Y\n" +
+	"\x13BatchGetNewsRequest\x12\x10\n" +
+	"\x03ids\x18\x01 \x03(\x03R\x03ids\x120\n" +
+	"\x14include_content_html\x18\x02 \x01(\bR\x12includeContentHtml\"W\n" +
+	"\x14BatchGetNewsResponse\x12\x1e\n" +
+	"\x04news\x18\x01 \x03(\v2\n" +
+	".data.NewsR\x04news\x12\x1f\n" +
+	"\vmissing_ids\x18\x02 \x03(\x03R\n" +
+	"missingIds\"\xc6\x01\n" +
+	"\n" +
+	"NewsFilter\x12\x14\n" +
+	"\x05title\x18\x01 \x01(\tR\x05title\x12\x1e\n" +
+	"\n" +
+	"categories\x18\x02 \x03(\tR\n" +
+	"categories\x12\x16\n" +
+	"\x06status\x18\x03 \x01(\tR\x06status\x12\x16\n" +
+	"\x06author\x18\x04 \x01(\tR\x06author\x12(\n" +
+	"\x10min_reading_time\x18\x05 \x01(\x05R\x0eminReadingTime\x12(\n" +
+	"\x10max_reading_time\x18\x06 \x01(\x05R\x0emaxReadingTime\"\xdb\x01\n" +
+	"\x15BulkUpdateNewsRequest\x12\x16\n" +
+	"\x06action\x18\x01 \x01(\tR\x06action\x12\x10\n" +
+	"\x03ids\x18\x02 \x03(\x03R\x03ids\x12(\n" +
+	"\x06filter\x18\x03 \x01(\v2\x10.data.NewsFilterR\x06filter\x12\x16\n" +
+	"\x06status\x18\x04 \x01(\tR\x06status\x12\x1a\n" +
+	"\bcategory\x18\x05 \x01(\tR\bcategory\x12!\n" +
+	"\fnew_category\x18\x06 \x01(\tR\vnewCategory\x12\x17\n" +
+	"\adry_run\x18\a \x01(\bR\x06dryRun\"\xe6\x02\n" +
+	"\aBulkJob\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x129\n" +
+	"\n" +
+	"created_at\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
+	"\n" +
+	"updated_at\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\x12\x16\n" +
+	"\x06action\x18\x04 \x01(\tR\x06action\x12\x16\n" +
+	"\x06status\x18\x05 \x01(\tR\x06status\x12\x18\n" +
+	"\amatched\x18\x06 \x01(\x03R\amatched\x12\x1c\n" +
+	"\tprocessed\x18\a \x01(\x03R\tprocessed\x12\x1a\n" +
+	"\baffected\x18\b \x01(\x03R\baffected\x12\x14\n" +
+	"\x05error\x18\t \x01(\tR\x05error\x12;\n" +
+	"\vfinished_at\x18\n" +
+	" \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"finishedAt\"o\n" +
+	"\x16BulkUpdateNewsResponse\x12\x18\n" +
+	"\amatched\x18\x01 \x01(\x03R\amatched\x12\x1a\n" +
+	"\baffected\x18\x02 \x01(\x03R\baffected\x12\x1f\n" +
+	"\x03job\x18\x03 \x01(\v2\r.data.BulkJobR\x03job\"\x1b\n" +
+	"\tBulkJobId\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\"\xa2\x02\n" +
+	"\x10WatchNewsRequest\x12\x14\n" +
+	"\x05title\x18\x01 \x01(\tR\x05title\x12\x1e\n" +
+	"\n" +
+	"categories\x18\x02 \x03(\tR\n" +
+	"categories\x12\x16\n" +
+	"\x06status\x18\x03 \x01(\tR\x06status\x12\x16\n" +
+	"\x06author\x18\x04 \x01(\tR\x06author\x120\n" +
+	"\x14include_content_html\x18\x05 \x01(\bR\x12includeContentHtml\x12(\n" +
+	"\x10min_reading_time\x18\x06 \x01(\x05R\x0eminReadingTime\x12(\n" +
+	"\x10max_reading_time\x18\a \x01(\x05R\x0emaxReadingTime\x12\"\n" +
+	"\rlast_event_id\x18\b \x01(\x03R\vlastEventId\"\x8c\x01\n" +
+	"\tNewsEvent\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x12\n" +
+	"\x04type\x18\x02 \x01(\tR\x04type\x12\x1e\n" +
+	"\x04news\x18\x03 \x01(\v2\n" +
+	".data.NewsR\x04news\x12;\n" +
+	"\voccurred_at\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"occurredAt\"P\n" +
+	"\x0fUploadMediaInfo\x12\x1a\n" +
+	"\bfilename\x18\x01 \x01(\tR\bfilename\x12!\n" +
+	"\fcontent_type\x18\x02 \x01(\tR\vcontentType\"a\n" +
+	"\x12UploadMediaRequest\x12+\n" +
+	"\x04info\x18\x01 \x01(\v2\x15.data.UploadMediaInfoH\x00R\x04info\x12\x16\n" +
+	"\x05chunk\x18\x02 \x01(\fH\x00R\x05chunkB\x06\n" +
+	"\x04data\"r\n" +
+	"\x13UploadMediaResponse\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x10\n" +
+	"\x03url\x18\x02 \x01(\tR\x03url\x12!\n" +
+	"\fcontent_type\x18\x03 \x01(\tR\vcontentType\x12\x12\n" +
+	"\x04size\x18\x04 \x01(\x03R\x04size\"\x8c\x02\n" +
+	"\aWebhook\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x129\n" +
+	"\n" +
+	"created_at\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
+	"\n" +
+	"updated_at\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\x12\x10\n" +
+	"\x03url\x18\x04 \x01(\tR\x03url\x12\x16\n" +
+	"\x06secret\x18\x05 \x01(\tR\x06secret\x12\x1f\n" +
+	"\vevent_types\x18\x06 \x03(\tR\n" +
+	"eventTypes\x12\x16\n" +
+	"\x06active\x18\a \x01(\bR\x06active\x12\x18\n" +
+	"\aversion\x18\b \x01(\x05R\aversion\"\x89\x01\n" +
+	"\x14CreateWebhookRequest\x12\x10\n" +
+	"\x03url\x18\x01 \x01(\tR\x03url\x12\x16\n" +
+	"\x06secret\x18\x02 \x01(\tR\x06secret\x12\x1f\n" +
+	"\vevent_types\x18\x03 \x03(\tR\n" +
+	"eventTypes\x12\x1b\n" +
+	"\x06active\x18\x04 \x01(\bH\x00R\x06active\x88\x01\x01B\t\n" +
+	"\a_active\"\xe1\x01\n" +
+	"\x14UpdateWebhookRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x15\n" +
+	"\x03url\x18\x02 \x01(\tH\x00R\x03url\x88\x01\x01\x12\x1b\n" +
+	"\x06secret\x18\x03 \x01(\tH\x01R\x06secret\x88\x01\x01\x12\x1f\n" +
+	"\vevent_types\x18\x04 \x03(\tR\n" +
+	"eventTypes\x12\x1b\n" +
+	"\x06active\x18\x05 \x01(\bH\x02R\x06active\x88\x01\x01\x12\x1d\n" +
+	"\aversion\x18\x06 \x01(\x05H\x03R\aversion\x88\x01\x01B\x06\n" +
+	"\x04_urlB\t\n" +
+	"\a_secretB\t\n" +
+	"\a_activeB\n" +
+	"\n" +
+	"\b_version\"\x1b\n" +
+	"\tWebhookId\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\"8\n" +
+	"\vWebhookList\x12)\n" +
+	"\bwebhooks\x18\x01 \x03(\v2\r.data.WebhookR\bwebhooks\"\xaf\x01\n" +
+	"\x0eWebhookAttempt\x12=\n" +
+	"\fattempted_at\x18\x01 \x01(\v2\x1a.google.protobuf.TimestampR\vattemptedAt\x12'\n" +
+	"\x0fresponse_status\x18\x02 \x01(\x05R\x0eresponseStatus\x12\x14\n" +
+	"\x05error\x18\x03 \x01(\tR\x05error\x12\x1f\n" +
+	"\vduration_ms\x18\x04 \x01(\x05R\n" +
+	"durationMs\"\xba\x03\n" +
+	"\x0fWebhookDelivery\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x1d\n" +
+	"\n" +
+	"webhook_id\x18\x02 \x01(\x03R\twebhookId\x12\x1d\n" +
+	"\n" +
+	"event_type\x18\x03 \x01(\tR\teventType\x12\x18\n" +
+	"\apayload\x18\x04 \x01(\tR\apayload\x12\x16\n" +
+	"\x06status\x18\x05 \x01(\tR\x06status\x12\x1a\n" +
+	"\battempts\x18\x06 \x01(\x05R\battempts\x12B\n" +
+	"\x0fnext_attempt_at\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\rnextAttemptAt\x12\x1d\n" +
+	"\n" +
+	"last_error\x18\b \x01(\tR\tlastError\x129\n" +
+	"\n" +
+	"created_at\x18\t \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x12=\n" +
+	"\fdelivered_at\x18\n" +
+	" \x01(\v2\x1a.google.protobuf.TimestampR\vdeliveredAt\x12.\n" +
+	"\ahistory\x18\v \x03(\v2\x14.data.WebhookAttemptR\ahistory\"\x93\x01\n" +
+	"\x15ListDeliveriesRequest\x12\x1d\n" +
+	"\n" +
+	"webhook_id\x18\x01 \x01(\x03R\twebhookId\x12\x16\n" +
+	"\x06status\x18\x02 \x01(\tR\x06status\x12\x12\n" +
+	"\x04page\x18\x03 \x01(\x05R\x04page\x12\x1b\n" +
+	"\tpage_size\x18\x04 \x01(\x05R\bpageSize\x12\x12\n" +
+	"\x04sort\x18\x05 \x01(\tR\x04sort\"q\n" +
+	"\fDeliveryList\x125\n" +
+	"\n" +
+	"deliveries\x18\x01 \x03(\v2\x15.data.WebhookDeliveryR\n" +
+	"deliveries\x12*\n" +
+	"\bmetadata\x18\x02 \x01(\v2\x0e.data.MetadataR\bmetadata\"W\n" +
+	"\x15ReplayDeliveryRequest\x12\x1d\n" +
+	"\n" +
+	"webhook_id\x18\x01 \x01(\x03R\twebhookId\x12\x1f\n" +
+	"\vdelivery_id\x18\x02 \x01(\x03R\n" +
+	"deliveryId2\xeb\x04\n" +
 	"\vNewsService\x128\n" +
 	"\x11CreateNewsHandler\x12\x17.data.CreateNewsRequest\x1a\n" +
 	".data.News\x12+\n" +
@@ -662,7 +2826,24 @@ const file_news_proto_rawDesc = "" +
 	"\x11UpdateNewsHandler\x12\x17.data.UpdateNewsRequest\x1a\n" +
 	".data.News\x129\n" +
 	"\x11DeleteNewsHandler\x12\f.data.NewsId\x1a\x16.google.protobuf.Empty\x126\n" +
-	"\x0fListNewsHandler\x12\x13.data.GetAllRequest\x1a\x0e.data.NewsListB\x1fZ\x1dnews-service/proto;news_protob\x06proto3"
+	"\x0fListNewsHandler\x12\x13.data.GetAllRequest\x1a\x0e.data.NewsList\x126\n" +
+	"\tWatchNews\x12\x16.data.WatchNewsRequest\x1a\x0f.data.NewsEvent0\x01\x12N\n" +
+	"\x0fBatchCreateNews\x12\x1c.data.BatchCreateNewsRequest\x1a\x1d.data.BatchCreateNewsResponse\x12E\n" +
+	"\fBatchGetNews\x12\x19.data.BatchGetNewsRequest\x1a\x1a.data.BatchGetNewsResponse\x12K\n" +
+	"\x0eBulkUpdateNews\x12\x1b.data.BulkUpdateNewsRequest\x1a\x1c.data.BulkUpdateNewsResponse\x12,\n" +
+	"\n" +
+	"GetBulkJob\x12\x0f.data.BulkJobId\x1a\r.data.BulkJob2T\n" +
+	"\fMediaService\x12D\n" +
+	"\vUploadMedia\x12\x18.data.UploadMediaRequest\x1a\x19.data.UploadMediaResponse(\x012\xb4\x03\n" +
+	"\x0eWebhookService\x12:\n" +
+	"\rCreateWebhook\x12\x1a.data.CreateWebhookRequest\x1a\r.data.Webhook\x12,\n" +
+	"\n" +
+	"GetWebhook\x12\x0f.data.WebhookId\x1a\r.data.Webhook\x12:\n" +
+	"\rUpdateWebhook\x12\x1a.data.UpdateWebhookRequest\x1a\r.data.Webhook\x128\n" +
+	"\rDeleteWebhook\x12\x0f.data.WebhookId\x1a\x16.google.protobuf.Empty\x129\n" +
+	"\fListWebhooks\x12\x16.google.protobuf.Empty\x1a\x11.data.WebhookList\x12A\n" +
+	"\x0eListDeliveries\x12\x1b.data.ListDeliveriesRequest\x1a\x12.data.DeliveryList\x12D\n" +
+	"\x0eReplayDelivery\x12\x1b.data.ReplayDeliveryRequest\x1a\x15.data.WebhookDeliveryB\x1fZ\x1dnews-service/proto;news_protob\x06proto3"
 
 var (
 	file_news_proto_rawDescOnce sync.Once
@@ -676,38 +2857,118 @@ func file_news_proto_rawDescGZIP() []byte {
 	return file_news_proto_rawDescData
 }
 
-var file_news_proto_msgTypes = make([]protoimpl.MessageInfo, 7)
+var file_news_proto_msgTypes = make([]protoimpl.MessageInfo, 33)
 var file_news_proto_goTypes = []any{
-	(*News)(nil),                  // 0: data.News
-	(*Metadata)(nil),              // 1: data.Metadata
-	(*GetAllRequest)(nil),         // 2: data.GetAllRequest
-	(*NewsList)(nil),              // 3: data.NewsList
-	(*NewsId)(nil),                // 4: data.NewsId
-	(*CreateNewsRequest)(nil),     // 5: data.CreateNewsRequest
-	(*UpdateNewsRequest)(nil),     // 6: data.UpdateNewsRequest
-	(*timestamppb.Timestamp)(nil), // 7: google.protobuf.Timestamp
-	(*emptypb.Empty)(nil),         // 8: google.protobuf.Empty
+	(*Rendition)(nil),               // 0: data.Rendition
+	(*Media)(nil),                   // 1: data.Media
+	(*News)(nil),                    // 2: data.News
+	(*Metadata)(nil),                // 3: data.Metadata
+	(*GetAllRequest)(nil),           // 4: data.GetAllRequest
+	(*NewsList)(nil),                // 5: data.NewsList
+	(*NewsId)(nil),                  // 6: data.NewsId
+	(*CreateNewsRequest)(nil),       // 7: data.CreateNewsRequest
+	(*UpdateNewsRequest)(nil),       // 8: data.UpdateNewsRequest
+	(*BatchCreateNewsRequest)(nil),  // 9: data.BatchCreateNewsRequest
+	(*BatchCreateNewsResponse)(nil), // 10: data.BatchCreateNewsResponse
+	(*BatchGetNewsRequest)(nil),     // 11: data.BatchGetNewsRequest
+	(*BatchGetNewsResponse)(nil),    // 12: data.BatchGetNewsResponse
+	(*NewsFilter)(nil),              // 13: data.NewsFilter
+	(*BulkUpdateNewsRequest)(nil),   // 14: data.BulkUpdateNewsRequest
+	(*BulkJob)(nil),                 // 15: data.BulkJob
+	(*BulkUpdateNewsResponse)(nil),  // 16: data.BulkUpdateNewsResponse
+	(*BulkJobId)(nil),               // 17: data.BulkJobId
+	(*WatchNewsRequest)(nil),        // 18: data.WatchNewsRequest
+	(*NewsEvent)(nil),               // 19: data.NewsEvent
+	(*UploadMediaInfo)(nil),         // 20: data.UploadMediaInfo
+	(*UploadMediaRequest)(nil),      // 21: data.UploadMediaRequest
+	(*UploadMediaResponse)(nil),     // 22: data.UploadMediaResponse
+	(*Webhook)(nil),                 // 23: data.Webhook
+	(*CreateWebhookRequest)(nil),    // 24: data.CreateWebhookRequest
+	(*UpdateWebhookRequest)(nil),    // 25: data.UpdateWebhookRequest
+	(*WebhookId)(nil),               // 26: data.WebhookId
+	(*WebhookList)(nil),             // 27: data.WebhookList
+	(*WebhookAttempt)(nil),          // 28: data.WebhookAttempt
+	(*WebhookDelivery)(nil),         // 29: data.WebhookDelivery
+	(*ListDeliveriesRequest)(nil),   // 30: data.ListDeliveriesRequest
+	(*DeliveryList)(nil),            // 31: data.DeliveryList
+	(*ReplayDeliveryRequest)(nil),   // 32: data.ReplayDeliveryRequest
+	(*timestamppb.Timestamp)(nil),   // 33: google.protobuf.Timestamp
+	(*fieldmaskpb.FieldMask)(nil),   // 34: google.protobuf.FieldMask
+	(*emptypb.Empty)(nil),           // 35: google.protobuf.Empty
 }
 var file_news_proto_depIdxs = []int32{
-	7, // 0: data.News.created_at:type_name -> google.protobuf.Timestamp
-	7, // 1: data.News.updated_at:type_name -> google.protobuf.Timestamp
-	0, // 2: data.NewsList.news:type_name -> data.News
-	1, // 3: data.NewsList.metadata:type_name -> data.Metadata
-	5, // 4: data.NewsService.CreateNewsHandler:input_type -> data.CreateNewsRequest
-	4, // 5: data.NewsService.ShowNewsHandler:input_type -> data.NewsId
-	6, // 6: data.NewsService.UpdateNewsHandler:input_type -> data.UpdateNewsRequest
-	4, // 7: data.NewsService.DeleteNewsHandler:input_type -> data.NewsId
-	2, // 8: data.NewsService.ListNewsHandler:input_type -> data.GetAllRequest
-	0, // 9: data.NewsService.CreateNewsHandler:output_type -> data.News
-	0, // 10: data.NewsService.ShowNewsHandler:output_type -> data.News
-	0, // 11: data.NewsService.UpdateNewsHandler:output_type -> data.News
-	8, // 12: data.NewsService.DeleteNewsHandler:output_type -> google.protobuf.Empty
-	3, // 13: data.NewsService.ListNewsHandler:output_type -> data.NewsList
-	9, // [9:14] is the sub-list for method output_type
-	4, // [4:9] is the sub-list for method input_type
-	4, // [4:4] is the sub-list for extension type_name
-	4, // [4:4] is the sub-list for extension extendee
-	0, // [0:4] is the sub-list for field type_name
+	0,  // 0: data.Media.renditions:type_name -> data.Rendition
+	33, // 1: data.News.created_at:type_name -> google.protobuf.Timestamp
+	33, // 2: data.News.updated_at:type_name -> google.protobuf.Timestamp
+	33, // 3: data.News.published_at:type_name -> google.protobuf.Timestamp
+	1,  // 4: data.News.media:type_name -> data.Media
+	2,  // 5: data.NewsList.news:type_name -> data.News
+	3,  // 6: data.NewsList.metadata:type_name -> data.Metadata
+	1,  // 7: data.CreateNewsRequest.media:type_name -> data.Media
+	1,  // 8: data.UpdateNewsRequest.media:type_name -> data.Media
+	34, // 9: data.UpdateNewsRequest.update_mask:type_name -> google.protobuf.FieldMask
+	7,  // 10: data.BatchCreateNewsRequest.items:type_name -> data.CreateNewsRequest
+	2,  // 11: data.BatchCreateNewsResponse.news:type_name -> data.News
+	2,  // 12: data.BatchGetNewsResponse.news:type_name -> data.News
+	13, // 13: data.BulkUpdateNewsRequest.filter:type_name -> data.NewsFilter
+	33, // 14: data.BulkJob.created_at:type_name -> google.protobuf.Timestamp
+	33, // 15: data.BulkJob.updated_at:type_name -> google.protobuf.Timestamp
+	33, // 16: data.BulkJob.finished_at:type_name -> google.protobuf.Timestamp
+	15, // 17: data.BulkUpdateNewsResponse.job:type_name -> data.BulkJob
+	2,  // 18: data.NewsEvent.news:type_name -> data.News
+	33, // 19: data.NewsEvent.occurred_at:type_name -> google.protobuf.Timestamp
+	20, // 20: data.UploadMediaRequest.info:type_name -> data.UploadMediaInfo
+	33, // 21: data.Webhook.created_at:type_name -> google.protobuf.Timestamp
+	33, // 22: data.Webhook.updated_at:type_name -> google.protobuf.Timestamp
+	23, // 23: data.WebhookList.webhooks:type_name -> data.Webhook
+	33, // 24: data.WebhookAttempt.attempted_at:type_name -> google.protobuf.Timestamp
+	33, // 25: data.WebhookDelivery.next_attempt_at:type_name -> google.protobuf.Timestamp
+	33, // 26: data.WebhookDelivery.created_at:type_name -> google.protobuf.Timestamp
+	33, // 27: data.WebhookDelivery.delivered_at:type_name -> google.protobuf.Timestamp
+	28, // 28: data.WebhookDelivery.history:type_name -> data.WebhookAttempt
+	29, // 29: data.DeliveryList.deliveries:type_name -> data.WebhookDelivery
+	3,  // 30: data.DeliveryList.metadata:type_name -> data.Metadata
+	7,  // 31: data.NewsService.CreateNewsHandler:input_type -> data.CreateNewsRequest
+	6,  // 32: data.NewsService.ShowNewsHandler:input_type -> data.NewsId
+	8,  // 33: data.NewsService.UpdateNewsHandler:input_type -> data.UpdateNewsRequest
+	6,  // 34: data.NewsService.DeleteNewsHandler:input_type -> data.NewsId
+	4,  // 35: data.NewsService.ListNewsHandler:input_type -> data.GetAllRequest
+	18, // 36: data.NewsService.WatchNews:input_type -> data.WatchNewsRequest
+	9,  // 37: data.NewsService.BatchCreateNews:input_type -> data.BatchCreateNewsRequest
+	11, // 38: data.NewsService.BatchGetNews:input_type -> data.BatchGetNewsRequest
+	14, // 39: data.NewsService.BulkUpdateNews:input_type -> data.BulkUpdateNewsRequest
+	17, // 40: data.NewsService.GetBulkJob:input_type -> data.BulkJobId
+	21, // 41: data.MediaService.UploadMedia:input_type -> data.UploadMediaRequest
+	24, // 42: data.WebhookService.CreateWebhook:input_type -> data.CreateWebhookRequest
+	26, // 43: data.WebhookService.GetWebhook:input_type -> data.WebhookId
+	25, // 44: data.WebhookService.UpdateWebhook:input_type -> data.UpdateWebhookRequest
+	26, // 45: data.WebhookService.DeleteWebhook:input_type -> data.WebhookId
+	35, // 46: data.WebhookService.ListWebhooks:input_type -> google.protobuf.Empty
+	30, // 47: data.WebhookService.ListDeliveries:input_type -> data.ListDeliveriesRequest
+	32, // 48: data.WebhookService.ReplayDelivery:input_type -> data.ReplayDeliveryRequest
+	2,  // 49: data.NewsService.CreateNewsHandler:output_type -> data.News
+	2,  // 50: data.NewsService.ShowNewsHandler:output_type -> data.News
+	2,  // 51: data.NewsService.UpdateNewsHandler:output_type -> data.News
+	35, // 52: data.NewsService.DeleteNewsHandler:output_type -> google.protobuf.Empty
+	5,  // 53: data.NewsService.ListNewsHandler:output_type -> data.NewsList
+	19, // 54: data.NewsService.WatchNews:output_type -> data.NewsEvent
+	10, // 55: data.NewsService.BatchCreateNews:output_type -> data.BatchCreateNewsResponse
+	12, // 56: data.NewsService.BatchGetNews:output_type -> data.BatchGetNewsResponse
+	16, // 57: data.NewsService.BulkUpdateNews:output_type -> data.BulkUpdateNewsResponse
+	15, // 58: data.NewsService.GetBulkJob:output_type -> data.BulkJob
+	22, // 59: data.MediaService.UploadMedia:output_type -> data.UploadMediaResponse
+	23, // 60: data.WebhookService.CreateWebhook:output_type -> data.Webhook
+	23, // 61: data.WebhookService.GetWebhook:output_type -> data.Webhook
+	23, // 62: data.WebhookService.UpdateWebhook:output_type -> data.Webhook
+	35, // 63: data.WebhookService.DeleteWebhook:output_type -> google.protobuf.Empty
+	27, // 64: data.WebhookService.ListWebhooks:output_type -> data.WebhookList
+	31, // 65: data.WebhookService.ListDeliveries:output_type -> data.DeliveryList
+	29, // 66: data.WebhookService.ReplayDelivery:output_type -> data.WebhookDelivery
+	49, // [49:67] is the sub-list for method output_type
+	31, // [31:49] is the sub-list for method input_type
+	31, // [31:31] is the sub-list for extension type_name
+	31, // [31:31] is the sub-list for extension extendee
+	0,  // [0:31] is the sub-list for field type_name
 }
 
 func init() { file_news_proto_init() }
@@ -715,16 +2976,22 @@ func file_news_proto_init() {
 	if File_news_proto != nil {
 		return
 	}
-	file_news_proto_msgTypes[6].OneofWrappers = []any{}
+	file_news_proto_msgTypes[8].OneofWrappers = []any{}
+	file_news_proto_msgTypes[21].OneofWrappers = []any{
+		(*UploadMediaRequest_Info)(nil),
+		(*UploadMediaRequest_Chunk)(nil),
+	}
+	file_news_proto_msgTypes[24].OneofWrappers = []any{}
+	file_news_proto_msgTypes[25].OneofWrappers = []any{}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_news_proto_rawDesc), len(file_news_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   7,
+			NumMessages:   33,
 			NumExtensions: 0,
-			NumServices:   1,
+			NumServices:   3,
 		},
 		GoTypes:           file_news_proto_goTypes,
 		DependencyIndexes: file_news_proto_depIdxs,